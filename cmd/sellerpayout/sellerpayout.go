@@ -1,9 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/TestardR/seller-payout/config"
+	"github.com/TestardR/seller-payout/internal/bankfile"
 	"github.com/TestardR/seller-payout/internal/handler/cron"
 	"github.com/TestardR/seller-payout/internal/handler/http"
+	"github.com/TestardR/seller-payout/internal/outbox"
+	"github.com/TestardR/seller-payout/internal/webhook"
 	"github.com/TestardR/seller-payout/pkg/currency"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/logger"
@@ -22,26 +33,111 @@ func main() {
 		log.Fatal(err)
 	}
 
-	db, err := db.New(db.Config{
-		User:     c.PGUser,
-		Name:     c.PGName,
-		Password: c.PGPassword,
-		Host:     c.PGHost,
-	})
+	database, err := newDB(c)
 	if err != nil {
-		log.Fatal("failed to create postgres: %w", err)
+		log.Fatal(err)
 	}
 
-	if err = db.RunMigrations(migrationDir); err != nil {
-		log.Fatal("failed to run postgres migration: %w", err)
-	}
+	hooks := webhook.New(db.NewUnitOfWork(database))
 
-	cron.Run(log, db, currency.New(), c.CronIntervals)
+	pub, err := outbox.NewPublisher(c.OutboxPublisher, c.OutboxURL, log)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	server := http.NewServer(c.Env, log, db)
+	// outbox messages are sent to the webhook subscriptions as well.
+	relay := outbox.NewRelay(database, outbox.Publishers{hooks, pub})
 
-	err = server.Run(":" + c.Port)
+	jobs, err := cron.Run(log, database, currency.New(), hooks, relay, c.Schedules)
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	router := http.NewServer(c.Env, log, database, bankfile.Originator{
+		Name:          c.OriginatorName,
+		IBAN:          c.OriginatorIBAN,
+		BIC:           c.OriginatorBIC,
+		RoutingNumber: c.OriginatorRoutingNumber,
+		BankName:      c.OriginatorBankName,
+		CompanyID:     c.OriginatorCompanyID,
+	}, cron.NewPayoutCreator(log, database, c.Schedules.PayoutWorkers, c.Schedules.PayoutTransactions, jobs), jobs)
+
+	server := &nethttp.Server{Addr: ":" + c.Port, Handler: router}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("shutting down")
+
+	// the requests in progress and the jobs are waited for until the same deadline.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.ShutdownTimeout)
+	defer cancel()
+
+	// the server stops accepting requests first, so that no manual run is refused while the requests in
+	// progress are waited for.
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error(err)
+	}
+
+	// the jobs, the manual runs included, start no payout transaction once stopped and wait for the ones in progress.
+	stopped := make(chan struct{})
+
+	go func() {
+		jobs.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		log.Error(fmt.Errorf("jobs not stopped: %w", shutdownCtx.Err()))
+	}
+
+	if err := database.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+// newDB returns the migrated database of the configured driver.
+func newDB(c config.Conf) (db.DB, error) {
+	if c.DBDriver == "memory" {
+		return db.NewMemory(), nil
+	}
+
+	d, err := db.New(db.Config{
+		DSN:              c.PGURL,
+		User:             c.PGUser,
+		Name:             c.PGName,
+		Password:         c.PGPassword,
+		Host:             c.PGHost,
+		Port:             c.PGPort,
+		SSLMode:          c.PGSSLMode,
+		SSLRootCert:      c.PGSSLRootCert,
+		SSLCert:          c.PGSSLCert,
+		SSLKey:           c.PGSSLKey,
+		ApplicationName:  c.PGApplicationName,
+		StatementTimeout: c.PGStatementTimeout,
+		MaxOpenConns:     c.PGMaxOpenConns,
+		MaxIdleConns:     c.PGMaxIdleConns,
+		ConnMaxLifetime:  c.PGConnMaxLifetime,
+		// the listings and the statements are read from the replicas.
+		ReplicaHosts:  c.PGReplicaHosts,
+		MaxReplicaLag: c.PGMaxReplicaLag,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres: %w", err)
+	}
+
+	if err = d.RunMigrations(migrationDir); err != nil {
+		return nil, fmt.Errorf("failed to run postgres migration: %w", err)
+	}
+
+	return d, nil
 }
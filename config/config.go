@@ -3,30 +3,100 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/kelseyhightower/envconfig"
 )
 
-var errParseEnv = errors.New("failed to parse environment variable")
+var (
+	errParseEnv       = errors.New("failed to parse environment variable")
+	errPostgresConfig = errors.New("PG_URL or PG_USER, PG_NAME, PG_PASSWORD and PG_HOST are required by the postgres driver")
+)
 
 // Conf represents the application configuration.
 type Conf struct {
 	// App config
 	Port string `required:"true"`
 	Env  string `required:"true" validate:"eq=debug|eq=release"`
-	CronIntervals
-	// Postgres config
-	PGUser     string `required:"true" split_words:"true"`
-	PGName     string `required:"true" split_words:"true"`
-	PGPassword string `required:"true" split_words:"true"`
-	PGHost     string `required:"true" split_words:"true"`
+	// ShutdownTimeout is how long the requests and the jobs in progress are waited for on SIGTERM,
+	// it should stay below the grace period of the orchestrator.
+	ShutdownTimeout time.Duration `default:"25s" split_words:"true"`
+	Schedules
+	BankOriginator
+	// Outbox config
+	OutboxPublisher string `default:"log" split_words:"true" validate:"eq=log|eq=http"`
+	OutboxURL       string `envconfig:"OUTBOX_URL"`
+	// DBDriver is the database used, memory is an empty in-memory database for local development
+	// which does not need the postgres config.
+	DBDriver string `default:"postgres" envconfig:"DB_DRIVER" validate:"eq=postgres|eq=memory"`
+	// Postgres config, required by the postgres driver unless PGURL is set.
+	PGUser     string `split_words:"true"`
+	PGName     string `split_words:"true"`
+	PGPassword string `split_words:"true"`
+	PGHost     string `split_words:"true"`
+	PGPort     int    `default:"5432" split_words:"true" validate:"min=1,max=65535"`
+	// PGSSLMode is the libpq sslmode, PGSSLRootCert the path of the CA certificates verify-ca and verify-full
+	// check the server against, PGSSLCert and PGSSLKey the paths of a client certificate and its key.
+	PGSSLMode     string `default:"disable" envconfig:"PG_SSL_MODE" validate:"eq=disable|eq=allow|eq=prefer|eq=require|eq=verify-ca|eq=verify-full"`
+	PGSSLRootCert string `envconfig:"PG_SSL_ROOT_CERT"`
+	PGSSLCert     string `envconfig:"PG_SSL_CERT"`
+	PGSSLKey      string `envconfig:"PG_SSL_KEY"`
+	// PGURL is a full connection string, key/value or URL, the connection fields above are then ignored.
+	PGURL string `envconfig:"PG_URL"`
+	// PGStatementTimeout aborts the statements running longer, migrations included, no timeout when zero.
+	PGStatementTimeout time.Duration `split_words:"true"`
+	PGApplicationName  string        `default:"sellerpayout" split_words:"true"`
+	// Connections pool of each of the postgres hosts.
+	PGMaxOpenConns    int           `default:"20" split_words:"true" validate:"min=0"`
+	PGMaxIdleConns    int           `default:"10" split_words:"true" validate:"min=0"`
+	PGConnMaxLifetime time.Duration `default:"30m" split_words:"true"`
+	// PGReplicaHosts are read replicas sharing the connection config of PGHost, comma separated, PGURL excluded.
+	// The listings and the statements are read from a replica lagging at most PGMaxReplicaLag.
+	PGReplicaHosts  []string      `split_words:"true"`
+	PGMaxReplicaLag time.Duration `default:"5s" split_words:"true"`
 }
 
-// CronIntervals represents the intervals from conjobs.
-type CronIntervals struct {
-	PayoutInterval   int `required:"true" split_words:"true"`
-	CurrencyInterval int `required:"true" split_words:"true"`
+// Schedules represents the schedules of the background jobs, as cron expressions.
+type Schedules struct {
+	PayoutSchedule   string        `default:"0 */4 * * *" split_words:"true"`
+	PayoutTimezone   string        `default:"UTC" split_words:"true"`
+	PayoutRunOnStart bool          `split_words:"true"`
+	PayoutJitter     time.Duration `split_words:"true"`
+	// a failed run is attempted again after a backoff doubled on every attempt,
+	// the runs are skipped for a cooldown after a number of consecutive failed runs.
+	PayoutRetryAttempts    int           `default:"3" split_words:"true" validate:"min=1"`
+	PayoutRetryBackoff     time.Duration `default:"1m" split_words:"true"`
+	PayoutBreakerThreshold int           `default:"5" split_words:"true" validate:"min=0"`
+	PayoutBreakerCooldown  time.Duration `default:"1h" split_words:"true"`
+	// sellers are processed by PayoutWorkers workers, running at most PayoutTransactions transactions
+	// at the same time, which should stay below the connections of the DB pool, PGMaxOpenConns.
+	PayoutWorkers      int `default:"8" split_words:"true" validate:"min=1"`
+	PayoutTransactions int `default:"4" split_words:"true" validate:"min=1"`
+
+	CurrencySchedule         string        `default:"0 */12 * * *" split_words:"true"`
+	CurrencyTimezone         string        `default:"UTC" split_words:"true"`
+	CurrencyRunOnStart       bool          `split_words:"true"`
+	CurrencyJitter           time.Duration `split_words:"true"`
+	CurrencyRetryAttempts    int           `default:"3" split_words:"true" validate:"min=1"`
+	CurrencyRetryBackoff     time.Duration `default:"30s" split_words:"true"`
+	CurrencyBreakerThreshold int           `default:"5" split_words:"true" validate:"min=0"`
+	CurrencyBreakerCooldown  time.Duration `default:"1h" split_words:"true"`
+
+	// WebhookInterval is in seconds, deliveries are retried with a backoff starting at 30 seconds.
+	WebhookInterval int `default:"10" split_words:"true"`
+	// OutboxInterval is in seconds.
+	OutboxInterval int `default:"5" split_words:"true"`
+}
+
+// BankOriginator represents our bank details, written in the bank files.
+type BankOriginator struct {
+	OriginatorName          string `split_words:"true"`
+	OriginatorIBAN          string `envconfig:"ORIGINATOR_IBAN"`
+	OriginatorBIC           string `envconfig:"ORIGINATOR_BIC"`
+	OriginatorRoutingNumber string `split_words:"true"`
+	OriginatorBankName      string `split_words:"true"`
+	OriginatorCompanyID     string `envconfig:"ORIGINATOR_COMPANY_ID"`
 }
 
 // New returns a new instance of Conf struct.
@@ -41,5 +111,9 @@ func New() (Conf, error) {
 		return Conf{}, fmt.Errorf("%w: %s", errParseEnv, err)
 	}
 
+	if c.DBDriver == "postgres" && c.PGURL == "" && (c.PGUser == "" || c.PGName == "" || c.PGPassword == "" || c.PGHost == "") {
+		return Conf{}, fmt.Errorf("%w: %s", errParseEnv, errPostgresConfig)
+	}
+
 	return c, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gotest.tools/assert"
@@ -26,6 +27,25 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, true, errors.Is(err, errParseEnv))
 	})
 
+	t.Run("should return an errParseEnv error because postgres config is missing", func(t *testing.T) {
+		t.Setenv("PORT", "v")
+		t.Setenv("ENV", "debug")
+		t.Setenv("PG_HOST", "postgres")
+
+		_, err := New()
+		assert.Equal(t, true, errors.Is(err, errParseEnv))
+	})
+
+	t.Run("should be ok without postgres config on memory driver", func(t *testing.T) {
+		t.Setenv("PORT", "v")
+		t.Setenv("ENV", "debug")
+		t.Setenv("DB_DRIVER", "memory")
+
+		c, err := New()
+		require.NoError(t, err)
+		assert.Equal(t, "memory", c.DBDriver)
+	})
+
 	t.Run("should be ok", func(t *testing.T) {
 		t.Setenv("PORT", "v")
 		t.Setenv("ENV", "debug")
@@ -39,4 +59,64 @@ func TestConfig(t *testing.T) {
 		_, err := New()
 		require.NoError(t, err)
 	})
+
+	t.Run("should be ok with a postgres URL only", func(t *testing.T) {
+		t.Setenv("PORT", "v")
+		t.Setenv("ENV", "debug")
+		t.Setenv("PG_URL", "postgres://u:p@pg.example.com:25060/db?sslmode=verify-full")
+
+		c, err := New()
+		require.NoError(t, err)
+		assert.Equal(t, "postgres://u:p@pg.example.com:25060/db?sslmode=verify-full", c.PGURL)
+	})
+
+	t.Run("should read the connection config", func(t *testing.T) {
+		t.Setenv("PORT", "v")
+		t.Setenv("ENV", "debug")
+		t.Setenv("PG_HOST", "pg.example.com")
+		t.Setenv("PG_USER", "v")
+		t.Setenv("PG_NAME", "postgres")
+		t.Setenv("PG_PASSWORD", "v")
+		t.Setenv("PG_PORT", "25060")
+		t.Setenv("PG_SSL_MODE", "verify-full")
+		t.Setenv("PG_SSL_ROOT_CERT", "/certs/ca.pem")
+		t.Setenv("PG_STATEMENT_TIMEOUT", "30s")
+		t.Setenv("PG_MAX_OPEN_CONNS", "50")
+
+		c, err := New()
+		require.NoError(t, err)
+		assert.Equal(t, 25060, c.PGPort)
+		assert.Equal(t, "verify-full", c.PGSSLMode)
+		assert.Equal(t, "/certs/ca.pem", c.PGSSLRootCert)
+		assert.Equal(t, 30*time.Second, c.PGStatementTimeout)
+		assert.Equal(t, 50, c.PGMaxOpenConns)
+		assert.Equal(t, 10, c.PGMaxIdleConns)
+		assert.Equal(t, "sellerpayout", c.PGApplicationName)
+	})
+
+	t.Run("should return an errParseEnv error because sslmode is unknown", func(t *testing.T) {
+		t.Setenv("PORT", "v")
+		t.Setenv("ENV", "debug")
+		t.Setenv("PG_URL", "postgres://u:p@pg.example.com/db")
+		t.Setenv("PG_SSL_MODE", "on")
+
+		_, err := New()
+		assert.Equal(t, true, errors.Is(err, errParseEnv))
+	})
+
+	t.Run("should read the replicas", func(t *testing.T) {
+		t.Setenv("PORT", "v")
+		t.Setenv("ENV", "debug")
+		t.Setenv("PG_HOST", "postgres")
+		t.Setenv("PG_USER", "v")
+		t.Setenv("PG_NAME", "postgres")
+		t.Setenv("PG_PASSWORD", "v")
+		t.Setenv("PG_REPLICA_HOSTS", "replica-1,replica-2")
+		t.Setenv("PG_MAX_REPLICA_LAG", "10s")
+
+		c, err := New()
+		require.NoError(t, err)
+		require.Equal(t, []string{"replica-1", "replica-2"}, c.PGReplicaHosts)
+		require.Equal(t, 10*time.Second, c.PGMaxReplicaLag)
+	})
 }
@@ -2,16 +2,9 @@
 // This file was generated by swaggo/swag
 package docs
 
-import (
-	"bytes"
-	"encoding/json"
-	"strings"
-	"text/template"
+import "github.com/swaggo/swag"
 
-	"github.com/swaggo/swag"
-)
-
-var doc = `{
+const docTemplate_swagger = `{
     "schemes": {{ marshal .Schemes }},
     "swagger": "2.0",
     "info": {
@@ -21,37 +14,907 @@ var doc = `{
             "name": "Romain Testard",
             "email": "romain.rtestard@gmail.com"
         },
-        "version": "{{.Version}}"
-    },
-    "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
-    "paths": {
-        "/health": {
-            "get": {
-                "description": "Healthcheck endpoint, to ensure that the service is running.",
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/jobs": {
+            "get": {
+                "description": "Read the history of the background job runs with their outcome, duration and counts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Endpoint to list the runs of the background jobs.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job name, e.g. create-payouts",
+                        "name": "job",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run status (running, succeeded, partial or failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order by start date (asc or desc, default desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponsePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/jobs/{id}": {
+            "get": {
+                "description": "Read a job run with its outcome, duration, counts and amounts paid out per currency.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Endpoint to retrieve a run of a background job.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payout-runs": {
+            "post": {
+                "description": "Run the payouts creation now, for all sellers or the ones listed.\nWith dry_run the payouts are computed and returned, but not created,\nfor the sellers listed in seller_ids only, which is then required, 100 at most.\nOtherwise the run goes on in the background and is returned once started, to be polled on /admin/jobs/:id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Endpoint to create payouts on demand.",
+                "parameters": [
+                    {
+                        "description": "Find the fields needed to run the payouts creation using the 'http' tab below.",
+                        "name": "create",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.PayoutRun"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-files": {
+            "post": {
+                "description": "Export approved payouts to a SEPA pain.001.001.03 file for EUR and a NACHA file for USD.\nExported payouts are linked to their file, invalid payouts are reported and stay approved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "BankFile"
+                ],
+                "summary": "Endpoint to generate bank files out of approved payouts.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank-files/{id}": {
+            "get": {
+                "description": "Download a bank file to upload it to the bank.",
+                "produces": [
+                    "application/xml",
+                    "text/plain"
+                ],
+                "tags": [
+                    "BankFile"
+                ],
+                "summary": "Endpoint to download a bank file.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bank file ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Healthcheck endpoint, to ensure that the service is running.\nThe state of the background jobs, failures and open circuits, is reported along.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.HealthResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/items": {
+            "get": {
+                "description": "Read items with their paid out status and payout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Endpoint to list items.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Seller ID",
+                        "name": "seller_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item currency (GBP, USD or EUR)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item status (paid or unpaid)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order by creation date (asc or desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponsePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create items. Invalid items are reported one by one with their index, field and code.\nWith partial=true, valid items are created and invalid ones reported alongside.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Endpoint to send sold items.",
+                "parameters": [
+                    {
+                        "description": "Find the fields needed to create items using the 'http' tab below.",
+                        "name": "create",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateItemsRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create valid items even if some are invalid",
+                        "name": "partial",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/imports": {
+            "post": {
+                "description": "Streams a CSV (name,amount,currency,seller_id header) or NDJSON upload,\nvalid rows are inserted by batches and rejected rows reported on the import.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Endpoint to bulk import sold items.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload format (csv or ndjson), defaults to the Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/imports/{id}": {
+            "get": {
+                "description": "Read the status and row counts of an items import.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Endpoint to retrieve an items import.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/imports/{id}/errors": {
+            "get": {
+                "description": "Read the per-row error report of an items import, ordered by row.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Endpoint to retrieve the rejected rows of an items import.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponsePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}": {
+            "get": {
+                "description": "Read an item with its paid out status and payout.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Endpoint to retrieve an item.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts": {
+            "get": {
+                "description": "Read payouts of a seller with the detail of their items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seller"
+                ],
+                "summary": "Endpoint to retrieve payouts for a specific seller.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Seller ID query parameter",
+                        "name": "seller_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payout status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order by creation date (asc or desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponsePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts/{id}": {
+            "get": {
+                "description": "Read a payout with its items, original amounts and conversions.\nDeprecated: the path used to take a seller ID, which still lists the latest 200 payouts of the seller\nwith a Link header to the following ones. Use /payouts?seller_id= instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Endpoint to retrieve a payout.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payouts/{id}/approve": {
+            "post": {
+                "description": "Approve a created payout so that it is exported in the next bank file.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payout"
+                ],
+                "summary": "Endpoint to approve a payout.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations": {
+            "post": {
+                "description": "Upload a camt.053 XML or CSV (date,amount,currency,reference header, debits negative) statement.\nDebits are matched to exported payouts by reference, then by amount, currency and date,\nmatched payouts are settled and the others reported as exceptions.",
                 "consumes": [
+                    "application/xml",
+                    "text/csv"
+                ],
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Reconciliation"
+                ],
+                "summary": "Endpoint to reconcile a bank statement against exported payouts.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statement format (camt.053 or csv), defaults to the Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations/{id}": {
+            "get": {
+                "description": "Read a reconciliation with its exceptions report.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Reconciliation"
+                ],
+                "summary": "Endpoint to retrieve a reconciliation.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.HealthResp"
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     }
                 }
             }
         },
-        "/items": {
+        "/seller": {
             "post": {
-                "description": "Create items.",
+                "description": "Create Seller.",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,17 +922,17 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Items"
+                    "Seller"
                 ],
-                "summary": "Endpoint to send sold items.",
+                "summary": "Endpoint to create seller.",
                 "parameters": [
                     {
-                        "description": "Find the fields needed to create items using the 'handler' tab below.",
+                        "description": "Find the fields needed to create a seller using the 'http' tab below.",
                         "name": "create",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateItemsRequest"
+                            "$ref": "#/definitions/http.Seller"
                         }
                     }
                 ],
@@ -77,71 +940,95 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseSuccess"
+                            "$ref": "#/definitions/http.ResponseSuccess"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseError"
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseError"
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     }
                 }
             }
         },
-        "/payouts/:seller_id": {
+        "/sellers/{id}/statements": {
             "get": {
-                "description": "Create Seller.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Export the payouts of a seller over a period with their items, conversions and fees,\nalong with the opening and closing balance (cumulated amount paid out).",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/pdf"
                 ],
                 "tags": [
                     "Seller"
                 ],
-                "summary": "Endpoint to retrieve payouts for a specific seller.",
+                "summary": "Endpoint to export the payout statement of a seller.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Seller ID query parameter",
-                        "name": "seller_id",
+                        "description": "Seller ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period start, included (RFC3339)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period end, excluded (RFC3339)",
+                        "name": "to",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Statement format (csv or pdf), defaults to csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseSuccess"
+                            "type": "file"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseError"
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseError"
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     }
                 }
             }
         },
-        "/seller": {
+        "/webhooks": {
             "post": {
-                "description": "Create Seller.",
+                "description": "Register a URL notified of events. The secret returned signs the payloads and is not shown again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,37 +1036,116 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Seller"
+                    "Webhook"
                 ],
-                "summary": "Endpoint to create seller.",
+                "summary": "Endpoint to subscribe to events.",
                 "parameters": [
                     {
-                        "description": "Find the fields needed to create a seller using the 'handler' tab below.",
+                        "description": "Find the fields needed to create a webhook using the 'http' tab below.",
                         "name": "create",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.Seller"
+                            "$ref": "#/definitions/http.Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/dead-letters": {
+            "get": {
+                "description": "Read the deliveries which ran out of attempts and were not replayed yet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Endpoint to list the webhook dead letters.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseSuccess"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     }
+                }
+            }
+        },
+        "/webhooks/dead-letters/{id}/replay": {
+            "post": {
+                "description": "Schedule the delivery of a dead letter for a new round of attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Endpoint to replay a webhook dead letter.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseSuccess"
+                            "$ref": "#/definitions/http.ResponseSuccess"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseError"
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ResponseError"
+                            "$ref": "#/definitions/http.ResponseError"
                         }
                     }
                 }
@@ -187,34 +1153,81 @@ var doc = `{
         }
     },
     "definitions": {
-        "handler.CreateItemsRequest": {
+        "domain.JobState": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "description": "ConsecutiveFailures is the number of failed runs since the last successful one.",
+                    "type": "integer"
+                },
+                "job": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                },
+                "open_until": {
+                    "description": "OpenUntil is set while the circuit of the job is open, its runs are skipped until then.",
+                    "type": "string"
+                },
+                "running": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "http.CreateItemsRequest": {
             "type": "object",
             "properties": {
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.Item"
+                        "$ref": "#/definitions/http.Item"
                     }
                 }
             }
         },
-        "handler.Error": {
+        "http.Error": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "Code is a machine readable identifier of the error, e.g. required or out_of_range.",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Field is the path of the invalid field within the payload, e.g. amount.",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Index is the position of the invalid element when the payload is a list.",
+                    "type": "integer"
+                },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "handler.HealthResp": {
+        "http.HealthResp": {
             "type": "object",
             "properties": {
+                "jobs": {
+                    "description": "Jobs reports the background jobs of the replica, a failing job does not fail the health check.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.JobState"
+                    }
+                },
                 "status": {
                     "type": "boolean"
                 }
             }
         },
-        "handler.Item": {
+        "http.Item": {
             "type": "object",
             "required": [
                 "amount",
@@ -237,84 +1250,115 @@ var doc = `{
                 }
             }
         },
-        "handler.ResponseError": {
+        "http.PayoutRun": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "DryRun returns the payouts which would be created without creating them, for the sellers listed only.",
+                    "type": "boolean"
+                },
+                "seller_ids": {
+                    "description": "SellerIDs restricts the run to some sellers, all sellers are paid out when empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.ResponseError": {
             "type": "object",
             "properties": {
                 "errors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.Error"
+                        "$ref": "#/definitions/http.Error"
                     }
                 }
             }
         },
-        "handler.ResponseSuccess": {
+        "http.ResponsePage": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "next_cursor": {
+                    "description": "NextCursor is to be sent back as cursor query parameter to fetch the next page,\nit is omitted on the last page.",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "http.ResponseSuccess": {
             "type": "object",
             "properties": {
                 "data": {}
             }
         },
-        "handler.Seller": {
+        "http.Seller": {
             "type": "object",
             "properties": {
+                "account_number": {
+                    "type": "string",
+                    "maxLength": 17
+                },
+                "bic": {
+                    "type": "string",
+                    "maxLength": 11
+                },
                 "currency": {
                     "type": "string"
+                },
+                "iban": {
+                    "type": "string",
+                    "maxLength": 34
+                },
+                "name": {
+                    "description": "Bank details, checked when the payouts are exported to a bank file.",
+                    "type": "string",
+                    "maxLength": 140
+                },
+                "routing_number": {
+                    "type": "string",
+                    "maxLength": 9
+                }
+            }
+        },
+        "http.Webhook": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
     }
 }`
 
-type swaggerInfo struct {
-	Version     string
-	Host        string
-	BasePath    string
-	Schemes     []string
-	Title       string
-	Description string
-}
-
-// SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = swaggerInfo{
-	Version:     "1.0",
-	Host:        "localhost:3000",
-	BasePath:    "",
-	Schemes:     []string{},
-	Title:       "SellerPayout Rest Server",
-	Description: "Server allowing interaction with Seller Payout Domain",
-}
-
-type s struct{}
-
-func (s *s) ReadDoc() string {
-	sInfo := SwaggerInfo
-	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
-
-	t, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-			return string(a)
-		},
-		"escape": func(v interface{}) string {
-			// escape tabs
-			str := strings.Replace(v.(string), "\t", "\\t", -1)
-			// replace " with \", and if that results in \\", replace that with \\\"
-			str = strings.Replace(str, "\"", "\\\"", -1)
-			return strings.Replace(str, "\\\\\"", "\\\\\\\"", -1)
-		},
-	}).Parse(doc)
-	if err != nil {
-		return doc
-	}
-
-	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, sInfo); err != nil {
-		return doc
-	}
-
-	return tpl.String()
+// SwaggerInfo_swagger holds exported Swagger Info so clients can modify it
+var SwaggerInfo_swagger = &swag.Spec{
+	Version:          "1.0",
+	Host:             "localhost:3000",
+	BasePath:         "",
+	Schemes:          []string{},
+	Title:            "SellerPayout Rest Server",
+	Description:      "Server allowing interaction with Seller Payout Domain",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate_swagger,
 }
 
 func init() {
-	swag.Register("swagger", &s{})
+	swag.Register(SwaggerInfo_swagger.InstanceName(), SwaggerInfo_swagger)
 }
@@ -0,0 +1,252 @@
+package bankfile
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// amountDecimals is the number of decimals banks accept, payouts are rounded to the cent.
+const amountDecimals int32 = 2
+
+var (
+	errNotApproved   = errors.New("payout is not approved")
+	errCurrency      = errors.New("no bank file format for currency")
+	errAmount        = errors.New("invalid payout amount")
+	errBankDetails   = errors.New("invalid seller bank details")
+	errOriginator    = errors.New("invalid originator bank details")
+	errInvalidFile   = errors.New("invalid bank file")
+	errMissingSeller = errors.New("payout seller is not loaded")
+)
+
+var (
+	bicPattern     = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	ibanPattern    = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	routingPattern = regexp.MustCompile(`^[0-9]{9}$`)
+	accountPattern = regexp.MustCompile(`^[0-9A-Z-]{1,17}$`)
+)
+
+// formats maps the currencies to the format of their bank file.
+var formats = map[string]domain.BankFileFormat{
+	currency.EURCode: domain.BankFileFormatSEPA,
+	currency.USDCode: domain.BankFileFormatNACHA,
+}
+
+// Originator holds our bank details, the account payouts are sent from.
+type Originator struct {
+	Name string
+	// IBAN and BIC identify the account debited by SEPA transfers.
+	IBAN string
+	BIC  string
+	// RoutingNumber is the ACH routing number of our bank (ODFI).
+	RoutingNumber string
+	// BankName is the name of our bank, the destination of NACHA files.
+	BankName string
+	// CompanyID is our ACH company identification, usually 1 followed by our tax ID.
+	CompanyID string
+}
+
+// File is a bank file generated out of payouts of a same currency.
+type File struct {
+	domain.BankFile
+	PayoutIDs []uuid.UUID
+}
+
+// Rejection is a payout which could not be included in a bank file.
+type Rejection struct {
+	PayoutID uuid.UUID
+	Err      error
+}
+
+// Result holds the files generated and the payouts left aside.
+type Result struct {
+	Files    []File
+	Rejected []Rejection
+}
+
+// Generate builds a bank file per currency out of approved payouts, their Seller and Currency must be loaded.
+// Invalid payouts are rejected, the others are still exported.
+func Generate(o Originator, payouts []domain.Payout, now time.Time) (Result, error) {
+	var res Result
+
+	byCurrency := make(map[string][]domain.Payout)
+
+	for _, p := range payouts {
+		if err := checkPayout(p); err != nil {
+			res.Rejected = append(res.Rejected, Rejection{PayoutID: p.ID, Err: err})
+
+			continue
+		}
+
+		byCurrency[p.Currency.Code] = append(byCurrency[p.Currency.Code], p)
+	}
+
+	// sort currencies so that files are generated in a stable order.
+	codes := make([]string, 0, len(byCurrency))
+	for code := range byCurrency {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		var (
+			f   File
+			err error
+		)
+
+		switch formats[code] {
+		case domain.BankFileFormatSEPA:
+			f, err = newSEPAFile(o, byCurrency[code], now)
+		case domain.BankFileFormatNACHA:
+			f, err = newNACHAFile(o, byCurrency[code], now)
+		}
+
+		if err != nil {
+			return Result{}, fmt.Errorf("%s file: %w", code, err)
+		}
+
+		res.Files = append(res.Files, f)
+	}
+
+	return res, nil
+}
+
+// checkPayout validates a payout against the requirements of its bank file format.
+func checkPayout(p domain.Payout) error {
+	if p.Status != domain.PayoutStatusApproved {
+		return fmt.Errorf("%w: %s", errNotApproved, p.Status)
+	}
+
+	if p.Seller.ID == uuid.Nil {
+		return errMissingSeller
+	}
+
+	amount := p.PriceTotal.Round(amountDecimals)
+	if !amount.IsPositive() {
+		return fmt.Errorf("%w: %s", errAmount, p.PriceTotal)
+	}
+
+	switch formats[p.Currency.Code] {
+	case domain.BankFileFormatSEPA:
+		if amount.GreaterThan(sepaMaxAmount) {
+			return fmt.Errorf("%w: %s exceeds %s", errAmount, amount, sepaMaxAmount)
+		}
+
+		return checkSEPAAccount(p.Seller.Name, p.Seller.IBAN, p.Seller.BIC, errBankDetails)
+	case domain.BankFileFormatNACHA:
+		if amount.GreaterThan(nachaMaxAmount) {
+			return fmt.Errorf("%w: %s exceeds %s", errAmount, amount, nachaMaxAmount)
+		}
+
+		return checkACHAccount(p.Seller.Name, p.Seller.RoutingNumber, p.Seller.AccountNumber)
+	default:
+		return fmt.Errorf("%w: %s", errCurrency, p.Currency.Code)
+	}
+}
+
+func checkSEPAAccount(name, iban, bic string, sentinel error) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: missing name", sentinel)
+	}
+
+	if !validIBAN(iban) {
+		return fmt.Errorf("%w: invalid IBAN %q", sentinel, iban)
+	}
+
+	if !bicPattern.MatchString(bic) {
+		return fmt.Errorf("%w: invalid BIC %q", sentinel, bic)
+	}
+
+	return nil
+}
+
+func checkACHAccount(name, routing, account string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: missing name", errBankDetails)
+	}
+
+	if !validRoutingNumber(routing) {
+		return fmt.Errorf("%w: invalid routing number %q", errBankDetails, routing)
+	}
+
+	if !accountPattern.MatchString(account) {
+		return fmt.Errorf("%w: invalid account number %q", errBankDetails, account)
+	}
+
+	return nil
+}
+
+// validIBAN checks the IBAN format and its ISO 7064 mod 97-10 check digits.
+func validIBAN(iban string) bool {
+	if !ibanPattern.MatchString(iban) {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			// letters count as two digits, A=10 ... Z=35.
+			v := int(r-'A') + 10
+			remainder = (remainder*100 + v) % 97
+
+			continue
+		}
+
+		remainder = (remainder*10 + int(r-'0')) % 97
+	}
+
+	return remainder == 1
+}
+
+// validRoutingNumber checks an ABA routing number and its 3-7-1 weighted check digit.
+func validRoutingNumber(routing string) bool {
+	if !routingPattern.MatchString(routing) {
+		return false
+	}
+
+	weights := [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+
+	for i, r := range routing {
+		sum += int(r-'0') * weights[i]
+	}
+
+	return sum%10 == 0
+}
+
+// messageID is the unique reference of a file, at most 35 characters.
+func messageID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+func controlSum(payouts []domain.Payout) decimal.Decimal {
+	sum := decimal.Zero
+	for _, p := range payouts {
+		sum = sum.Add(p.PriceTotal.Round(amountDecimals))
+	}
+
+	return sum
+}
+
+func payoutIDs(payouts []domain.Payout) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(payouts))
+	for _, p := range payouts {
+		ids = append(ids, p.ID)
+	}
+
+	return ids
+}
+
+// newID generates the ID of a file, replaced in tests to get stable files.
+var newID = uuid.NewV4
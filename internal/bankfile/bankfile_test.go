@@ -0,0 +1,185 @@
+package bankfile
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var testNow = time.Date(2022, 2, 14, 13, 24, 21, 0, time.UTC)
+
+func testOriginator() Originator {
+	return Originator{
+		Name:          "Seller Payout Ltd",
+		IBAN:          "DE89370400440532013000",
+		BIC:           "COBADEFFXXX",
+		RoutingNumber: "021000021",
+		BankName:      "JPMorgan Chase",
+		CompanyID:     "1234567890",
+	}
+}
+
+func testPayout(id, code, amount string) domain.Payout {
+	return domain.Payout{
+		ID:         uuid.FromStringOrNil(id),
+		Status:     domain.PayoutStatusApproved,
+		PriceTotal: decimal.RequireFromString(amount),
+		Currency:   domain.Currency{Code: code},
+		Seller: domain.Seller{
+			ID:            uuid.FromStringOrNil("78dd7916-f276-494b-84a8-83e5bbee8c27"),
+			Name:          "Jane Dœ & Sons",
+			IBAN:          "FR1420041010050500013M02606",
+			BIC:           "BNPAFRPP",
+			RoutingNumber: "011000015",
+			AccountNumber: "123456789",
+		},
+	}
+}
+
+// stableIDs makes file IDs predictable for the duration of a test.
+func stableIDs(t *testing.T) {
+	t.Helper()
+
+	ids := []string{"0d6f5b8e-9a4c-4d0e-8f59-bd5d3b6c2a11", "1e7a6c9f-ab5d-4e1f-9a6a-ce6e4c7d3b22"}
+	orig := newID
+
+	newID = func() (uuid.UUID, error) {
+		id := uuid.FromStringOrNil(ids[0])
+		ids = ids[1:]
+
+		return id, nil
+	}
+
+	t.Cleanup(func() { newID = orig })
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o600))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(got))
+}
+
+func TestGenerate(t *testing.T) {
+	stableIDs(t)
+
+	payouts := []domain.Payout{
+		testPayout("7ba5b759-43b3-44f4-9c25-6377975836b7", currency.USDCode, "1.354"),
+		testPayout("155e9496-8890-426b-b530-58bf76867350", currency.EURCode, "1082.8915"),
+		testPayout("b55a1eae-f24b-4e61-acec-90d75bdb51b2", currency.EURCode, "10"),
+		testPayout("7cee6ac2-116a-418c-887e-5c46e7db2c2a", currency.USDCode, "999999.999"),
+		testPayout("3c8c3d1c-5d9a-4a43-9bb4-2a0e5f1c9f10", currency.GBPCode, "10"),
+	}
+
+	notApproved := testPayout("4d1cea0f-e45d-4773-891e-4543c99dab62", currency.EURCode, "10")
+	notApproved.Status = domain.PayoutStatusCreated
+
+	badIBAN := testPayout("9f2b7c1e-3d4a-4b5c-8d6e-7f8091a2b3c4", currency.EURCode, "10")
+	badIBAN.Seller.IBAN = "FR1420041010050500013M02607"
+
+	payouts = append(payouts, notApproved, badIBAN)
+
+	res, err := Generate(testOriginator(), payouts, testNow)
+	require.NoError(t, err)
+
+	require.Len(t, res.Files, 2)
+
+	sepa, nacha := res.Files[0], res.Files[1]
+
+	assert.Equal(t, domain.BankFileFormatSEPA, sepa.Format)
+	assert.Equal(t, "0d6f5b8e9a4c4d0e8f59bd5d3b6c2a11", sepa.MessageID)
+	assert.Equal(t, 2, sepa.PaymentsCount)
+	assert.Equal(t, "1092.89", sepa.ControlSum.StringFixed(2))
+	assert.Equal(t, payouts[1].ID, sepa.PayoutIDs[0])
+	assertGolden(t, "pain.001.001.03.xml", sepa.Content)
+
+	assert.Equal(t, domain.BankFileFormatNACHA, nacha.Format)
+	assert.Equal(t, 2, nacha.PaymentsCount)
+	assert.Equal(t, "1000001.35", nacha.ControlSum.StringFixed(2))
+	assertGolden(t, "nacha.ach", nacha.Content)
+
+	require.Len(t, res.Rejected, 3)
+	assert.ErrorIs(t, res.Rejected[0].Err, errCurrency)
+	assert.ErrorIs(t, res.Rejected[1].Err, errNotApproved)
+	assert.ErrorIs(t, res.Rejected[2].Err, errBankDetails)
+}
+
+func TestGenerateInvalidOriginator(t *testing.T) {
+	o := testOriginator()
+	o.RoutingNumber = "021000022"
+
+	_, err := Generate(o, []domain.Payout{testPayout("7ba5b759-43b3-44f4-9c25-6377975836b7", currency.USDCode, "1")}, testNow)
+	assert.ErrorIs(t, err, errOriginator)
+}
+
+func Test_validIBAN(t *testing.T) {
+	assert.True(t, validIBAN("DE89370400440532013000"))
+	assert.True(t, validIBAN("GB29NWBK60161331926819"))
+	assert.False(t, validIBAN("DE89370400440532013001"))
+	assert.False(t, validIBAN("de89370400440532013000"))
+}
+
+func Test_validRoutingNumber(t *testing.T) {
+	assert.True(t, validRoutingNumber("021000021"))
+	assert.False(t, validRoutingNumber("021000022"))
+	assert.False(t, validRoutingNumber("02100002"))
+}
+
+func Test_validateNACHA(t *testing.T) {
+	stableIDs(t)
+
+	f, err := newNACHAFile(testOriginator(), []domain.Payout{testPayout("7ba5b759-43b3-44f4-9c25-6377975836b7", currency.USDCode, "1")}, testNow)
+	require.NoError(t, err)
+
+	records := splitLines(string(f.Content))
+	require.Len(t, records, nachaBlockingFactor)
+	require.NoError(t, validateNACHA(records))
+
+	// tamper the amount of the entry.
+	records[2] = records[2][:29] + "0000000200" + records[2][39:]
+	assert.ErrorIs(t, validateNACHA(records), errInvalidFile)
+}
+
+func Test_sepaText(t *testing.T) {
+	assert.Equal(t, "Jane D Sons", sepaText("Jane Dœ & Sons", 70))
+	assert.Equal(t, "abc", sepaText("abcdef", 3))
+}
+
+func splitLines(s string) []string {
+	var lines []string
+
+	for len(s) > 0 {
+		i := 0
+		for i < len(s) && s[i] != '\n' {
+			i++
+		}
+
+		lines = append(lines, s[:i])
+
+		if i < len(s) {
+			i++
+		}
+
+		s = s[i:]
+	}
+
+	return lines
+}
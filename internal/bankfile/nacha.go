@@ -0,0 +1,209 @@
+package bankfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	nachaRecordSize     = 94
+	nachaBlockingFactor = 10
+	// nachaCreditsOnly is the service class code of a batch of credits.
+	nachaCreditsOnly = "220"
+	// nachaCheckingCredit is the transaction code of a credit to a checking account.
+	nachaCheckingCredit = "22"
+	nachaEntryClass     = "PPD"
+	nachaEntryDesc      = "PAYOUT"
+)
+
+// nachaMaxAmount is the maximum amount of an entry, its field holds 10 digits of cents.
+var nachaMaxAmount = decimal.RequireFromString("99999999.99")
+
+// newNACHAFile builds a NACHA file holding a single batch of credit entries.
+func newNACHAFile(o Originator, payouts []domain.Payout, now time.Time) (File, error) {
+	if strings.TrimSpace(o.Name) == "" || !validRoutingNumber(o.RoutingNumber) || len(o.CompanyID) != 10 {
+		return File{}, fmt.Errorf("%w: name, routing number and 10 characters company ID are required", errOriginator)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return File{}, err
+	}
+
+	var (
+		records   []string
+		entryHash int64
+		credit    int64
+	)
+
+	now = now.UTC()
+	odfi := o.RoutingNumber[:8]
+
+	records = append(records, "1"+
+		"01"+
+		" "+o.RoutingNumber+
+		" "+o.RoutingNumber+
+		now.Format("060102")+
+		now.Format("1504")+
+		"A"+
+		"094"+
+		"10"+
+		"1"+
+		nachaAlpha(o.BankName, 23)+
+		nachaAlpha(o.Name, 23)+
+		nachaAlpha(messageID(id), 8))
+
+	records = append(records, "5"+
+		nachaCreditsOnly+
+		nachaAlpha(o.Name, 16)+
+		nachaAlpha("", 20)+
+		nachaAlpha(o.CompanyID, 10)+
+		nachaEntryClass+
+		nachaAlpha(nachaEntryDesc, 10)+
+		now.Format("060102")+
+		now.Format("060102")+
+		"   "+
+		"1"+
+		odfi+
+		nachaNum(1, 7))
+
+	for i, p := range payouts {
+		cents := p.PriceTotal.Round(amountDecimals).Shift(amountDecimals).IntPart()
+		rdfi, _ := strconv.ParseInt(p.Seller.RoutingNumber[:8], 10, 64)
+
+		entryHash += rdfi
+		credit += cents
+
+		records = append(records, "6"+
+			nachaCheckingCredit+
+			p.Seller.RoutingNumber+
+			nachaAlpha(p.Seller.AccountNumber, 17)+
+			nachaNum(cents, 10)+
+			nachaAlpha(messageID(p.ID), 15)+
+			nachaAlpha(p.Seller.Name, 22)+
+			"  "+
+			"0"+
+			odfi+nachaNum(int64(i+1), 7))
+	}
+
+	// entry hash keeps the 10 rightmost digits of the sum.
+	entryHash %= 10_000_000_000
+
+	records = append(records, "8"+
+		nachaCreditsOnly+
+		nachaNum(int64(len(payouts)), 6)+
+		nachaNum(entryHash, 10)+
+		nachaNum(0, 12)+
+		nachaNum(credit, 12)+
+		nachaAlpha(o.CompanyID, 10)+
+		nachaAlpha("", 19)+
+		nachaAlpha("", 6)+
+		odfi+
+		nachaNum(1, 7))
+
+	// records are grouped by blocks of 10, the last one is filled with 9s.
+	blocks := (len(records) + 1 + nachaBlockingFactor - 1) / nachaBlockingFactor
+
+	records = append(records, "9"+
+		nachaNum(1, 6)+
+		nachaNum(int64(blocks), 6)+
+		nachaNum(int64(len(payouts)), 8)+
+		nachaNum(entryHash, 10)+
+		nachaNum(0, 12)+
+		nachaNum(credit, 12)+
+		nachaAlpha("", 39))
+
+	for len(records)%nachaBlockingFactor != 0 {
+		records = append(records, strings.Repeat("9", nachaRecordSize))
+	}
+
+	if err := validateNACHA(records); err != nil {
+		return File{}, err
+	}
+
+	return File{
+		BankFile: domain.BankFile{
+			ID:            id,
+			Format:        domain.BankFileFormatNACHA,
+			CurrencyCode:  payouts[0].Currency.Code,
+			MessageID:     messageID(id),
+			PaymentsCount: len(payouts),
+			ControlSum:    decimal.New(credit, -amountDecimals),
+			Content:       []byte(strings.Join(records, "\n") + "\n"),
+		},
+		PayoutIDs: payoutIDs(payouts),
+	}, nil
+}
+
+// validateNACHA checks the records layout and that control records match the entries.
+func validateNACHA(records []string) error {
+	if len(records)%nachaBlockingFactor != 0 {
+		return fmt.Errorf("%w: %d records is not a multiple of %d", errInvalidFile, len(records), nachaBlockingFactor)
+	}
+
+	var (
+		entries   int64
+		entryHash int64
+		credit    int64
+		control   string
+	)
+
+	for i, r := range records {
+		if len(r) != nachaRecordSize {
+			return fmt.Errorf("%w: record %d holds %d characters", errInvalidFile, i+1, len(r))
+		}
+
+		switch r[0] {
+		case '6':
+			rdfi, err1 := strconv.ParseInt(r[3:11], 10, 64)
+			amount, err2 := strconv.ParseInt(r[29:39], 10, 64)
+
+			if err1 != nil || err2 != nil || !validRoutingNumber(r[3:12]) {
+				return fmt.Errorf("%w: invalid entry record %d", errInvalidFile, i+1)
+			}
+
+			entries++
+			entryHash += rdfi
+			credit += amount
+		case '9':
+			if control == "" {
+				control = r
+			}
+		}
+	}
+
+	expected := nachaNum(entries, 8) + nachaNum(entryHash%10_000_000_000, 10) + nachaNum(0, 12) + nachaNum(credit, 12)
+	if control == "" || control[13:55] != expected {
+		return fmt.Errorf("%w: file control does not match entries", errInvalidFile)
+	}
+
+	return nil
+}
+
+// nachaAlpha left justifies an alphanumeric field, upper cased and truncated to n characters.
+func nachaAlpha(s string, n int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' {
+			return ' '
+		}
+
+		return r
+	}, strings.ToUpper(s))
+	s = strings.Join(strings.Fields(s), " ")
+
+	if len(s) > n {
+		return s[:n]
+	}
+
+	return s + strings.Repeat(" ", n-len(s))
+}
+
+// nachaNum right justifies a numeric field padded with zeros.
+func nachaNum(v int64, n int) string {
+	return fmt.Sprintf("%0*d", n, v)
+}
@@ -0,0 +1,231 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	sepaNamespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+	// sepaMaxText is the length of the Max35Text ISO 20022 type.
+	sepaMaxText = 35
+	// sepaMaxName is the length of the Max70Text ISO 20022 type used for names.
+	sepaMaxName = 70
+	// sepaMaxRemittance is the length of the Max140Text ISO 20022 type.
+	sepaMaxRemittance = 140
+)
+
+// sepaMaxAmount is the maximum amount of a SEPA credit transfer.
+var sepaMaxAmount = decimal.RequireFromString("999999999.99")
+
+// sepaDocument is a pain.001.001.03 customer credit transfer initiation,
+// restricted to the elements of the SEPA implementation guidelines we fill.
+type sepaDocument struct {
+	XMLName xml.Name        `xml:"Document"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Init    sepaCstmrCdtTrf `xml:"CstmrCdtTrfInitn"`
+}
+
+type sepaCstmrCdtTrf struct {
+	GrpHdr sepaGrpHdr `xml:"GrpHdr"`
+	PmtInf sepaPmtInf `xml:"PmtInf"`
+}
+
+type sepaGrpHdr struct {
+	MsgID    string    `xml:"MsgId"`
+	CreDtTm  string    `xml:"CreDtTm"`
+	NbOfTxs  int       `xml:"NbOfTxs"`
+	CtrlSum  string    `xml:"CtrlSum"`
+	InitgPty sepaParty `xml:"InitgPty"`
+}
+
+type sepaPmtInf struct {
+	PmtInfID    string       `xml:"PmtInfId"`
+	PmtMtd      string       `xml:"PmtMtd"`
+	BtchBookg   bool         `xml:"BtchBookg"`
+	NbOfTxs     int          `xml:"NbOfTxs"`
+	CtrlSum     string       `xml:"CtrlSum"`
+	SvcLvl      string       `xml:"PmtTpInf>SvcLvl>Cd"`
+	ReqdExctnDt string       `xml:"ReqdExctnDt"`
+	Dbtr        sepaParty    `xml:"Dbtr"`
+	DbtrAcct    sepaAccount  `xml:"DbtrAcct"`
+	DbtrAgt     sepaAgent    `xml:"DbtrAgt"`
+	ChrgBr      string       `xml:"ChrgBr"`
+	CdtTrfTxInf []sepaCdtTrf `xml:"CdtTrfTxInf"`
+}
+
+type sepaCdtTrf struct {
+	EndToEndID string      `xml:"PmtId>EndToEndId"`
+	InstdAmt   sepaAmount  `xml:"Amt>InstdAmt"`
+	CdtrAgt    sepaAgent   `xml:"CdtrAgt"`
+	Cdtr       sepaParty   `xml:"Cdtr"`
+	CdtrAcct   sepaAccount `xml:"CdtrAcct"`
+	Ustrd      string      `xml:"RmtInf>Ustrd"`
+}
+
+type sepaParty struct {
+	Nm string `xml:"Nm"`
+}
+
+type sepaAccount struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type sepaAgent struct {
+	BIC string `xml:"FinInstnId>BIC"`
+}
+
+type sepaAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+// newSEPAFile builds a pain.001.001.03 file with a single payment information block.
+func newSEPAFile(o Originator, payouts []domain.Payout, now time.Time) (File, error) {
+	if err := checkSEPAAccount(o.Name, o.IBAN, o.BIC, errOriginator); err != nil {
+		return File{}, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return File{}, err
+	}
+
+	msgID := messageID(id)
+	sum := controlSum(payouts)
+
+	doc := sepaDocument{
+		Xmlns: sepaNamespace,
+		Init: sepaCstmrCdtTrf{
+			GrpHdr: sepaGrpHdr{
+				MsgID:    msgID,
+				CreDtTm:  now.UTC().Format("2006-01-02T15:04:05"),
+				NbOfTxs:  len(payouts),
+				CtrlSum:  sum.StringFixed(amountDecimals),
+				InitgPty: sepaParty{Nm: sepaText(o.Name, sepaMaxName)},
+			},
+			PmtInf: sepaPmtInf{
+				PmtInfID:    msgID,
+				PmtMtd:      "TRF",
+				BtchBookg:   true,
+				NbOfTxs:     len(payouts),
+				CtrlSum:     sum.StringFixed(amountDecimals),
+				SvcLvl:      "SEPA",
+				ReqdExctnDt: now.UTC().Format("2006-01-02"),
+				Dbtr:        sepaParty{Nm: sepaText(o.Name, sepaMaxName)},
+				DbtrAcct:    sepaAccount{IBAN: o.IBAN},
+				DbtrAgt:     sepaAgent{BIC: o.BIC},
+				ChrgBr:      "SLEV",
+			},
+		},
+	}
+
+	for _, p := range payouts {
+		doc.Init.PmtInf.CdtTrfTxInf = append(doc.Init.PmtInf.CdtTrfTxInf, sepaCdtTrf{
+			EndToEndID: messageID(p.ID),
+			InstdAmt:   sepaAmount{Ccy: p.Currency.Code, Value: p.PriceTotal.Round(amountDecimals).StringFixed(amountDecimals)},
+			CdtrAgt:    sepaAgent{BIC: p.Seller.BIC},
+			Cdtr:       sepaParty{Nm: sepaText(p.Seller.Name, sepaMaxName)},
+			CdtrAcct:   sepaAccount{IBAN: p.Seller.IBAN},
+			Ustrd:      sepaText("Payout "+p.ID.String(), sepaMaxRemittance),
+		})
+	}
+
+	if err := doc.validate(); err != nil {
+		return File{}, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return File{}, err
+	}
+
+	buf.WriteString("\n")
+
+	return File{
+		BankFile: domain.BankFile{
+			ID:            id,
+			Format:        domain.BankFileFormatSEPA,
+			CurrencyCode:  payouts[0].Currency.Code,
+			MessageID:     msgID,
+			PaymentsCount: len(payouts),
+			ControlSum:    sum,
+			Content:       buf.Bytes(),
+		},
+		PayoutIDs: payoutIDs(payouts),
+	}, nil
+}
+
+// validate checks the document against the pain.001.001.03 schema facets
+// and the SEPA rules: lengths, patterns, counts and control sums.
+func (d sepaDocument) validate() error {
+	hdr, pmt := d.Init.GrpHdr, d.Init.PmtInf
+
+	if len(hdr.MsgID) == 0 || len(hdr.MsgID) > sepaMaxText || len(pmt.PmtInfID) > sepaMaxText {
+		return fmt.Errorf("%w: message id should hold 1 to %d characters", errInvalidFile, sepaMaxText)
+	}
+
+	if len(pmt.CdtTrfTxInf) == 0 || hdr.NbOfTxs != len(pmt.CdtTrfTxInf) || pmt.NbOfTxs != hdr.NbOfTxs {
+		return fmt.Errorf("%w: number of transactions mismatch", errInvalidFile)
+	}
+
+	sum := decimal.Zero
+
+	for _, tx := range pmt.CdtTrfTxInf {
+		amount, err := decimal.NewFromString(tx.InstdAmt.Value)
+		if err != nil || !amount.IsPositive() || amount.GreaterThan(sepaMaxAmount) || amount.Exponent() < -amountDecimals {
+			return fmt.Errorf("%w: invalid amount %q for %s", errInvalidFile, tx.InstdAmt.Value, tx.EndToEndID)
+		}
+
+		if tx.InstdAmt.Ccy != "EUR" {
+			return fmt.Errorf("%w: SEPA transfers should be in EUR, got %s", errInvalidFile, tx.InstdAmt.Ccy)
+		}
+
+		if len(tx.EndToEndID) == 0 || len(tx.EndToEndID) > sepaMaxText {
+			return fmt.Errorf("%w: invalid end to end id %q", errInvalidFile, tx.EndToEndID)
+		}
+
+		if len(tx.Cdtr.Nm) == 0 || len(tx.Cdtr.Nm) > sepaMaxName || len(tx.Ustrd) > sepaMaxRemittance {
+			return fmt.Errorf("%w: invalid text length for %s", errInvalidFile, tx.EndToEndID)
+		}
+
+		sum = sum.Add(amount)
+	}
+
+	if hdr.CtrlSum != sum.StringFixed(amountDecimals) || pmt.CtrlSum != hdr.CtrlSum {
+		return fmt.Errorf("%w: control sum %s, expected %s", errInvalidFile, hdr.CtrlSum, sum.StringFixed(amountDecimals))
+	}
+
+	return nil
+}
+
+// sepaText restricts a text to the SEPA character set and truncates it to max characters.
+func sepaText(s string, max int) string {
+	const allowed = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/-?:().,'+ "
+
+	out := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(allowed, r) {
+			return r
+		}
+
+		return ' '
+	}, s)
+
+	out = strings.Join(strings.Fields(out), " ")
+	if len(out) > max {
+		out = strings.TrimSpace(out[:max])
+	}
+
+	return out
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BankFileFormat is the format of a bank file.
+type BankFileFormat string
+
+const (
+	// BankFileFormatSEPA is the ISO 20022 pain.001.001.03 credit transfer format, for EUR.
+	BankFileFormatSEPA BankFileFormat = "pain.001.001.03"
+	// BankFileFormatNACHA is the NACHA ACH format, for USD.
+	BankFileFormatNACHA BankFileFormat = "nacha"
+)
+
+// BankFile is a payment file handed over to a bank to send payouts.
+type BankFile struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	Format       BankFileFormat `json:"format"`
+	CurrencyCode string         `json:"currency"`
+	// MessageID is the reference of the file known by the bank.
+	MessageID     string          `json:"message_id"`
+	PaymentsCount int             `json:"payments_count"`
+	ControlSum    decimal.Decimal `json:"control_sum"`
+	Content       []byte          `json:"-"`
+
+	// https://gorm.io/docs/has_many.html
+	Payouts []Payout `gorm:"foreignKey:BankFileID" json:"-"`
+}
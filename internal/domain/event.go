@@ -0,0 +1,13 @@
+package domain
+
+// EventType is the type of an event published to other systems.
+type EventType string
+
+const (
+	// EventItemCreated is emitted for each item created through the API.
+	EventItemCreated EventType = "item.created"
+	// EventPayoutCreated is emitted for each payout issued by the payouts creation task.
+	EventPayoutCreated EventType = "payout.created"
+	// EventPayoutStatusChanged is emitted when a payout is approved, exported or settled.
+	EventPayoutStatusChanged EventType = "payout.status_changed"
+)
@@ -24,4 +24,8 @@ type Item struct {
 	// https://gorm.io/docs/belongs_to.html#Belongs-To
 	SellerID uuid.UUID `gorm:"type:uuid" json:"seller_id"`
 	Seller   Seller    `gorm:"foreignKey:seller_id" json:"seller"`
+
+	// https://gorm.io/docs/has_one.html
+	// PayoutItem links the item to its payout, nil until the item is paid out.
+	PayoutItem *PayoutItem `gorm:"foreignKey:ItemID" json:"-"`
 }
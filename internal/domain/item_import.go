@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ItemImportStatus is the state of an items import.
+type ItemImportStatus string
+
+const (
+	// ItemImportStatusProcessing is the status of an import being streamed.
+	ItemImportStatusProcessing ItemImportStatus = "processing"
+	// ItemImportStatusCompleted is the status of an import which went through every row.
+	ItemImportStatusCompleted ItemImportStatus = "completed"
+	// ItemImportStatusFailed is the status of an import aborted before its last row,
+	// rows of already inserted batches are kept.
+	ItemImportStatusFailed ItemImportStatus = "failed"
+)
+
+// ItemImport is a bulk upload of items.
+type ItemImport struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	Format       string           `json:"format"`
+	Status       ItemImportStatus `json:"status"`
+	TotalRows    int              `json:"total_rows"`
+	ImportedRows int              `json:"imported_rows"`
+	FailedRows   int              `json:"failed_rows"`
+	Error        string           `json:"error,omitempty"`
+	FinishedAt   *time.Time       `json:"finished_at"`
+}
+
+// ItemImportError is a row rejected by an items import.
+type ItemImportError struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	// RowNumber is the 1-based position of the record in the uploaded file, CSV header excluded.
+	RowNumber int    `json:"row"`
+	Message   string `json:"message"`
+
+	ItemImportID uuid.UUID `gorm:"type:uuid" json:"-"`
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// JobRunStatus is the outcome of a background job run.
+type JobRunStatus string
+
+const (
+	// JobRunRunning is a run which has not finished yet, or whose process stopped before it finished.
+	JobRunRunning JobRunStatus = "running"
+	// JobRunSucceeded is a run which finished without error.
+	JobRunSucceeded JobRunStatus = "succeeded"
+	// JobRunPartial is a run which finished without error, but failed for some sellers.
+	JobRunPartial JobRunStatus = "partial"
+	// JobRunFailed is a run which returned an error.
+	JobRunFailed JobRunStatus = "failed"
+)
+
+// JobRunTrigger is what started a background job run.
+type JobRunTrigger string
+
+const (
+	// JobRunTriggerSchedule is a run started by the scheduler.
+	JobRunTriggerSchedule JobRunTrigger = "schedule"
+	// JobRunTriggerManual is a run started through the admin API.
+	JobRunTriggerManual JobRunTrigger = "manual"
+)
+
+// JobRun records a run of a background job and what it did.
+type JobRun struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	Job        string        `json:"job"`
+	Trigger    JobRunTrigger `json:"trigger"`
+	Status     JobRunStatus  `json:"status"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	DurationMS int64         `gorm:"column:duration_ms" json:"duration_ms"`
+	Error      string        `json:"error,omitempty"`
+
+	// SellersProcessed, SellersFailed and PayoutsCreated are only counted by the payouts creation job.
+	SellersProcessed int `json:"sellers_processed"`
+	SellersFailed    int `json:"sellers_failed"`
+	PayoutsCreated   int `json:"payouts_created"`
+
+	// https://gorm.io/docs/has_many.html
+	Totals   []JobRunTotal   `gorm:"foreignKey:JobRunID" json:"totals"`
+	Failures []JobRunFailure `gorm:"foreignKey:JobRunID" json:"failures"`
+}
+
+// JobRunTotal is the amount paid out by a run in a currency.
+type JobRunTotal struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	CurrencyCode string          `json:"currency"`
+	PayoutsCount int             `json:"payouts_count"`
+	Amount       decimal.Decimal `json:"amount"`
+
+	JobRunID uuid.UUID `gorm:"type:uuid" json:"-"`
+}
+
+// JobRunFailure is a seller the payouts creation failed for, its unpaid out items are left for the next run.
+type JobRunFailure struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	SellerID uuid.UUID `gorm:"type:uuid" json:"seller_id"`
+	Error    string    `json:"error"`
+
+	JobRunID uuid.UUID `gorm:"type:uuid" json:"-"`
+}
+
+// AddFailure records the failure of the payouts creation of a seller.
+func (r *JobRun) AddFailure(sellerID uuid.UUID, err error) {
+	r.SellersFailed++
+	r.Failures = append(r.Failures, JobRunFailure{SellerID: sellerID, Error: err.Error()})
+}
+
+// AddPayout counts a payout created by the run in the total of its currency.
+func (r *JobRun) AddPayout(p Payout) {
+	r.PayoutsCreated++
+
+	for i := range r.Totals {
+		if r.Totals[i].CurrencyCode == p.Seller.CurrencyCode {
+			r.Totals[i].PayoutsCount++
+			r.Totals[i].Amount = r.Totals[i].Amount.Add(p.PriceTotal)
+
+			return
+		}
+	}
+
+	r.Totals = append(r.Totals, JobRunTotal{
+		CurrencyCode: p.Seller.CurrencyCode,
+		PayoutsCount: 1,
+		Amount:       p.PriceTotal,
+	})
+}
+
+// Finish sets the outcome of the run.
+func (r *JobRun) Finish(at time.Time, err error) {
+	r.FinishedAt = &at
+	r.DurationMS = at.Sub(r.StartedAt).Milliseconds()
+	r.Status = JobRunSucceeded
+
+	if r.SellersFailed > 0 {
+		r.Status = JobRunPartial
+	}
+
+	if err != nil {
+		r.Status = JobRunFailed
+		r.Error = err.Error()
+	}
+}
+
+// JobState is the health of a background job on the replica reporting it.
+type JobState struct {
+	Job     string `json:"job"`
+	Running bool   `json:"running"`
+	// ConsecutiveFailures is the number of failed runs since the last successful one.
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastRunAt           *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	// OpenUntil is set while the circuit of the job is open, its runs are skipped until then.
+	OpenUntil *time.Time `json:"open_until,omitempty"`
+}
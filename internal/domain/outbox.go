@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// OutboxMessage is an event recorded in the transaction of the change it is about,
+// published afterwards by the outbox relay.
+type OutboxMessage struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	// Seq orders the messages, it is assigned by the database on insert.
+	Seq int64 `gorm:"autoIncrement;<-:false" json:"seq"`
+	// Key groups the messages published in order, e.g. the seller ID.
+	Key       uuid.UUID `gorm:"type:uuid" json:"key"`
+	EventType EventType `json:"event_type"`
+	// Payload is the JSON encoded event data.
+	Payload []byte `json:"-"`
+
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	// DeadAt is set once the message ran out of attempts, it is not published anymore.
+	DeadAt *time.Time `json:"dead_at,omitempty"`
+}
+
+// TableName overrides the table name, outbox is a singular noun.
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
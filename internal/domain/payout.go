@@ -7,6 +7,20 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// PayoutStatus is the lifecycle state of a payout.
+type PayoutStatus string
+
+const (
+	// PayoutStatusCreated is the status of a payout just issued by the payouts creation task.
+	PayoutStatusCreated PayoutStatus = "created"
+	// PayoutStatusApproved is the status of a payout cleared to be sent to the bank.
+	PayoutStatusApproved PayoutStatus = "approved"
+	// PayoutStatusExported is the status of a payout included in a bank file.
+	PayoutStatusExported PayoutStatus = "exported"
+	// PayoutStatusSettled is the status of a payout found on a bank statement.
+	PayoutStatusSettled PayoutStatus = "settled"
+)
+
 // Payout is an invoice assigned to a seller with a total price in a currency
 // for a list of items.
 type Payout struct {
@@ -15,12 +29,19 @@ type Payout struct {
 	UpdatedAt time.Time `json:"-"`
 
 	PriceTotal decimal.Decimal `json:"price_total"`
+	Status     PayoutStatus    `gorm:"default:created" json:"status"`
 
 	// https://gorm.io/docs/belongs_to.html#Belongs-To
 	SellerID   uuid.UUID `gorm:"type:uuid" json:"seller_id"`
 	Seller     Seller    `gorm:"foreignKey:seller_id" json:"seller"`
 	CurrencyID uuid.UUID `gorm:"type:uuid" json:"currency_id"`
 	Currency   Currency  `gorm:"foreignKey:currency_id" json:"currency"`
+	// BankFileID is the bank file the payout was exported in, nil until exported.
+	BankFileID *uuid.UUID `gorm:"type:uuid" json:"bank_file_id,omitempty"`
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+	SettledAt  *time.Time `json:"settled_at,omitempty"`
 
 	Items []Item `gorm:"many2many:payout_items;"`
+	// https://gorm.io/docs/has_many.html
+	Lines []PayoutItem `gorm:"foreignKey:PayoutID" json:"lines"`
 }
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayoutItem is a payout line, it records how an item price was converted
+// into the payout currency.
+type PayoutItem struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	// ConvertedAmount and ExchangeRate are null for payouts created before
+	// conversions were recorded.
+	ConvertedAmount decimal.NullDecimal `json:"converted_amount"`
+	ExchangeRate    decimal.NullDecimal `json:"exchange_rate"`
+
+	// https://gorm.io/docs/belongs_to.html#Belongs-To
+	PayoutID uuid.UUID `gorm:"type:uuid" json:"payout_id"`
+	ItemID   uuid.UUID `gorm:"type:uuid" json:"item_id"`
+	Item     Item      `gorm:"foreignKey:item_id" json:"item"`
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ReconciliationExceptionKind is the reason a statement entry or a payout could not be reconciled.
+type ReconciliationExceptionKind string
+
+const (
+	// ExceptionUnmatchedEntry is a debit of the statement matching no payout.
+	ExceptionUnmatchedEntry ReconciliationExceptionKind = "unmatched_entry"
+	// ExceptionAmountMismatch is an entry referencing a payout with a different amount.
+	ExceptionAmountMismatch ReconciliationExceptionKind = "amount_mismatch"
+	// ExceptionCurrencyMismatch is an entry referencing a payout in a different currency.
+	ExceptionCurrencyMismatch ReconciliationExceptionKind = "currency_mismatch"
+	// ExceptionAmbiguousEntry is an entry without reference matching several payouts.
+	ExceptionAmbiguousEntry ReconciliationExceptionKind = "ambiguous_entry"
+	// ExceptionDuplicateEntry is an entry referencing a payout already matched.
+	ExceptionDuplicateEntry ReconciliationExceptionKind = "duplicate_entry"
+	// ExceptionUnmatchedPayout is an exported payout not found on the statement in time.
+	ExceptionUnmatchedPayout ReconciliationExceptionKind = "unmatched_payout"
+)
+
+// Reconciliation is the matching of a bank statement against exported payouts.
+type Reconciliation struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	Format          string `json:"format"`
+	EntriesCount    int    `json:"entries_count"`
+	MatchedCount    int    `json:"matched_count"`
+	ExceptionsCount int    `json:"exceptions_count"`
+
+	// https://gorm.io/docs/has_many.html
+	Exceptions []ReconciliationException `gorm:"foreignKey:ReconciliationID" json:"exceptions"`
+}
+
+// ReconciliationException reports a statement entry or a payout left unreconciled.
+type ReconciliationException struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+
+	Kind    ReconciliationExceptionKind `json:"kind"`
+	Message string                      `json:"message"`
+
+	// Entry fields are empty for unmatched payouts.
+	EntryReference string              `json:"entry_reference,omitempty"`
+	EntryAmount    decimal.NullDecimal `json:"entry_amount"`
+	EntryCurrency  string              `json:"entry_currency,omitempty"`
+	EntryDate      *time.Time          `json:"entry_date,omitempty"`
+
+	// PayoutID is the payout the exception is about, if any.
+	PayoutID *uuid.UUID `gorm:"type:uuid" json:"payout_id,omitempty"`
+
+	ReconciliationID uuid.UUID `gorm:"type:uuid" json:"-"`
+}
@@ -14,5 +14,13 @@ type Seller struct {
 
 	CurrencyCode string `json:"currency_code"`
 
+	// Bank details the payouts are sent to, IBAN and BIC for SEPA transfers,
+	// routing and account numbers for ACH transfers.
+	Name          string `json:"name,omitempty"`
+	IBAN          string `json:"-"`
+	BIC           string `json:"bic,omitempty"`
+	RoutingNumber string `json:"routing_number,omitempty"`
+	AccountNumber string `json:"-"`
+
 	Items []Item
 }
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// WebhookDeliveryStatus is the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is a delivery waiting for its next attempt.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered is a delivery acknowledged by the subscriber.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead is a delivery which ran out of attempts, see WebhookDeadLetter.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookSubscription is an endpoint notified of events.
+type WebhookSubscription struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	URL string `json:"url"`
+	// Secret signs the payloads, it is only returned on creation.
+	Secret string `json:"-"`
+	// Events is the comma separated list of subscribed event types.
+	Events string `json:"events"`
+	Active bool   `gorm:"default:true" json:"active"`
+}
+
+// Subscribes tells whether the subscription receives an event type.
+func (s WebhookSubscription) Subscribes(t EventType) bool {
+	for _, e := range strings.Split(s.Events, ",") {
+		if EventType(strings.TrimSpace(e)) == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDelivery is an event to send to a subscription.
+type WebhookDelivery struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	EventID   uuid.UUID `gorm:"type:uuid" json:"event_id"`
+	EventType EventType `json:"event_type"`
+	// Payload is the JSON body sent, signed as is.
+	Payload []byte `json:"-"`
+
+	Status        WebhookDeliveryStatus `gorm:"default:pending" json:"status"`
+	Attempts      int                   `json:"attempts"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	LastError     string                `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty"`
+
+	SubscriptionID uuid.UUID           `gorm:"type:uuid" json:"subscription_id"`
+	Subscription   WebhookSubscription `gorm:"foreignKey:subscription_id" json:"-"`
+}
+
+// WebhookDeadLetter is a delivery which ran out of attempts, kept to be replayed.
+type WebhookDeadLetter struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+
+	DeliveryID uuid.UUID       `gorm:"type:uuid" json:"delivery_id"`
+	Delivery   WebhookDelivery `gorm:"foreignKey:delivery_id" json:"-"`
+}
@@ -1,20 +1,19 @@
 package cron
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/TestardR/seller-payout/internal/domain"
 	"github.com/TestardR/seller-payout/pkg/db"
 )
 
 // UpdateCurrencies is a background task,
 // it calls an external API to update currencies exchange rate.
-func (h handler) UpdateCurrencies() error {
+func (h handler) UpdateCurrencies(ctx context.Context) error {
 	h.Log.Info("currencies update started")
 
-	var currencies []domain.Currency
-
-	if err := h.DB.FindAll(&currencies); err != nil {
+	currencies, err := h.Repos.Currencies().All(ctx)
+	if err != nil {
 		err = fmt.Errorf("%w: %s", db.ErrDB, err)
 		h.Log.Error(err)
 
@@ -22,7 +21,7 @@ func (h handler) UpdateCurrencies() error {
 	}
 
 	for i, c := range currencies {
-		rate, err := h.EX.GetConversionRate(c.Code)
+		rate, err := h.EX.GetConversionRate(ctx, c.Code)
 		if err != nil {
 			h.Log.Error(err)
 
@@ -32,7 +31,7 @@ func (h handler) UpdateCurrencies() error {
 		currencies[i].USDExchRate = rate
 	}
 
-	if err := h.DB.Update(currencies); err != nil {
+	if err := h.Repos.Currencies().UpdateRates(ctx, currencies); err != nil {
 		err = fmt.Errorf("%w: %s", db.ErrDB, err)
 		h.Log.Error(err)
 
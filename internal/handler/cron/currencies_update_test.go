@@ -1,6 +1,7 @@
 package cron
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -20,37 +21,37 @@ func TestHandler_UpdateCurrencies(t *testing.T) {
 	mEX := mock.NewMockExchanger(ctrl)
 
 	h := handler{
-		Log: mLog,
-		DB:  mDB,
-		EX:  mEX,
+		Log:   mLog,
+		Repos: db.NewUnitOfWork(mDB),
+		EX:    mEX,
 	}
 
 	t.Run("should_be_ok", func(t *testing.T) {
 		mLog.EXPECT().Info(gomock.Any())
-		mDB.EXPECT().FindAll(gomock.Any())
-		mDB.EXPECT().Update(gomock.Any())
+		mDB.EXPECT().FindCurrencies(gomock.Any())
+		mDB.EXPECT().UpdateCurrencyRates(gomock.Any(), gomock.Any())
 		mLog.EXPECT().Info(gomock.Any())
 
-		err := h.UpdateCurrencies()
+		err := h.UpdateCurrencies(context.Background())
 		require.NoError(t, err)
 	})
 
 	t.Run("should_return_an_error_if_db_find_all_fails", func(t *testing.T) {
 		mLog.EXPECT().Info(gomock.Any())
-		mDB.EXPECT().FindAll(gomock.Any()).Return(errors.New("mock"))
+		mDB.EXPECT().FindCurrencies(gomock.Any()).Return(nil, errors.New("mock"))
 		mLog.EXPECT().Error(gomock.Any())
 
-		err := h.UpdateCurrencies()
+		err := h.UpdateCurrencies(context.Background())
 		assert.ErrorIs(t, err, db.ErrDB)
 	})
 
 	t.Run("should_return_an_error_if_db_update_fails", func(t *testing.T) {
 		mLog.EXPECT().Info(gomock.Any())
-		mDB.EXPECT().FindAll(gomock.Any())
-		mDB.EXPECT().Update(gomock.Any()).Return(errors.New("mock"))
+		mDB.EXPECT().FindCurrencies(gomock.Any())
+		mDB.EXPECT().UpdateCurrencyRates(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
 		mLog.EXPECT().Error(gomock.Any())
 
-		err := h.UpdateCurrencies()
+		err := h.UpdateCurrencies(context.Background())
 		assert.ErrorIs(t, err, db.ErrDB)
 	})
 }
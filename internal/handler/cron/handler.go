@@ -1,49 +1,233 @@
 package cron
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/TestardR/seller-payout/config"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/outbox"
+	"github.com/TestardR/seller-payout/internal/scheduler"
+	"github.com/TestardR/seller-payout/internal/webhook"
 	"github.com/TestardR/seller-payout/pkg/currency"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/logger"
 )
 
 var (
-	errCreatePayouts    = errors.New("failed to create payouts")
-	errUpdateCurrencies = errors.New("failed to update currencies")
+	errLockLost  = errors.New("job lock lost while running")
+	errRecordRun = errors.New("failed to record job run")
+)
+
+// maxRetryBackoff caps the delay between the attempts of a failed run.
+const maxRetryBackoff = 10 * time.Minute
+
+// Names of the jobs registered in the scheduler.
+const (
+	jobCreatePayouts    = "create-payouts"
+	jobUpdateCurrencies = "update-currencies"
+	jobDeliverWebhooks  = "deliver-webhooks"
+	jobFlushOutbox      = "flush-outbox"
 )
 
 type handler struct {
 	Log logger.Logger
-	DB  db.DB
-	EX  currency.Exchanger
+	// DB holds the jobs locks, the data is read and written through Repos.
+	DB db.Locker
+	// Repos persists the data.
+	Repos db.UnitOfWork
+	EX    currency.Exchanger
+	Pool  pool
 }
 
-// Run initializes cron jobs.
-func Run(log logger.Logger, db db.DB, ex currency.Exchanger, c config.CronIntervals) {
+// Run registers the background jobs and starts running them on their schedule.
+func Run(
+	log logger.Logger,
+	database db.DB,
+	ex currency.Exchanger,
+	hooks *webhook.Dispatcher,
+	relay *outbox.Relay,
+	c config.Schedules) (*scheduler.Scheduler, error) {
 	h := handler{
-		Log: log,
-		DB:  db,
-		EX:  currency.New(),
+		Log:   log,
+		DB:    database,
+		Repos: db.NewUnitOfWork(database),
+		EX:    ex,
+		// the manual runs have their own pool, see NewPayoutCreator.
+		Pool: newPool(c.PayoutWorkers, c.PayoutTransactions),
+	}
+
+	s := scheduler.New(log)
+
+	// the currencies update has nothing to count in its run.
+	updateCurrencies := func(ctx context.Context, _ *domain.JobRun) error { return h.UpdateCurrencies(ctx) }
+
+	jobs := []scheduler.Job{
+		{
+			Name:       jobCreatePayouts,
+			Spec:       c.PayoutSchedule,
+			Timezone:   c.PayoutTimezone,
+			RunOnStart: c.PayoutRunOnStart,
+			Jitter:     c.PayoutJitter,
+			Retry: scheduler.Retry{
+				Attempts:   c.PayoutRetryAttempts,
+				Backoff:    c.PayoutRetryBackoff,
+				MaxBackoff: maxRetryBackoff,
+			},
+			Breaker: scheduler.Breaker{
+				Threshold: c.PayoutBreakerThreshold,
+				Cooldown:  c.PayoutBreakerCooldown,
+			},
+			Run: h.exclusive(jobCreatePayouts, h.recorded(jobCreatePayouts, h.CreatePayouts)),
+		},
+		{
+			Name:       jobUpdateCurrencies,
+			Spec:       c.CurrencySchedule,
+			Timezone:   c.CurrencyTimezone,
+			RunOnStart: c.CurrencyRunOnStart,
+			Jitter:     c.CurrencyJitter,
+			Retry: scheduler.Retry{
+				Attempts:   c.CurrencyRetryAttempts,
+				Backoff:    c.CurrencyRetryBackoff,
+				MaxBackoff: maxRetryBackoff,
+			},
+			Breaker: scheduler.Breaker{
+				Threshold: c.CurrencyBreakerThreshold,
+				Cooldown:  c.CurrencyBreakerCooldown,
+			},
+			Run: h.exclusive(jobUpdateCurrencies, h.recorded(jobUpdateCurrencies, updateCurrencies)),
+		},
+		// the webhooks and outbox jobs run every few seconds, their runs are not recorded.
+		{
+			// failed deliveries are retried, only the database errors are reported.
+			Name: jobDeliverWebhooks,
+			Spec: fmt.Sprintf("@every %ds", c.WebhookInterval),
+			Run:  h.exclusive(jobDeliverWebhooks, hooks.Deliver),
+		},
+		{
+			// messages which failed are published on the next runs,
+			// a single replica publishes them so that they stay in order.
+			Name: jobFlushOutbox,
+			Spec: fmt.Sprintf("@every %ds", c.OutboxInterval),
+			Run:  h.exclusive(jobFlushOutbox, relay.Flush),
+		},
+	}
+
+	for _, j := range jobs {
+		if err := s.Register(j); err != nil {
+			return nil, err
+		}
+	}
+
+	s.Start()
+
+	return s, nil
+}
+
+// exclusive runs a job only if no other replica is running it, the other replicas skip the run.
+func (h handler) exclusive(name string, run func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, release, err := h.lock(ctx, name)
+		if errors.Is(err, db.ErrLockHeld) {
+			h.Log.Info(fmt.Sprintf("job %s skipped, running on another replica", name))
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		defer release()
+
+		return run(ctx)
+	}
+}
+
+// lock takes the lock of a job across replicas, db.ErrLockHeld is returned when another replica holds it.
+// The returned context is cancelled when the lock is lost, so that the run stops before another replica
+// takes over, and once released.
+func (h handler) lock(ctx context.Context, name string) (context.Context, func(), error) {
+	lock, err := h.DB.TryLock(ctx, name)
+	if errors.Is(err, db.ErrLockHeld) {
+		return nil, nil, err
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", db.ErrDB, err)
 	}
 
-	payoutTicker := time.NewTicker(time.Duration(c.PayoutInterval) * time.Hour)
-	currencyTicker := time.NewTicker(time.Duration(c.CurrencyInterval) * time.Hour)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
 
 	go func() {
-		for {
-			select {
-			case <-payoutTicker.C:
-				if err := h.CreatePayouts(); err != nil {
-					log.Fatal("%w: %s", errCreatePayouts, err)
-				}
-			case <-currencyTicker.C:
-				if err := h.UpdateCurrencies(); err != nil {
-					log.Fatal("%w: %s", errUpdateCurrencies, err)
-				}
-			}
+		select {
+		case <-lock.Lost():
+			h.Log.Error(fmt.Errorf("%w: %s", errLockLost, name))
+			cancel()
+		case <-done:
 		}
 	}()
+
+	return ctx, func() {
+		close(done)
+		cancel()
+
+		if err := lock.Release(); err != nil {
+			h.Log.Error(fmt.Errorf("%w: %s", db.ErrDB, err))
+		}
+	}, nil
 }
+
+// recorded keeps the history of the runs of a job in the job_runs table.
+func (h handler) recorded(name string, run func(context.Context, *domain.JobRun) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		r := domain.JobRun{Job: name, Trigger: domain.JobRunTriggerSchedule}
+
+		return h.record(ctx, &r, run)
+	}
+}
+
+// record inserts the run before running it, then saves its outcome, even when ctx is cancelled.
+func (h handler) record(ctx context.Context, r *domain.JobRun, run func(context.Context, *domain.JobRun) error) error {
+	r.Status = domain.JobRunRunning
+	r.StartedAt = time.Now()
+
+	if err := h.Repos.JobRuns().Start(ctx, r); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	err := run(ctx, r)
+	r.Finish(time.Now(), err)
+
+	// the run already happened, failing to record its outcome does not fail it.
+	if ferr := h.finishRun(detached{ctx}, *r); ferr != nil {
+		h.Log.Error(fmt.Errorf("%w: %s", errRecordRun, ferr))
+	}
+
+	return err
+}
+
+func (h handler) finishRun(ctx context.Context, r domain.JobRun) error {
+	return h.Repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		if err := tx.JobRuns().Finish(ctx, r); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		return nil
+	})
+}
+
+// detached carries the values of its parent context but is never cancelled, so that the writes
+// started before a shutdown, such as a payout transaction, are completed rather than interrupted.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detached) Done() <-chan struct{} { return nil }
+
+func (detached) Err() error { return nil }
@@ -0,0 +1,234 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TestardR/seller-payout/config"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_InvalidSchedule(t *testing.T) {
+	_, err := Run(nil, nil, nil, nil, nil, config.Schedules{
+		PayoutSchedule:   "0 2 * * mon-fri",
+		PayoutTimezone:   "UTC",
+		CurrencySchedule: "every day",
+		WebhookInterval:  10,
+		OutboxInterval:   5,
+	})
+
+	assert.Error(t, err)
+}
+
+func TestHandler_Exclusive(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	t.Run("held-by-another-replica", func(t *testing.T) {
+		ml := mock.NewMockLogger(mc)
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(nil, db.ErrLockHeld)
+		ml.EXPECT().Info(gomock.Any())
+
+		h := handler{Log: ml, DB: mdb}
+		err := h.exclusive(jobCreatePayouts, func(context.Context) error {
+			t.Error("job should not run")
+
+			return nil
+		})(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("fail-db-lock", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(nil, errors.New("mock"))
+
+		h := handler{DB: mdb}
+		err := h.exclusive(jobCreatePayouts, func(context.Context) error { return nil })(context.Background())
+
+		assert.ErrorIs(t, err, db.ErrDB)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mlock := mock.NewMockLock(mc)
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(mlock, nil)
+		mlock.EXPECT().Lost().Return(make(chan struct{})).AnyTimes()
+		mlock.EXPECT().Release()
+
+		ran := false
+		h := handler{DB: mdb}
+		err := h.exclusive(jobCreatePayouts, func(context.Context) error {
+			ran = true
+
+			return errors.New("mock")
+		})(context.Background())
+
+		assert.Error(t, err)
+		assert.True(t, ran)
+	})
+
+	t.Run("lock-lost", func(t *testing.T) {
+		ml := mock.NewMockLogger(mc)
+		mdb := mock.NewMockDB(mc)
+		mlock := mock.NewMockLock(mc)
+
+		lost := make(chan struct{})
+		logged := make(chan struct{})
+
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(mlock, nil)
+		mlock.EXPECT().Lost().Return(lost)
+		mlock.EXPECT().Release()
+		ml.EXPECT().Error(gomock.Any()).Do(func(...interface{}) { close(logged) })
+
+		h := handler{Log: ml, DB: mdb}
+		err := h.exclusive(jobCreatePayouts, func(ctx context.Context) error {
+			close(lost)
+			<-logged
+			<-ctx.Done()
+
+			return ctx.Err()
+		})(context.Background())
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestHandler_Recorded(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	t.Run("fail-db-insert-run", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+
+		h := handler{DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+		err := h.recorded(jobCreatePayouts, func(context.Context, *domain.JobRun) error {
+			t.Error("job should not run")
+
+			return nil
+		})(context.Background())
+
+		assert.ErrorIs(t, err, db.ErrDB)
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.JobRun) error {
+			assert.Equal(t, jobCreatePayouts, r.Job)
+			assert.Equal(t, domain.JobRunSucceeded, r.Status)
+			assert.NotNil(t, r.FinishedAt)
+			assert.Equal(t, 2, r.PayoutsCreated)
+			assert.Len(t, r.Totals, 1)
+
+			return nil
+		})
+		mdb.EXPECT().Commit()
+
+		h := handler{DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+		err := h.recorded(jobCreatePayouts, func(_ context.Context, r *domain.JobRun) error {
+			seller := domain.Seller{CurrencyCode: "EUR"}
+			r.AddPayout(domain.Payout{Seller: seller, PriceTotal: decimal.NewFromInt(10)})
+			r.AddPayout(domain.Payout{Seller: seller, PriceTotal: decimal.NewFromInt(5)})
+
+			return nil
+		})(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.JobRun) error {
+			assert.Equal(t, domain.JobRunFailed, r.Status)
+			assert.Equal(t, "mock", r.Error)
+
+			return nil
+		})
+		mdb.EXPECT().Commit()
+
+		h := handler{DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+		err := h.recorded(jobCreatePayouts, func(context.Context, *domain.JobRun) error { return errors.New("mock") })(context.Background())
+
+		assert.EqualError(t, err, "mock")
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.JobRun) error {
+			assert.Equal(t, domain.JobRunPartial, r.Status)
+			assert.Equal(t, 1, r.SellersFailed)
+			assert.Len(t, r.Failures, 1)
+			assert.Empty(t, r.Error)
+
+			return nil
+		})
+		mdb.EXPECT().Commit()
+
+		h := handler{DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+		err := h.recorded(jobCreatePayouts, func(_ context.Context, r *domain.JobRun) error {
+			r.AddFailure(uuid.Must(uuid.NewV4()), errors.New("mock"))
+
+			return nil
+		})(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("recorded-once-cancelled", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		mdb.EXPECT().Begin(gomock.Any()).DoAndReturn(func(ctx context.Context) (db.DB, error) {
+			assert.NoError(t, ctx.Err())
+
+			return mdb, nil
+		})
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.JobRun) error {
+			assert.Equal(t, domain.JobRunFailed, r.Status)
+			assert.Equal(t, context.Canceled.Error(), r.Error)
+
+			return nil
+		})
+		mdb.EXPECT().Commit()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		h := handler{DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+		err := h.recorded(jobCreatePayouts, func(ctx context.Context, _ *domain.JobRun) error {
+			cancel()
+
+			return ctx.Err()
+		})(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("fail-db-finish-run", func(t *testing.T) {
+		ml := mock.NewMockLogger(mc)
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+		mdb.EXPECT().Rollback()
+		ml.EXPECT().Error(gomock.Any())
+
+		h := handler{Log: ml, DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+		err := h.recorded(jobCreatePayouts, func(context.Context, *domain.JobRun) error { return nil })(context.Background())
+
+		assert.NoError(t, err)
+	})
+}
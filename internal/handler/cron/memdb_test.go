@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// memDB is the memory DB where each call of the payouts creation waits for latency,
+// as a round trip to the database would.
+type memDB struct {
+	db.DB
+	latency time.Duration
+	sellers []domain.Seller
+}
+
+// newMemDB returns a memDB of sellers having items unpaid out, in USD as the sellers.
+func newMemDB(t testing.TB, latency time.Duration, sellers, items int) memDB {
+	m := memDB{DB: db.NewMemory(), latency: latency}
+	epoch := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < sellers; i++ {
+		s := domain.Seller{CreatedAt: epoch.Add(time.Duration(i) * time.Minute), CurrencyCode: "USD"}
+		require.NoError(t, m.DB.InsertSeller(context.Background(), &s))
+
+		batch := make([]domain.Item, 0, items)
+
+		for j := 0; j < items; j++ {
+			batch = append(batch, domain.Item{
+				CreatedAt:    epoch.Add(time.Duration(j) * time.Minute),
+				SellerID:     s.ID,
+				PriceAmount:  decimal.NewFromInt(400_000),
+				CurrencyCode: "USD",
+			})
+		}
+
+		require.NoError(t, m.DB.InsertItems(context.Background(), batch))
+
+		m.sellers = append(m.sellers, s)
+	}
+
+	return m
+}
+
+// payouts returns the number of payouts persisted.
+func (m memDB) payouts(t testing.TB) int {
+	payouts, err := m.DB.FindPayoutsByStatus(context.Background(), domain.PayoutStatusCreated)
+	require.NoError(t, err)
+
+	return len(payouts)
+}
+
+func (m memDB) roundTrip() {
+	time.Sleep(m.latency)
+}
+
+func (m memDB) Begin(ctx context.Context) (db.DB, error) {
+	m.roundTrip()
+
+	tx, err := m.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return memDB{DB: tx, latency: m.latency, sellers: m.sellers}, nil
+}
+
+func (m memDB) Commit() error {
+	m.roundTrip()
+
+	return m.DB.Commit()
+}
+
+func (m memDB) Rollback() error {
+	m.roundTrip()
+
+	return m.DB.Rollback()
+}
+
+func (m memDB) FindCurrencies(ctx context.Context) ([]domain.Currency, error) {
+	m.roundTrip()
+
+	return m.DB.FindCurrencies(ctx)
+}
+
+func (m memDB) FindSellersWithUnpaidOutItems(ctx context.Context, f db.SellersFilter) (db.SellersPage, error) {
+	m.roundTrip()
+
+	return m.DB.FindSellersWithUnpaidOutItems(ctx, f)
+}
+
+func (m memDB) FindUnpaidOutItemsPage(ctx context.Context, sellerID string, c *db.Cursor, limit int) (db.ItemsPage, error) {
+	m.roundTrip()
+
+	return m.DB.FindUnpaidOutItemsPage(ctx, sellerID, c, limit)
+}
+
+func (m memDB) LockUnpaidItems(ctx context.Context, ids []string) error {
+	m.roundTrip()
+
+	return m.DB.LockUnpaidItems(ctx, ids)
+}
+
+func (m memDB) InsertPayout(ctx context.Context, p *domain.Payout) error {
+	m.roundTrip()
+
+	return m.DB.InsertPayout(ctx, p)
+}
+
+func (m memDB) InsertOutboxMessage(ctx context.Context, msg *domain.OutboxMessage) error {
+	m.roundTrip()
+
+	return m.DB.InsertOutboxMessage(ctx, msg)
+}
+
+func (m memDB) PayOutItems(ctx context.Context, ids []string) error {
+	m.roundTrip()
+
+	return m.DB.PayOutItems(ctx, ids)
+}
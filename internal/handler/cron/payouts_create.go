@@ -1,37 +1,98 @@
 package cron
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/TestardR/seller-payout/internal/domain"
-	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/TestardR/seller-payout/internal/outbox"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/internal/webhook"
 	"github.com/TestardR/seller-payout/pkg/db"
-	"github.com/shopspring/decimal"
 )
 
-var errRecoverFromPanic = errors.New("panic defer handler")
+// Size of the chunks the sellers and their items are streamed by.
+const (
+	sellersChunk = 100
+	itemsChunk   = 500
+)
 
-const totalPriceLimit = 1_000_000
+var (
+	errSellerFailed     = errors.New("failed to create payouts of seller")
+	errAllSellersFailed = errors.New("failed to create payouts of every seller")
+)
 
-// CreatePayouts is a background task which goal is to create payouts.
-func (h handler) CreatePayouts() error {
-	h.Log.Info("payouts creation started")
+// CreatePayouts is a background task which goal is to create payouts,
+// the sellers processed and the payouts created are counted in the run.
+// A seller failing does not stop the others from being paid out: the failure is recorded in the run,
+// which ends up partial, and the items of the seller are left unpaid out for the next run to retry.
+// The run fails when every seller failed.
+// Once ctx is done no payout transaction is started, the ones in progress are completed.
+func (h handler) CreatePayouts(ctx context.Context, run *domain.JobRun) error {
+	_, err := h.createPayouts(ctx, run, payouts.Request{})
 
-	sellers, err := h.DB.FindSellersWhereItems(map[string]interface{}{"paid_out": false})
-	if err != nil {
-		err = fmt.Errorf("%w: %s", db.ErrDB, err)
-		h.Log.Error(err)
+	return err
+}
+
+// pool bounds the concurrency of the payouts creation: Workers sellers are processed at the same time
+// and at most Transactions payout transactions run at the same time across them.
+type pool struct {
+	Workers int
+	txs     chan struct{}
+}
+
+// newPool returns a pool of workers sharing the transactions slots.
+func newPool(workers, transactions int) pool {
+	return pool{Workers: workers, txs: make(chan struct{}, transactions)}
+}
+
+// workers is the number of sellers processed at the same time, sequentially if not configured.
+func (p pool) workers() int {
+	if p.Workers < 1 {
+		return 1
+	}
+
+	return p.Workers
+}
 
-		return err
+// acquire waits for a transaction slot, it does not wait if the transactions are not bounded.
+func (p pool) acquire() func() {
+	if p.txs == nil {
+		return func() {}
 	}
 
-	var currencies []domain.Currency
-	if err := h.DB.FindAll(&currencies); err != nil {
+	p.txs <- struct{}{}
+
+	return func() { <-p.txs }
+}
+
+// sellerResult is the outcome of the payouts creation of a seller.
+type sellerResult struct {
+	// seq is the position of the seller in the stream of sellers.
+	seq    int
+	seller domain.Seller
+	// payouts created, without their items, or only planned on a dry run, even when the seller failed afterwards.
+	// A seller interrupted by the cancellation has the error of the context.
+	payouts []domain.Payout
+	err     error
+}
+
+// createPayouts creates the payouts of the sellers in the scope of the request,
+// on a dry run the payouts are only computed and returned.
+// The sellers are streamed to the workers of the pool, the results are added to the run
+// in the order of the sellers so that its totals and failures do not depend on the scheduling.
+// No seller nor payout transaction is started once ctx is done, the run then fails with the error of ctx.
+func (h handler) createPayouts(ctx context.Context, run *domain.JobRun, r payouts.Request) ([]domain.Payout, error) {
+	h.Log.Info("payouts creation started")
+
+	currencies, err := h.Repos.Currencies().All(ctx)
+	if err != nil {
 		err = fmt.Errorf("%w: %s", db.ErrDB, err)
 		h.Log.Error(err)
 
-		return err
+		return nil, err
 	}
 
 	currenciesMap := make(map[string]domain.Currency)
@@ -39,189 +100,295 @@ func (h handler) CreatePayouts() error {
 		currenciesMap[c.Code] = c
 	}
 
-	for _, seller := range sellers {
-		if len(seller.Items) == 0 {
-			continue
+	var planned []domain.Payout
+
+	add := func(res sellerResult) {
+		for _, p := range res.payouts {
+			run.AddPayout(p)
 		}
-		// Concurrent Pipeline organizing payouts creation stages
-		if err := h.setupPipeline(seller, currenciesMap); err != nil {
-			h.Log.Error(err)
 
-			return err
+		if r.DryRun {
+			planned = append(planned, res.payouts...)
 		}
-	}
 
-	h.Log.Info("payouts creation finished")
+		// the items of an interrupted seller which are not paid out are left to the next run.
+		if res.err != nil && ctx.Err() != nil && errors.Is(res.err, ctx.Err()) {
+			h.Log.Info(fmt.Sprintf("payouts creation of seller %s interrupted: %s", res.seller.ID, res.err))
 
-	return nil
-}
+			return
+		}
 
-// setupPipeline organizes stages for staged processing.
-func (h handler) setupPipeline(seller domain.Seller, currenciesMap map[string]domain.Currency) error {
-	// if an error occurs the done channel will gracefully terminate stages 1. and 2.
-	done := make(chan struct{})
-	defer close(done)
+		if res.err != nil {
+			h.Log.Error(fmt.Errorf("%w %s: %s", errSellerFailed, res.seller.ID, res.err))
+			run.AddFailure(res.seller.ID, res.err)
 
-	// Stage 1. creates batch of items
-	itemsBatchC := generateItemsBatch(done, seller, currenciesMap)
-	// Stage 2. creates payouts
-	payoutC := generatePayouts(done, seller, currenciesMap, itemsBatchC)
-	// Stage 3. persists payouts
-	if err := h.persistPayouts(payoutC); err != nil {
+			return
+		}
+
+		run.SellersProcessed++
+	}
+
+	sellers := db.NewSellerIterator(h.Repos.Sellers(), r.SellerIDs, sellersChunk)
+
+	if err := h.processSellers(ctx, sellers, currenciesMap, r.DryRun, add); err != nil {
+		err = fmt.Errorf("%w: %s", db.ErrDB, err)
 		h.Log.Error(err)
 
-		return err
+		return planned, err
 	}
 
-	return nil
-}
+	h.Log.Info(fmt.Sprintf("payouts creation finished: %d sellers processed, %d failed",
+		run.SellersProcessed, run.SellersFailed))
+
+	if err := ctx.Err(); err != nil {
+		return planned, err
+	}
 
-type itemsBatch struct {
-	items      []domain.Item
-	totalPrice decimal.Decimal
+	// a single seller failing leaves the run partial, all of them failing is rather a failure of the job.
+	if run.SellersFailed > 0 && run.SellersProcessed == 0 {
+		return planned, fmt.Errorf("%w: %d sellers failed", errAllSellersFailed, run.SellersFailed)
+	}
+
+	return planned, nil
 }
 
-func generateItemsBatch(
-	done <-chan struct{},
-	seller domain.Seller,
-	currencies map[string]domain.Currency) <-chan itemsBatch {
-	itemsBatchC := make(chan itemsBatch)
+// processSellers streams the sellers to the workers and hands their results over to add in the order
+// of the sellers, holding back the results of the sellers processed ahead of a slower one.
+// The error of the sellers stream is returned, the streaming stops once ctx is done.
+func (h handler) processSellers(
+	ctx context.Context,
+	sellers *db.SellerIterator,
+	currenciesMap map[string]domain.Currency,
+	dryRun bool,
+	add func(sellerResult)) error {
+	sellerC := make(chan sellerResult)
+	resultC := make(chan sellerResult)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < h.Pool.workers(); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for res := range sellerC {
+				res.payouts, res.err = h.setupPipeline(ctx, res.seller, currenciesMap, dryRun)
+				resultC <- res
+			}
+		}()
+	}
 
-	go func() {
-		defer close(itemsBatchC)
+	var streamErr error
 
-		var batch []domain.Item
+	go func() {
+		defer close(sellerC)
 
-		totalPrice := decimal.NewFromInt(0)
+		for seq := 0; ctx.Err() == nil && sellers.Next(ctx); seq++ {
+			select {
+			case sellerC <- sellerResult{seq: seq, seller: sellers.Seller()}:
+			case <-ctx.Done():
+				return
+			}
+		}
 
-		for _, item := range seller.Items {
-			price := convertToSellerCurrency(
-				seller.CurrencyCode,
-				item.CurrencyCode,
-				currencies,
-				item.PriceAmount)
+		// the stream stopped by the cancellation is not an error of the stream.
+		if ctx.Err() == nil {
+			streamErr = sellers.Err()
+		}
+	}()
 
-			if totalPrice.Add(price).GreaterThan(decimal.NewFromInt(totalPriceLimit)) {
-				itemsBatchC <- itemsBatch{
-					batch,
-					totalPrice,
-				}
+	go func() {
+		wg.Wait()
+		close(resultC)
+	}()
 
-				batch = nil
-				totalPrice = decimal.NewFromInt(0)
-			}
+	pending := make(map[int]sellerResult)
+	next := 0
 
-			totalPrice = totalPrice.Add(price)
+	for res := range resultC {
+		pending[res.seq] = res
 
-			batch = append(batch, item)
+		for r, ok := pending[next]; ok; r, ok = pending[next] {
+			delete(pending, next)
+			add(r)
+			next++
 		}
+	}
 
-		ib := itemsBatch{
-			batch,
-			totalPrice,
-		}
+	return streamErr
+}
+
+// setupPipeline organizes stages for staged processing, it returns the payouts persisted
+// without their items, or only planned on a dry run with the items in their lines.
+func (h handler) setupPipeline(
+	ctx context.Context,
+	seller domain.Seller,
+	currenciesMap map[string]domain.Currency,
+	dryRun bool) ([]domain.Payout, error) {
+	// if an error occurs the done channel will gracefully terminate stages 0., 1. and 2.
+	done := make(chan struct{})
+	defer close(done)
+
+	// Stage 0. streams the unpaid out items of the seller
+	itemC, errC := h.streamItems(ctx, done, seller)
+	// Stages 1. and 2. create batches of items, then payouts
+	payoutC := payouts.Generate(done, seller, currenciesMap, itemC)
 
-		select {
-		case itemsBatchC <- ib:
-		case <-done:
+	if dryRun {
+		var planned []domain.Payout
+
+		for p := range payoutC {
+			// each line keeps the item it previews, as persistPayouts the payout does not keep its items.
+			for i := range p.Lines {
+				p.Lines[i].Item = p.Items[i]
+				p.Lines[i].Item.Seller = domain.Seller{}
+			}
+
+			p.Items = nil
+			planned = append(planned, p)
 		}
-	}()
 
-	return itemsBatchC
+		return planned, <-errC
+	}
+
+	// Stage 3. persists payouts
+	created, err := h.persistPayouts(ctx, payoutC)
+	if err == nil {
+		// the items stream ended before the payouts stages, its error is ready.
+		err = <-errC
+	}
+
+	if err != nil {
+		h.Log.Error(err)
+
+		return created, err
+	}
+
+	return created, nil
 }
 
-func generatePayouts(
+// streamItems fetches the unpaid out items of the seller by chunks and sends them on the returned channel.
+// The error of the stream is sent on the error channel once the items channel is closed.
+func (h handler) streamItems(
+	ctx context.Context,
 	done <-chan struct{},
-	seller domain.Seller,
-	currencies map[string]domain.Currency,
-	itemsBatchC <-chan itemsBatch) <-chan domain.Payout {
-	payoutC := make(chan domain.Payout)
-	sellerCurrency := seller.CurrencyCode
+	seller domain.Seller) (<-chan domain.Item, <-chan error) {
+	itemC := make(chan domain.Item)
+	errC := make(chan error, 1)
 
 	go func() {
-		defer close(payoutC)
-
-		for batch := range itemsBatchC {
-			p := domain.Payout{
-				PriceTotal: batch.totalPrice.Round(domain.PriceDecimals),
-				Items:      batch.items,
-				SellerID:   seller.ID,
-				Seller:     seller,
-				CurrencyID: currencies[sellerCurrency].ID,
-				Currency:   currencies[sellerCurrency],
-			}
+		defer close(itemC)
 
+		items := db.NewItemIterator(h.Repos.Items(), seller.ID.String(), itemsChunk)
+
+		for items.Next(ctx) {
 			select {
-			case payoutC <- p:
+			case itemC <- items.Item():
 			case <-done:
+				errC <- nil
+
+				return
 			}
 		}
+
+		switch {
+		case ctx.Err() != nil:
+			errC <- ctx.Err()
+		case items.Err() != nil:
+			errC <- fmt.Errorf("%w: %s", db.ErrDB, items.Err())
+		default:
+			errC <- nil
+		}
 	}()
 
-	return payoutC
+	return itemC, errC
 }
 
-func (h handler) persistPayouts(payoutC <-chan domain.Payout) error {
-	runTransaction := func(payout domain.Payout) error {
-		tx, err := h.DB.Begin()
-		if err != nil {
-			err = fmt.Errorf("failed to create DB transaction: %w", err)
+// persistPayouts persists each payout in a transaction of its own, it stops before the next transaction
+// once ctx is done. The transaction in progress is not cancelled, so that a shutdown does not abort it.
+func (h handler) persistPayouts(ctx context.Context, payoutC <-chan domain.Payout) ([]domain.Payout, error) {
+	txCtx := detached{ctx}
 
-			return err
+	runTransaction := func(p domain.Payout) error {
+		release := h.Pool.acquire()
+		defer release()
+
+		// payout_items rows are created from payout.Lines which hold the conversion details.
+		items := p.Items
+		p.Items = nil
+
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID.String())
 		}
 
-		defer func() {
-			if r := recover(); r != nil {
-				err = tx.Rollback()
+		return h.Repos.Transaction(txCtx, func(tx db.UnitOfWork) error {
+			// the items are locked until commit, those another replica is paying out are skipped.
+			if err := tx.Items().Lock(txCtx, ids); err != nil {
+				if errors.Is(err, db.ErrItemsUnavailable) {
+					return err
+				}
 
-				err = errRecoverFromPanic
+				return fmt.Errorf("%w: %s", db.ErrDB, err)
 			}
-		}()
 
-		if err := tx.Insert(&payout); err != nil {
-			return fmt.Errorf("%w: %s", db.ErrDB, err)
-		}
+			if err := tx.Payouts().Create(txCtx, &p); err != nil {
+				// an item of the payout is already in another payout.
+				if errors.Is(err, db.ErrUniqueViolation) {
+					return fmt.Errorf("%w: %s", db.ErrItemsPaidOut, err)
+				}
 
-		for i := 0; i < len(payout.Items); i++ {
-			payout.Items[i].PaidOut = true
-		}
+				return fmt.Errorf("%w: %s", db.ErrDB, err)
+			}
 
-		if err := tx.Update(payout.Items); err != nil {
-			return fmt.Errorf("%w: %s", db.ErrDB, err)
-		}
+			// the items are paid out only if none of them is already, whatever the lock above.
+			if err := tx.Items().PayOut(txCtx, ids); err != nil {
+				if errors.Is(err, db.ErrItemsPaidOut) {
+					return err
+				}
 
-		if err = tx.Commit(); err != nil {
-			return fmt.Errorf("failed to commit transaction in DB: %w", err)
-		}
+				return fmt.Errorf("%w: %s", db.ErrDB, err)
+			}
+
+			// the event is recorded in the transaction so that it is only published if the payout is.
+			msg, err := outbox.NewMessage(p.SellerID, domain.EventPayoutCreated, webhook.NewPayoutCreated(p))
+			if err != nil {
+				return err
+			}
 
-		return nil
+			if err := tx.Outbox().Add(txCtx, &msg); err != nil {
+				return fmt.Errorf("%w: %s", db.ErrDB, err)
+			}
+
+			return nil
+		})
 	}
 
-	for payout := range payoutC {
-		err := runTransaction(payout)
-		if err != nil {
-			return err
+	var created []domain.Payout
+
+	for p := range payoutC {
+		if err := ctx.Err(); err != nil {
+			return created, err
 		}
-	}
 
-	h.Log.Info("payout successfully persisted")
+		err := runTransaction(p)
+		if errors.Is(err, db.ErrItemsUnavailable) {
+			h.Log.Info(fmt.Sprintf("payout of seller %s skipped: %s", p.SellerID, err))
 
-	return nil
-}
+			continue
+		}
 
-func convertToSellerCurrency(
-	sellerCode, itemCode string,
-	currencies map[string]domain.Currency,
-	price decimal.Decimal) decimal.Decimal {
-	if itemCode == sellerCode {
-		return price
-	}
+		if err != nil {
+			return created, err
+		}
 
-	if sellerCode == currency.USDCode {
-		price = price.Div(currencies[itemCode].USDExchRate)
-	} else {
-		price = price.Div(currencies[itemCode].USDExchRate).Mul(currencies[sellerCode].USDExchRate)
+		// the items are not kept, the run only needs the totals of the payouts.
+		p.Items, p.Lines = nil, nil
+		created = append(created, p)
 	}
 
-	return price
+	h.Log.Info("payout successfully persisted")
+
+	return created, nil
 }
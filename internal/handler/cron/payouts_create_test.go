@@ -1,20 +1,29 @@
 package cron
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/mock"
 	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type handleCaseCreatePayouts struct {
-	h   handler
-	err error
+	h       handler
+	err     error
+	sellers int
+	failed  int
+	payouts int
 }
 
 func TestHandler_CreatePayouts(t *testing.T) {
@@ -26,9 +35,15 @@ func TestHandler_CreatePayouts(t *testing.T) {
 		"fail-db-find-unpaid-items":                payoutsCreateCaseFailDBFindUnpaidOutItems(mc),
 		"fail-db-find-currencies":                  payoutsCreateCaseFailDBFindCurrencies(mc),
 		"fail-db-begin-tx":                         payoutsCreateCaseFailDBBeginTX(mc),
+		"fail-db-lock-items-tx":                    payoutsCreateCaseFailDBLockItemsTX(mc),
+		"skip-items-locked-by-another-replica":     payoutsCreateCaseItemsUnavailable(mc),
 		"fail-db-insert-tx":                        payoutsCreateCaseFailDBInsertTX(mc),
 		"fail-db-update-tx":                        payoutsCreateCaseFailDBUpdateTX(mc),
+		"fail-db-insert-outbox-tx":                 payoutsCreateCaseFailDBInsertOutboxTX(mc),
+		"fail-items-already-paid-out-tx":           payoutsCreateCaseItemsPaidOut(mc),
+		"fail-item-in-another-payout-tx":           payoutsCreateCaseItemInAnotherPayout(mc),
 		"fail-db-commit-tx":                        payoutsCreateCaseFailDBCommitTX(mc),
+		"continue-after-seller-failure":            payoutsCreateCaseContinueAfterSellerFailure(mc),
 		"split-payouts-above-max-price":            payoutsCreateCaseSplitPayoutsAboveMaxPrice(mc),
 		"no-payout-created-if-seller-has-no-items": payoutsCreateCaseNoPayoutCreatedWithoutItems(mc),
 		"success": payoutsCreateCaseOK(mc),
@@ -39,11 +54,17 @@ func TestHandler_CreatePayouts(t *testing.T) {
 		t.Run(tn, func(t *testing.T) {
 			t.Parallel()
 
-			err := tc.h.CreatePayouts()
+			var run domain.JobRun
+			err := tc.h.CreatePayouts(context.Background(), &run)
 
 			if (err != nil) != (tc.err != nil) {
 				assert.Equal(t, err, tc.err)
 			}
+
+			assert.Equal(t, tc.sellers, run.SellersProcessed)
+			assert.Equal(t, tc.failed, run.SellersFailed)
+			assert.Len(t, run.Failures, tc.failed)
+			assert.Equal(t, tc.payouts, run.PayoutsCreated)
 		})
 	}
 }
@@ -55,21 +76,26 @@ func payoutsCreateCaseFailDBCommitTX(mc *gomock.Controller) handleCaseCreatePayo
 	merr := errors.New("mock")
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	mdb.EXPECT().Begin().Return(mdb, nil)
-	mdb.EXPECT().Insert(gomock.Any())
-	mdb.EXPECT().Update(validItems(true))
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), validItemIDs())
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any())
 	mdb.EXPECT().Commit().Return(merr)
 	ml.EXPECT().Error(gomock.Any())
 	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: merr,
+		err:    errAllSellersFailed,
+		failed: 1,
 	}
 }
 
@@ -80,20 +106,161 @@ func payoutsCreateCaseFailDBUpdateTX(mc *gomock.Controller) handleCaseCreatePayo
 	merr := errors.New("mock")
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	mdb.EXPECT().Begin().Return(mdb, nil)
-	mdb.EXPECT().Insert(gomock.Any())
-	mdb.EXPECT().Update(validItems(true)).Return(merr)
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), validItemIDs()).Return(merr)
+	mdb.EXPECT().Rollback()
 	ml.EXPECT().Error(gomock.Any())
 	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: merr,
+		err:    errAllSellersFailed,
+		failed: 1,
+	}
+}
+
+func payoutsCreateCaseItemsPaidOut(mc *gomock.Controller) handleCaseCreatePayouts {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), validItemIDs()).Return(db.ErrItemsPaidOut)
+	mdb.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handleCaseCreatePayouts{
+		h: handler{
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		err:    errAllSellersFailed,
+		failed: 1,
+	}
+}
+
+func payoutsCreateCaseItemInAnotherPayout(mc *gomock.Controller) handleCaseCreatePayouts {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	violation := &db.ConstraintError{Kind: db.ErrUniqueViolation, Table: "payout_items", Err: errors.New("mock")}
+
+	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any()).Return(violation)
+	mdb.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handleCaseCreatePayouts{
+		h: handler{
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		err:    errAllSellersFailed,
+		failed: 1,
+	}
+}
+
+func payoutsCreateCaseFailDBInsertOutboxTX(mc *gomock.Controller) handleCaseCreatePayouts {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	merr := errors.New("mock")
+
+	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.AssignableToTypeOf(&domain.Payout{}))
+	mdb.EXPECT().PayOutItems(gomock.Any(), validItemIDs())
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any()).Return(merr)
+	mdb.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handleCaseCreatePayouts{
+		h: handler{
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		err:    errAllSellersFailed,
+		failed: 1,
+	}
+}
+
+func payoutsCreateCaseFailDBLockItemsTX(mc *gomock.Controller) handleCaseCreatePayouts {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	merr := errors.New("mock")
+
+	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any()).Return(merr)
+	mdb.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handleCaseCreatePayouts{
+		h: handler{
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		err:    errAllSellersFailed,
+		failed: 1,
+	}
+}
+
+func payoutsCreateCaseItemsUnavailable(mc *gomock.Controller) handleCaseCreatePayouts {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any()).Return(db.ErrItemsUnavailable)
+	mdb.EXPECT().Rollback()
+	// the skipped payout, then the persisted and finished logs.
+	ml.EXPECT().Info(gomock.Any()).Times(3)
+
+	return handleCaseCreatePayouts{
+		h: handler{
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		err:     nil,
+		sellers: 1,
 	}
 }
 
@@ -104,19 +271,24 @@ func payoutsCreateCaseFailDBInsertTX(mc *gomock.Controller) handleCaseCreatePayo
 	merr := errors.New("mock")
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	mdb.EXPECT().Begin().Return(mdb, nil)
-	mdb.EXPECT().Insert(gomock.Any()).Return(merr)
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any()).Return(merr)
+	mdb.EXPECT().Rollback()
 	ml.EXPECT().Error(gomock.Any())
 	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: merr,
+		err:    errAllSellersFailed,
+		failed: 1,
 	}
 }
 
@@ -127,18 +299,21 @@ func payoutsCreateCaseFailDBBeginTX(mc *gomock.Controller) handleCaseCreatePayou
 	merr := errors.New("mock")
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	mdb.EXPECT().Begin().Return(nil, merr)
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(nil, merr)
 	ml.EXPECT().Error(gomock.Any())
 	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: merr,
+		err:    errAllSellersFailed,
+		failed: 1,
 	}
 }
 
@@ -149,14 +324,14 @@ func payoutsCreateCaseFailDBFindCurrencies(mc *gomock.Controller) handleCaseCrea
 	merr := errors.New("mock")
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any()).Return(merr)
+	mdb.EXPECT().FindCurrencies(gomock.Any()).Return(nil, merr)
 	ml.EXPECT().Error(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
 		err: merr,
 	}
@@ -169,43 +344,89 @@ func payoutsCreateCaseFailDBFindUnpaidOutItems(mc *gomock.Controller) handleCase
 	merr := errors.New("mock")
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return([]domain.Seller{}, merr)
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	mdb.EXPECT().FindSellersWithUnpaidOutItems(gomock.Any(), db.SellersFilter{Limit: sellersChunk}).Return(db.SellersPage{}, merr)
 	ml.EXPECT().Error(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
 		err: merr,
 	}
 }
 
+func payoutsCreateCaseContinueAfterSellerFailure(mc *gomock.Controller) handleCaseCreatePayouts {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	failing := sellersWithUnpaidOutItems()[0]
+	failing.ID = uuid.Must(uuid.NewV4())
+
+	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, append([]domain.Seller{failing}, sellersWithUnpaidOutItems()...))
+
+	gomock.InOrder(
+		mdb.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("mock")),
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil),
+	)
+	ml.EXPECT().Error(gomock.Any()).Times(2)
+
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), validItemIDs())
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any())
+	mdb.EXPECT().Commit()
+	ml.EXPECT().Info(gomock.Any()).Times(2)
+
+	return handleCaseCreatePayouts{
+		h: handler{
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		err:     nil,
+		sellers: 1,
+		failed:  1,
+		payouts: 1,
+	}
+}
+
 func payoutsCreateCaseSplitPayoutsAboveMaxPrice(mc *gomock.Controller) handleCaseCreatePayouts {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItemsAboveMaxPrice(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	mdb.EXPECT().Begin().Return(mdb, nil)
-	mdb.EXPECT().Insert(gomock.Any())
-	mdb.EXPECT().Update(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItemsAboveMaxPrice())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any())
 	mdb.EXPECT().Commit()
 
-	mdb.EXPECT().Begin().Return(mdb, nil)
-	mdb.EXPECT().Insert(gomock.Any())
-	mdb.EXPECT().Update(gomock.Any())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any())
 	mdb.EXPECT().Commit()
 	ml.EXPECT().Info(gomock.Any())
 	ml.EXPECT().Info(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: nil,
+		err:     nil,
+		sellers: 1,
+		payouts: 2,
 	}
 }
 
@@ -214,16 +435,19 @@ func payoutsCreateCaseNoPayoutCreatedWithoutItems(mc *gomock.Controller) handleC
 	mdb := mock.NewMockDB(mc)
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithoutUnpaidOutitems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	ml.EXPECT().Info(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	// the items of the seller were paid out since the seller was found, the seller has nothing left to pay out.
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithoutUnpaidOutitems())
+	ml.EXPECT().Info(gomock.Any()).Times(2)
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: nil,
+		err:     nil,
+		sellers: 1,
 	}
 }
 
@@ -232,39 +456,31 @@ func payoutsCreateCaseOK(mc *gomock.Controller) handleCaseCreatePayouts {
 	mdb := mock.NewMockDB(mc)
 
 	ml.EXPECT().Info(gomock.Any())
-	mdb.EXPECT().FindSellersWhereItems(map[string]interface{}{"paid_out": false}).Return(sellersWithUnpaidOutItems(), nil)
-	mdb.EXPECT().FindAll(gomock.Any())
-	mdb.EXPECT().Begin().Return(mdb, nil)
-	mdb.EXPECT().Insert(gomock.Any())
-	mdb.EXPECT().Update(validItems(true))
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItems())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any())
+	mdb.EXPECT().PayOutItems(gomock.Any(), validItemIDs())
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any())
 	mdb.EXPECT().Commit()
 	ml.EXPECT().Info(gomock.Any())
 	ml.EXPECT().Info(gomock.Any())
 
 	return handleCaseCreatePayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			DB:    mdb,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		err: nil,
+		err:     nil,
+		sellers: 1,
+		payouts: 1,
 	}
 }
 
-func Test_convertToSellerCurrency(t *testing.T) {
-	currencies := make(map[string]domain.Currency)
-
-	currencies["USD"] = domain.Currency{
-		USDExchRate: decimal.NewFromInt(1),
-	}
-	currencies["EUR"] = domain.Currency{
-		USDExchRate: decimal.NewFromInt(1),
-	}
-
-	got := convertToSellerCurrency("USD", "EUR", currencies, decimal.NewFromInt(1))
-	assert.True(t, got.Equal(decimal.NewFromInt(1)))
-
-	got = convertToSellerCurrency("EUR", "USD", currencies, decimal.NewFromInt(1))
-	assert.True(t, got.Equal(decimal.NewFromInt(1)))
+func validItemIDs() []string {
+	return []string{validItem(false).ID.String()}
 }
 
 func validItems(paidout bool) []domain.Item {
@@ -287,6 +503,21 @@ func validItem(paidout bool) domain.Item {
 	}
 }
 
+// expectUnpaidOut expects the sellers to be streamed in a single chunk, followed by their items.
+func expectUnpaidOut(mdb *mock.MockDB, f db.SellersFilter, sellers []domain.Seller) {
+	page := db.SellersPage{}
+
+	for _, s := range sellers {
+		mdb.EXPECT().FindUnpaidOutItemsPage(gomock.Any(), s.ID.String(), nil, itemsChunk).
+			Return(db.ItemsPage{Items: s.Items}, nil)
+
+		s.Items = nil
+		page.Sellers = append(page.Sellers, s)
+	}
+
+	mdb.EXPECT().FindSellersWithUnpaidOutItems(gomock.Any(), f).Return(page, nil)
+}
+
 func sellersWithUnpaidOutItems() []domain.Seller {
 	mSeller := domain.Seller{
 		CurrencyCode: "USD",
@@ -312,3 +543,165 @@ func sellersWithoutUnpaidOutitems() []domain.Seller {
 
 	return []domain.Seller{mSeller}
 }
+
+func TestHandler_CreatePayoutsDryRun(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	sellerID := "7f3c9a52-0c1e-4f6b-8d2a-5e4b3c2a1f0e"
+
+	ml.EXPECT().Info(gomock.Any()).Times(2)
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{IDs: []string{sellerID}, Limit: sellersChunk}, sellersWithUnpaidOutItemsAboveMaxPrice())
+
+	h := handler{Log: ml, DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+
+	var run domain.JobRun
+	planned, err := h.createPayouts(context.Background(), &run, payouts.Request{SellerIDs: []string{sellerID}, DryRun: true})
+
+	assert.NoError(t, err)
+	require.Len(t, planned, 2)
+	assert.Equal(t, 1, run.SellersProcessed)
+	assert.Equal(t, 2, run.PayoutsCreated)
+
+	// the items are only kept in the lines previewing them.
+	for _, p := range planned {
+		assert.Nil(t, p.Items)
+		require.NotEmpty(t, p.Lines)
+		assert.Equal(t, p.Lines[0].ItemID, p.Lines[0].Item.ID)
+	}
+}
+
+func TestHandler_CreatePayoutsWorkers(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	ml.EXPECT().Info(gomock.Any()).AnyTimes()
+
+	t.Run("all-sellers-paid-out", func(t *testing.T) {
+		mdb := newMemDB(t, 0, 50, 3)
+		h := handler{Log: ml, DB: mdb, Repos: db.NewUnitOfWork(mdb), Pool: newPool(8, 2)}
+
+		var run domain.JobRun
+		err := h.CreatePayouts(context.Background(), &run)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 50, run.SellersProcessed)
+		assert.Equal(t, 100, run.PayoutsCreated)
+		assert.Equal(t, 100, mdb.payouts(t))
+
+		page, _ := mdb.FindSellersWithUnpaidOutItems(context.Background(), db.SellersFilter{})
+		assert.Empty(t, page.Sellers)
+	})
+
+	t.Run("planned-in-sellers-order", func(t *testing.T) {
+		mdb := newMemDB(t, 0, 50, 3)
+		h := handler{Log: ml, DB: mdb, Repos: db.NewUnitOfWork(mdb), Pool: newPool(8, 2)}
+
+		ids := make([]string, 0, len(mdb.sellers))
+		for _, s := range mdb.sellers {
+			ids = append(ids, s.ID.String())
+		}
+
+		var run domain.JobRun
+		planned, err := h.createPayouts(context.Background(), &run, payouts.Request{SellerIDs: ids, DryRun: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, planned, 100)
+
+		for i, p := range planned {
+			assert.Equal(t, mdb.sellers[i/2].ID, p.SellerID)
+		}
+	})
+
+	t.Run("no-seller-started-once-cancelled", func(t *testing.T) {
+		mdb := newMemDB(t, 0, 50, 3)
+		ctx, cancel := context.WithCancel(context.Background())
+		cdb := cancelOnFindCurrencies{memDB: mdb, cancel: cancel}
+		h := handler{Log: ml, DB: cdb, Repos: db.NewUnitOfWork(cdb), Pool: newPool(8, 2)}
+
+		var run domain.JobRun
+		_, err := h.createPayouts(ctx, &run, payouts.Request{})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, run.SellersProcessed)
+		assert.Zero(t, mdb.payouts(t))
+	})
+}
+
+// cancelOnFindCurrencies cancels the run once the currencies are found.
+type cancelOnFindCurrencies struct {
+	memDB
+	cancel context.CancelFunc
+}
+
+func (c cancelOnFindCurrencies) FindCurrencies(ctx context.Context) ([]domain.Currency, error) {
+	defer c.cancel()
+
+	return c.memDB.FindCurrencies(ctx)
+}
+
+func TestHandler_CreatePayoutsCancelled(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the seller has two payouts, the run is cancelled while the first one is persisted.
+	ml.EXPECT().Info(gomock.Any()).Times(3)
+	ml.EXPECT().Error(gomock.Any())
+	mdb.EXPECT().FindCurrencies(gomock.Any())
+	expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithUnpaidOutItemsAboveMaxPrice())
+	mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+	mdb.EXPECT().LockUnpaidItems(gomock.Any(), gomock.Any()).Do(func(context.Context, []string) { cancel() })
+	mdb.EXPECT().InsertPayout(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, _ *domain.Payout) {
+		assert.NoError(t, ctx.Err(), "the transaction in progress should not be cancelled")
+	})
+	mdb.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, _ *domain.OutboxMessage) {
+		assert.NoError(t, ctx.Err(), "the transaction in progress should not be cancelled")
+	})
+	mdb.EXPECT().PayOutItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().Commit()
+
+	h := handler{Log: ml, DB: mdb, Repos: db.NewUnitOfWork(mdb)}
+
+	var run domain.JobRun
+	err := h.CreatePayouts(ctx, &run)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, run.PayoutsCreated)
+	assert.Zero(t, run.SellersProcessed)
+	assert.Zero(t, run.SellersFailed)
+}
+
+func BenchmarkHandler_CreatePayouts(b *testing.B) {
+	mc := gomock.NewController(b)
+	b.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	ml.EXPECT().Info(gomock.Any()).AnyTimes()
+
+	for _, workers := range []int{1, 4, 16} {
+		workers := workers
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mdb := newMemDB(b, 50*time.Microsecond, 100, 3)
+				h := handler{Log: ml, DB: mdb, Repos: db.NewUnitOfWork(mdb), Pool: newPool(workers, workers)}
+				b.StartTimer()
+
+				var run domain.JobRun
+				if err := h.CreatePayouts(context.Background(), &run); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
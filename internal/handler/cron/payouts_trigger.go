@@ -0,0 +1,117 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/logger"
+)
+
+var errManualRun = errors.New("manual payouts run failed")
+
+// payoutCreator triggers the payouts creation job on demand.
+type payoutCreator struct {
+	h      handler
+	runner runner
+}
+
+// runner runs the manual runs in the background until the shutdown, see scheduler.Scheduler.Go.
+type runner interface {
+	Go(run func(ctx context.Context)) error
+}
+
+// started is the outcome of the start of a manual run.
+type started struct {
+	run domain.JobRun
+	err error
+}
+
+// NewPayoutCreator returns a payouts.Creator sharing the lock and the runs history of the scheduled job,
+// the sellers are processed by workers sharing transactions slots as in the scheduled job.
+// The runs are run by runner, so that they are not tied to the request triggering them.
+func NewPayoutCreator(log logger.Logger, database db.DB, workers, transactions int, runner runner) payouts.Creator {
+	h := handler{Log: log, DB: database, Repos: db.NewUnitOfWork(database), Pool: newPool(workers, transactions)}
+
+	return payoutCreator{h: h, runner: runner}
+}
+
+// Create creates the payouts of the request scope, payouts.ErrRunning is returned
+// when the job is running on any replica. A dry run is neither locked nor recorded, it runs with ctx,
+// and is limited to the sellers listed, payouts.ErrDryRunSellers is returned when they are not.
+// Otherwise Create returns once the run is locked and recorded, the run going on in the background
+// until it completes or the runner stops: its outcome is read from the job runs.
+func (c payoutCreator) Create(ctx context.Context, r payouts.Request) (payouts.Result, error) {
+	run := domain.JobRun{
+		Job:       jobCreatePayouts,
+		Trigger:   domain.JobRunTriggerManual,
+		Status:    domain.JobRunRunning,
+		StartedAt: time.Now(),
+	}
+
+	if r.DryRun {
+		if len(r.SellerIDs) == 0 || len(r.SellerIDs) > payouts.MaxDryRunSellers {
+			return payouts.Result{}, payouts.ErrDryRunSellers
+		}
+
+		planned, err := c.h.createPayouts(ctx, &run, r)
+		run.Finish(time.Now(), err)
+
+		return payouts.Result{Run: run, Payouts: planned}, err
+	}
+
+	startC := make(chan started, 1)
+
+	if err := c.runner.Go(func(ctx context.Context) { c.run(ctx, run, r, startC) }); err != nil {
+		return payouts.Result{}, err
+	}
+
+	select {
+	case s := <-startC:
+		return payouts.Result{Run: s.run}, s.err
+	case <-ctx.Done():
+		return payouts.Result{}, ctx.Err()
+	}
+}
+
+// run locks and records the run, sends it on startC once recorded, or the error preventing it, then creates the payouts.
+func (c payoutCreator) run(ctx context.Context, run domain.JobRun, r payouts.Request, startC chan<- started) {
+	ctx, release, err := c.h.lock(ctx, jobCreatePayouts)
+	if errors.Is(err, db.ErrLockHeld) {
+		startC <- started{err: payouts.ErrRunning}
+
+		return
+	}
+
+	if err != nil {
+		startC <- started{err: err}
+
+		return
+	}
+
+	defer release()
+
+	recorded := false
+
+	err = c.h.record(ctx, &run, func(ctx context.Context, run *domain.JobRun) error {
+		recorded = true
+		startC <- started{run: *run}
+
+		_, err := c.h.createPayouts(ctx, run, r)
+
+		return err
+	})
+	if !recorded {
+		startC <- started{err: err}
+
+		return
+	}
+
+	if err != nil {
+		c.h.Log.Error(fmt.Errorf("%w: %s", errManualRun, err))
+	}
+}
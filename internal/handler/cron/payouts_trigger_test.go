@@ -0,0 +1,144 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRunner runs the runs in the background until wait is called.
+type testRunner struct {
+	wg sync.WaitGroup
+}
+
+func (r *testRunner) Go(run func(ctx context.Context)) error {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		run(context.Background())
+	}()
+
+	return nil
+}
+
+func (r *testRunner) wait() {
+	r.wg.Wait()
+}
+
+func TestPayoutCreator_Create(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	t.Run("dry-run-without-lock", func(t *testing.T) {
+		ml := mock.NewMockLogger(mc)
+		mdb := mock.NewMockDB(mc)
+
+		sellers := sellersWithUnpaidOutItems()
+		ids := []string{sellers[0].ID.String()}
+
+		ml.EXPECT().Info(gomock.Any()).Times(2)
+		mdb.EXPECT().FindCurrencies(gomock.Any())
+		expectUnpaidOut(mdb, db.SellersFilter{IDs: ids, Limit: sellersChunk}, sellers)
+
+		res, err := NewPayoutCreator(ml, mdb, 1, 1, nil).Create(context.Background(), payouts.Request{SellerIDs: ids, DryRun: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Payouts, 1)
+		assert.Equal(t, domain.JobRunSucceeded, res.Run.Status)
+		assert.Equal(t, domain.JobRunTriggerManual, res.Run.Trigger)
+	})
+
+	t.Run("fail-dry-run-without-sellers", func(t *testing.T) {
+		_, err := NewPayoutCreator(nil, mock.NewMockDB(mc), 1, 1, nil).Create(context.Background(), payouts.Request{DryRun: true})
+
+		assert.ErrorIs(t, err, payouts.ErrDryRunSellers)
+	})
+
+	t.Run("fail-already-running", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(nil, db.ErrLockHeld)
+
+		_, err := NewPayoutCreator(nil, mdb, 1, 1, &testRunner{}).Create(context.Background(), payouts.Request{})
+
+		assert.ErrorIs(t, err, payouts.ErrRunning)
+	})
+
+	t.Run("fail-db-lock", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(nil, errors.New("mock"))
+
+		_, err := NewPayoutCreator(nil, mdb, 1, 1, &testRunner{}).Create(context.Background(), payouts.Request{})
+
+		assert.ErrorIs(t, err, db.ErrDB)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ml := mock.NewMockLogger(mc)
+		mdb := mock.NewMockDB(mc)
+		mlock := mock.NewMockLock(mc)
+
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(mlock, nil)
+		mlock.EXPECT().Lost().Return(make(chan struct{})).AnyTimes()
+		mlock.EXPECT().Release()
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		ml.EXPECT().Info(gomock.Any()).Times(3)
+		mdb.EXPECT().FindCurrencies(gomock.Any())
+		expectUnpaidOut(mdb, db.SellersFilter{IDs: []string{"a"}, Limit: sellersChunk}, sellersWithoutUnpaidOutitems())
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+
+		finished := make(chan domain.JobRun, 1)
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).Do(func(_ context.Context, r domain.JobRun) { finished <- r })
+		mdb.EXPECT().Commit()
+
+		runner := &testRunner{}
+		res, err := NewPayoutCreator(ml, mdb, 1, 1, runner).Create(context.Background(), payouts.Request{SellerIDs: []string{"a"}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, res.Payouts)
+		assert.Equal(t, domain.JobRunRunning, res.Run.Status)
+		assert.Equal(t, domain.JobRunTriggerManual, res.Run.Trigger)
+
+		runner.wait()
+		assert.Equal(t, domain.JobRunSucceeded, (<-finished).Status)
+	})
+
+	t.Run("run-not-tied-to-request", func(t *testing.T) {
+		ml := mock.NewMockLogger(mc)
+		mdb := mock.NewMockDB(mc)
+		mlock := mock.NewMockLock(mc)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mdb.EXPECT().TryLock(gomock.Any(), jobCreatePayouts).Return(mlock, nil)
+		mlock.EXPECT().Lost().Return(make(chan struct{})).AnyTimes()
+		mlock.EXPECT().Release()
+		mdb.EXPECT().InsertJobRun(gomock.Any(), gomock.Any())
+		// the request is gone once the run started.
+		ml.EXPECT().Info(gomock.Any()).Do(func(...interface{}) { cancel() })
+		ml.EXPECT().Info(gomock.Any()).Times(2)
+		mdb.EXPECT().FindCurrencies(gomock.Any())
+		expectUnpaidOut(mdb, db.SellersFilter{Limit: sellersChunk}, sellersWithoutUnpaidOutitems())
+		mdb.EXPECT().Begin(gomock.Any()).Return(mdb, nil)
+
+		finished := make(chan domain.JobRun, 1)
+		mdb.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).Do(func(_ context.Context, r domain.JobRun) { finished <- r })
+		mdb.EXPECT().Commit()
+
+		runner := &testRunner{}
+		_, err := NewPayoutCreator(ml, mdb, 1, 1, runner).Create(ctx, payouts.Request{})
+		assert.NoError(t, err)
+
+		runner.wait()
+		assert.Equal(t, domain.JobRunSucceeded, (<-finished).Status)
+	})
+}
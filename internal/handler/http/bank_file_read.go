@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var errBankFileNotFound = errors.New("bank file not found")
+
+// bankFileDownloads maps the bank file formats to their content type and file extension.
+var bankFileDownloads = map[domain.BankFileFormat]struct {
+	contentType string
+	extension   string
+}{
+	domain.BankFileFormatSEPA:  {"application/xml", "xml"},
+	domain.BankFileFormatNACHA: {"text/plain", "ach"},
+}
+
+// ReadBankFile method http GET
+// @Summary Endpoint to download a bank file.
+// @Description Download a bank file to upload it to the bank.
+// @Tags BankFile
+// @Produce  application/xml
+// @Produce  text/plain
+// @Param id path string true "Bank file ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /bank-files/{id} [get].
+func (h handler) ReadBankFile(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	f, err := h.Repos.BankFiles().Get(c.Request.Context(), id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errBankFileNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	download := bankFileDownloads[f.Format]
+
+	h.Log.Info(successMessage)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", f.MessageID+"."+download.extension))
+	c.Data(http.StatusOK, download.contentType, f.Content)
+}
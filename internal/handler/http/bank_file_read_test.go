@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadBankFile struct {
+	h           handler
+	fileID      string
+	status      int
+	contentType string
+}
+
+func TestHandler_ReadBankFile(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadBankFile{
+		"fail-invalid-id":    bankFileReadCaseFailInvalidID(mc),
+		"fail-db-not-found":  bankFileReadCaseFailDBNotFound(mc),
+		"fail-db-find-by-id": bankFileReadCaseFailDBFindByID(mc),
+		"success":            bankFileReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/bank-files/"+tc.fileID, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+
+			if tc.contentType != "" && w.Header().Get("Content-Type") != tc.contentType {
+				t.Errorf("Expected content type %s, got %s", tc.contentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+const validBankFileID = "0d6f5b8e-9a4c-4d0e-8f59-bd5d3b6c2a11"
+
+func bankFileReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadBankFile {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadBankFile{
+		h: handler{
+			Log: ml,
+		},
+		fileID: "123",
+		status: http.StatusBadRequest,
+	}
+}
+
+func bankFileReadCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReadBankFile {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindBankFileByID(gomock.Any(), validBankFileID).Return(domain.BankFile{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadBankFile{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		fileID: validBankFileID,
+		status: http.StatusNotFound,
+	}
+}
+
+func bankFileReadCaseFailDBFindByID(mc *gomock.Controller) handlerCaseReadBankFile {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindBankFileByID(gomock.Any(), validBankFileID).Return(domain.BankFile{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadBankFile{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		fileID: validBankFileID,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func bankFileReadCaseOK(mc *gomock.Controller) handlerCaseReadBankFile {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindBankFileByID(gomock.Any(), validBankFileID).Return(domain.BankFile{
+		Format:    domain.BankFileFormatNACHA,
+		MessageID: "0d6f5b8e9a4c4d0e8f59bd5d3b6c2a11",
+		Content:   []byte("101"),
+	}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadBankFile{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		fileID:      validBankFileID,
+		status:      http.StatusOK,
+		contentType: "text/plain",
+	}
+}
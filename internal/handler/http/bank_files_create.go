@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/webhook"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// CreateBankFilesResult lists the bank files generated and the payouts left aside.
+type CreateBankFilesResult struct {
+	Files    []domain.BankFile `json:"files"`
+	Rejected []rejectedPayout  `json:"rejected"`
+}
+
+// rejectedPayout is an approved payout which could not be exported, it stays approved.
+type rejectedPayout struct {
+	PayoutID uuid.UUID `json:"payout_id"`
+	Error    string    `json:"error"`
+}
+
+// CreateBankFiles method http POST
+// @Summary Endpoint to generate bank files out of approved payouts.
+// @Description Export approved payouts to a SEPA pain.001.001.03 file for EUR and a NACHA file for USD.
+// @Description Exported payouts are linked to their file, invalid payouts are reported and stay approved.
+// @Tags BankFile
+// @Produce  json
+// @Success 200 {object} ResponseSuccess
+// @Failure 409 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /bank-files [post].
+func (h handler) CreateBankFiles(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	payouts, err := h.Repos.Payouts().FindByStatus(ctx, domain.PayoutStatusApproved)
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	res, err := bankfile.Generate(h.Bank, payouts, time.Now())
+	if err != nil {
+		outErr(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	out := CreateBankFilesResult{
+		Files:    make([]domain.BankFile, 0, len(res.Files)),
+		Rejected: make([]rejectedPayout, 0, len(res.Rejected)),
+	}
+
+	for _, r := range res.Rejected {
+		h.Log.Error(fmt.Errorf("payout %s not exported: %w", r.PayoutID, r.Err))
+		out.Rejected = append(out.Rejected, rejectedPayout{PayoutID: r.PayoutID, Error: r.Err.Error()})
+	}
+
+	sellers := make(map[uuid.UUID]uuid.UUID, len(payouts))
+	for _, p := range payouts {
+		sellers[p.ID] = p.SellerID
+	}
+
+	for _, f := range res.Files {
+		// files persisted so far are kept, their payouts are no longer approved.
+		if err := h.persistBankFile(ctx, f, sellers); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, db.ErrPayoutsChanged) {
+				status = http.StatusConflict
+			}
+
+			outErr(status, err)
+
+			return
+		}
+
+		out.Files = append(out.Files, f.BankFile)
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{out})
+}
+
+// persistBankFile records a bank file along with the payouts it includes and their status change events,
+// in a single transaction. sellers holds the seller ID of each payout.
+func (h handler) persistBankFile(ctx context.Context, f bankfile.File, sellers map[uuid.UUID]uuid.UUID) error {
+	ids := make([]string, 0, len(f.PayoutIDs))
+	for _, id := range f.PayoutIDs {
+		ids = append(ids, id.String())
+	}
+
+	return h.Repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		if err := tx.BankFiles().Create(ctx, &f.BankFile); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		if err := tx.Payouts().Export(ctx, f.ID.String(), ids); err != nil {
+			if errors.Is(err, db.ErrPayoutsChanged) {
+				return fmt.Errorf("%w: file %s", err, f.MessageID)
+			}
+
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		for _, id := range f.PayoutIDs {
+			err := addEvent(ctx, tx, sellers[id], domain.EventPayoutStatusChanged, webhook.PayoutStatusChanged{
+				PayoutID: id,
+				From:     domain.PayoutStatusApproved,
+				To:       domain.PayoutStatusExported,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/webhook"
+	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+type handlerCaseCreateBankFiles struct {
+	h      handler
+	status int
+}
+
+func TestHandler_CreateBankFiles(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseCreateBankFiles{
+		"fail-db-find-payouts":    bankFilesCreateCaseFailDBFindPayouts(mc),
+		"fail-db-begin":           bankFilesCreateCaseFailDBBegin(mc),
+		"fail-db-insert-file":     bankFilesCreateCaseFailDBInsertFile(mc),
+		"fail-payouts-changed":    bankFilesCreateCaseFailPayoutsChanged(mc),
+		"success-nothing-to-send": bankFilesCreateCaseNothingOK(mc),
+		"success":                 bankFilesCreateCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, createBankFileRoute, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func validOriginator() bankfile.Originator {
+	return bankfile.Originator{
+		Name:          "Seller Payout Ltd",
+		IBAN:          "DE89370400440532013000",
+		BIC:           "COBADEFFXXX",
+		RoutingNumber: "021000021",
+		BankName:      "JPMorgan Chase",
+		CompanyID:     "1234567890",
+	}
+}
+
+func approvedPayouts() []domain.Payout {
+	return []domain.Payout{
+		{
+			ID:         uuid.FromStringOrNil(validPayoutID),
+			SellerID:   uuid.FromStringOrNil(validSellerID),
+			Status:     domain.PayoutStatusApproved,
+			PriceTotal: decimal.NewFromInt(10),
+			Currency:   domain.Currency{Code: currency.EURCode},
+			Seller: domain.Seller{
+				ID:   uuid.FromStringOrNil(validSellerID),
+				Name: "Jane Doe",
+				IBAN: "FR1420041010050500013M02606",
+				BIC:  "BNPAFRPP",
+			},
+		},
+		{
+			ID:         uuid.Must(uuid.NewV4()),
+			Status:     domain.PayoutStatusApproved,
+			PriceTotal: decimal.NewFromInt(10),
+			Currency:   domain.Currency{Code: currency.GBPCode},
+			Seller:     domain.Seller{ID: uuid.FromStringOrNil(validSellerID)},
+		},
+	}
+}
+
+func bankFilesCreateCaseFailDBFindPayouts(mc *gomock.Controller) handlerCaseCreateBankFiles {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusApproved).Return(nil, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateBankFiles{
+		h: handler{
+			Log:  ml,
+			DB:   mdb,
+			Bank: validOriginator(),
+		},
+		status: http.StatusInternalServerError,
+	}
+}
+
+func bankFilesCreateCaseFailDBBegin(mc *gomock.Controller) handlerCaseCreateBankFiles {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusApproved).Return(approvedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(nil, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any()).Times(2)
+
+	return handlerCaseCreateBankFiles{
+		h: handler{
+			Log:  ml,
+			DB:   mdb,
+			Bank: validOriginator(),
+		},
+		status: http.StatusInternalServerError,
+	}
+}
+
+func bankFilesCreateCaseFailDBInsertFile(mc *gomock.Controller) handlerCaseCreateBankFiles {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusApproved).Return(approvedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertBankFile(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any()).Times(2)
+
+	return handlerCaseCreateBankFiles{
+		h: handler{
+			Log:  ml,
+			DB:   mdb,
+			Bank: validOriginator(),
+		},
+		status: http.StatusInternalServerError,
+	}
+}
+
+func bankFilesCreateCaseFailPayoutsChanged(mc *gomock.Controller) handlerCaseCreateBankFiles {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusApproved).Return(approvedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertBankFile(gomock.Any(), gomock.Any())
+	mtx.EXPECT().ExportPayouts(gomock.Any(), gomock.Any(), []string{validPayoutID}).Return(db.ErrPayoutsChanged)
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any()).Times(2)
+
+	return handlerCaseCreateBankFiles{
+		h: handler{
+			Log:  ml,
+			DB:   mdb,
+			Bank: validOriginator(),
+		},
+		status: http.StatusConflict,
+	}
+}
+
+func bankFilesCreateCaseNothingOK(mc *gomock.Controller) handlerCaseCreateBankFiles {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusApproved)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseCreateBankFiles{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		status: http.StatusOK,
+	}
+}
+
+func bankFilesCreateCaseOK(mc *gomock.Controller) handlerCaseCreateBankFiles {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusApproved).Return(approvedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertBankFile(gomock.Any(), gomock.Any()).Do(func(_ context.Context, f *domain.BankFile) {
+		if f.Format != domain.BankFileFormatSEPA || f.PaymentsCount != 1 || len(f.Content) == 0 {
+			panic("unexpected bank file")
+		}
+	})
+	mtx.EXPECT().ExportPayouts(gomock.Any(), gomock.Any(), []string{validPayoutID})
+	mtx.EXPECT().InsertOutboxMessage(gomock.Any(), outboxMessage(validSellerID, domain.EventPayoutStatusChanged,
+		webhook.PayoutStatusChanged{
+			PayoutID: uuid.FromStringOrNil(validPayoutID),
+			From:     domain.PayoutStatusApproved,
+			To:       domain.PayoutStatusExported,
+		}))
+	mtx.EXPECT().Commit()
+	// the GBP payout has no bank file format.
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseCreateBankFiles{
+		h: handler{
+			Log:  ml,
+			DB:   mdb,
+			Bank: validOriginator(),
+		},
+		status: http.StatusOK,
+	}
+}
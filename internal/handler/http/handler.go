@@ -1,13 +1,46 @@
 package http
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/outbox"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/internal/scheduler"
 	"github.com/TestardR/seller-payout/pkg/currency"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/logger"
+	"github.com/gofrs/uuid"
 )
 
 type handler struct {
 	Log logger.Logger
-	DB  db.DB
-	EX  currency.Exchanger
+	// DB is checked by the health endpoint, the data is read and written through Repos.
+	DB db.HealthChecker
+	// Repos persists the data.
+	Repos db.UnitOfWork
+	EX    currency.Exchanger
+	// Bank holds our bank details written in the bank files.
+	Bank bankfile.Originator
+	// Payouts creates payouts on demand.
+	Payouts payouts.Creator
+	// Jobs reports the state of the background jobs.
+	Jobs scheduler.Monitor
+}
+
+// addEvent records an event in the outbox of tx, so that it is only published if the change it is about
+// is committed. The events of a same key, the seller ID, are published in order.
+func addEvent(ctx context.Context, tx db.UnitOfWork, key uuid.UUID, t domain.EventType, data interface{}) error {
+	msg, err := outbox.NewMessage(key, t, data)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Outbox().Add(ctx, &msg); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	return nil
 }
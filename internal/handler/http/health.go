@@ -4,24 +4,28 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/TestardR/seller-payout/internal/domain"
 	"github.com/gin-gonic/gin"
 )
 
 // HealthResp holds the satus response from Health Check.
 type HealthResp struct {
 	Status bool `json:"status"`
+	// Jobs reports the background jobs of the replica, a failing job does not fail the health check.
+	Jobs []domain.JobState `json:"jobs,omitempty"`
 }
 
 // Health method http GET
 // @Summary Health check
 // @Description Healthcheck endpoint, to ensure that the service is running.
+// @Description The state of the background jobs, failures and open circuits, is reported along.
 // @Tags Health
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} HealthResp
 // @Router /health [get].
 func (h handler) Health(c *gin.Context) {
-	if err := h.DB.Health(); err != nil {
+	if err := h.DB.Health(c.Request.Context()); err != nil {
 		err = fmt.Errorf("failed to connect to database: %w", err)
 		h.Log.Error(err)
 		c.JSON(http.StatusInternalServerError, HealthResp{Status: false})
@@ -29,5 +33,10 @@ func (h handler) Health(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, &ResponseSuccess{&HealthResp{true}})
+	resp := HealthResp{Status: true}
+	if h.Jobs != nil {
+		resp.Jobs = h.Jobs.States()
+	}
+
+	c.JSON(http.StatusOK, &ResponseSuccess{&resp})
 }
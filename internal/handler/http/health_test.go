@@ -1,16 +1,23 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/outbox"
+	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/mock"
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandler_Health(t *testing.T) {
@@ -30,7 +37,7 @@ func TestHandler_Health(t *testing.T) {
 	defer ts.Close()
 
 	t.Run("should_be_ok", func(t *testing.T) {
-		mDB.EXPECT().Health().Return(nil)
+		mDB.EXPECT().Health(gomock.Any()).Return(nil)
 		resp, err := http.Get(fmt.Sprintf("%s%s", ts.URL, healthRoute))
 		if err != nil {
 			t.Error(err)
@@ -39,7 +46,7 @@ func TestHandler_Health(t *testing.T) {
 	})
 
 	t.Run("should_return_500", func(t *testing.T) {
-		mDB.EXPECT().Health().Return(errors.New("mock"))
+		mDB.EXPECT().Health(gomock.Any()).Return(errors.New("mock"))
 		mLog.EXPECT().Error(gomock.Any())
 		resp, err := http.Get(fmt.Sprintf("%s%s", ts.URL, healthRoute))
 		if err != nil {
@@ -47,5 +54,48 @@ func TestHandler_Health(t *testing.T) {
 		}
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	})
+}
+
+func TestHandler_HealthReportsJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mDB := mock.NewMockDB(ctrl)
+	mJobs := mock.NewMockMonitor(ctrl)
+
+	mDB.EXPECT().Health(gomock.Any()).Return(nil)
+	mJobs.EXPECT().States().Return([]domain.JobState{{Job: "update-currencies", ConsecutiveFailures: 5, LastError: "timeout"}})
+
+	router := NewServer(gin.TestMode, nil, mDB, bankfile.Originator{}, nil, mJobs)
+	w := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, healthRoute, nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data HealthResp `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Status)
+	require.Len(t, resp.Data.Jobs, 1)
+	assert.Equal(t, "timeout", resp.Data.Jobs[0].LastError)
+}
+
+// outboxMessage returns the outbox message a handler records for an event of the seller key.
+func outboxMessage(key string, t domain.EventType, data interface{}) *domain.OutboxMessage {
+	msg, err := outbox.NewMessage(uuid.FromStringOrNil(key), t, data)
+	if err != nil {
+		panic(err)
+	}
+
+	return &msg
+}
+
+// serverDB returns the database the test case handler reads, nil when it reads none.
+func serverDB(h handler) db.DB {
+	database, _ := h.DB.(db.DB)
 
+	return database
 }
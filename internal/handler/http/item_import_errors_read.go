@@ -0,0 +1,82 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+)
+
+// ItemImportErrorsQuery holds the query parameters accepted to list the rejected rows of an import.
+type ItemImportErrorsQuery struct {
+	// Cursor is the row after which the page starts.
+	Cursor int `form:"cursor" validate:"min=0"`
+	Limit  int `form:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// ReadItemImportErrors method http GET
+// @Summary Endpoint to retrieve the rejected rows of an items import.
+// @Description Read the per-row error report of an items import, ordered by row.
+// @Tags Items
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Import ID"
+// @Param cursor query int false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (default 50, max 1000)"
+// @Success 200 {object} ResponsePage
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /items/imports/{id}/errors [get].
+func (h handler) ReadItemImportErrors(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	var query ItemImportErrorsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindQuery, err))
+
+		return
+	}
+
+	if err := validator.New().Struct(query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultPageLimit
+	}
+
+	job, status, err := h.findItemImport(ctx, c.Param("id"))
+	if err != nil {
+		outErr(status, err)
+
+		return
+	}
+
+	rows, err := h.Repos.ItemImports().FindRejected(ctx, job.ID.String(), query.Cursor, query.Limit)
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	resp := ResponsePage{Data: rows, Total: int64(job.FailedRows)}
+	if len(rows) == query.Limit {
+		resp.NextCursor = strconv.Itoa(rows[len(rows)-1].RowNumber)
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, resp)
+}
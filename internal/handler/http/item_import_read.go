@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var errItemImportNotFound = errors.New("items import not found")
+
+// ReadItemImport method http GET
+// @Summary Endpoint to retrieve an items import.
+// @Description Read the status and row counts of an items import.
+// @Tags Items
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Import ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /items/imports/{id} [get].
+func (h handler) ReadItemImport(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	job, status, err := h.findItemImport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		outErr(status, err)
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{job})
+}
+
+// findItemImport returns the import along with the HTTP status matching the error if any.
+func (h handler) findItemImport(ctx context.Context, id string) (domain.ItemImport, int, error) {
+	if _, err := uuid.FromString(id); err != nil {
+		return domain.ItemImport{}, http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err)
+	}
+
+	job, err := h.Repos.ItemImports().Get(ctx, id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return domain.ItemImport{}, http.StatusNotFound, fmt.Errorf("%w: %s", errItemImportNotFound, id)
+	}
+
+	if err != nil {
+		return domain.ItemImport{}, http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	return job, http.StatusOK, nil
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadItemImport struct {
+	h      handler
+	uri    string
+	status int
+}
+
+func TestHandler_ReadItemImport(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadItemImport{
+		"fail-invalid-id":           itemImportReadCaseFailInvalidID(mc),
+		"fail-db-not-found":         itemImportReadCaseFailDBNotFound(mc),
+		"success":                   itemImportReadCaseOK(mc),
+		"errors-fail-invalid-query": itemImportErrorsReadCaseFailInvalidQuery(mc),
+		"errors-fail-db-find":       itemImportErrorsReadCaseFailDBFind(mc),
+		"errors-success":            itemImportErrorsReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.uri, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+const validImportID = "a8f3d5e2-4c1b-4f7a-9d2e-6b5c4a3f2e10"
+
+func itemImportReadCaseOK(mc *gomock.Controller) handlerCaseReadItemImport {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemImportByID(gomock.Any(), validImportID)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadItemImport{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/items/imports/" + validImportID,
+		status: http.StatusOK,
+	}
+}
+
+func itemImportReadCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReadItemImport {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemImportByID(gomock.Any(), validImportID).Return(domain.ItemImport{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItemImport{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/items/imports/" + validImportID,
+		status: http.StatusNotFound,
+	}
+}
+
+func itemImportReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadItemImport {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItemImport{
+		h: handler{
+			Log: ml,
+		},
+		uri:    "/items/imports/123",
+		status: http.StatusBadRequest,
+	}
+}
+
+func itemImportErrorsReadCaseOK(mc *gomock.Controller) handlerCaseReadItemImport {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemImportByID(gomock.Any(), validImportID)
+	mdb.EXPECT().FindItemImportErrors(gomock.Any(), gomock.Any(), 10, 2).Return([]domain.ItemImportError{{RowNumber: 11}, {RowNumber: 12}}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadItemImport{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/items/imports/" + validImportID + "/errors?cursor=10&limit=2",
+		status: http.StatusOK,
+	}
+}
+
+func itemImportErrorsReadCaseFailDBFind(mc *gomock.Controller) handlerCaseReadItemImport {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemImportByID(gomock.Any(), validImportID)
+	mdb.EXPECT().FindItemImportErrors(gomock.Any(), gomock.Any(), 0, defaultPageLimit).Return(nil, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItemImport{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/items/imports/" + validImportID + "/errors",
+		status: http.StatusInternalServerError,
+	}
+}
+
+func itemImportErrorsReadCaseFailInvalidQuery(mc *gomock.Controller) handlerCaseReadItemImport {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItemImport{
+		h: handler{
+			Log: ml,
+		},
+		uri:    "/items/imports/" + validImportID + "/errors?limit=5000",
+		status: http.StatusBadRequest,
+	}
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var errItemNotFound = errors.New("item not found")
+
+// ReadItem method http GET
+// @Summary Endpoint to retrieve an item.
+// @Description Read an item with its paid out status and payout.
+// @Tags Items
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Item ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /items/{id} [get].
+func (h handler) ReadItem(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	it, err := h.Repos.Items().Get(c.Request.Context(), id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errItemNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{newItemFromInput(it)})
+}
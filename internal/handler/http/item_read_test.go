@@ -0,0 +1,114 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadItem struct {
+	h      handler
+	itemID string
+	status int
+}
+
+func TestHandler_ReadItem(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadItem{
+		"fail-invalid-id":    itemReadCaseFailInvalidID(mc),
+		"fail-db-not-found":  itemReadCaseFailDBNotFound(mc),
+		"fail-db-find-by-id": itemReadCaseFailDBFindByID(mc),
+		"success":            itemReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/items/"+tc.itemID, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+const validItemID = "5c1f0f3e-7a53-4bd4-9e5f-2f1a3c0d9b21"
+
+func itemReadCaseOK(mc *gomock.Controller) handlerCaseReadItem {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemByID(gomock.Any(), validItemID).Return(domain.Item{}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadItem{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		itemID: validItemID,
+		status: http.StatusOK,
+	}
+}
+
+func itemReadCaseFailDBFindByID(mc *gomock.Controller) handlerCaseReadItem {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemByID(gomock.Any(), validItemID).Return(domain.Item{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItem{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		itemID: validItemID,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func itemReadCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReadItem {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItemByID(gomock.Any(), validItemID).Return(domain.Item{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItem{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		itemID: validItemID,
+		status: http.StatusNotFound,
+	}
+}
+
+func itemReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadItem {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItem{
+		h: handler{
+			Log: ml,
+		},
+		itemID: "123",
+		status: http.StatusBadRequest,
+	}
+}
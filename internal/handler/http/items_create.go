@@ -1,11 +1,17 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/webhook"
 	"github.com/TestardR/seller-payout/pkg/currency"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/gin-gonic/gin"
@@ -18,7 +24,10 @@ const (
 	successMessage = "success"
 )
 
-var errMissingPayload = errors.New("there should be at least one item")
+var (
+	errMissingPayload = errors.New("there should be at least one item")
+	errInvalidItems   = errors.New("invalid items")
+)
 
 // CreateItemsRequest is the payload sent on the endpoint.
 type CreateItemsRequest struct {
@@ -28,23 +37,33 @@ type CreateItemsRequest struct {
 // Item is the payload expected out of the CreateItemsRequest.
 type Item struct {
 	Name     string    `json:"name" validate:"required"`
-	Currency string    `required:"true" validate:"eq=GBP|eq=USD|eq=EUR"`
+	Currency string    `json:"currency" validate:"eq=GBP|eq=USD|eq=EUR"`
 	Amount   int64     `json:"amount" validate:"required,min=0"`
 	SellerID uuid.UUID `json:"seller_id" validate:"required"`
 }
 
+// CreateItemsResult is the response data of a partial items creation.
+type CreateItemsResult struct {
+	Items  []domain.Item `json:"items"`
+	Errors []Error       `json:"errors"`
+}
+
 // CreateItems method http POST
 // @Summary Endpoint to send sold items.
-// @Description Create items.
+// @Description Create items. Invalid items are reported one by one with their index, field and code.
+// @Description With partial=true, valid items are created and invalid ones reported alongside.
 // @Tags Items
 // @Accept  json
 // @Produce  json
-// @Param create body handler.CreateItemsRequest true "Find the fields needed to create items using the 'handler' tab below."
+// @Param create body CreateItemsRequest true "Find the fields needed to create items using the 'http' tab below."
+// @Param partial query bool false "Create valid items even if some are invalid"
 // @Success 200 {object} ResponseSuccess
 // @Failure 400 {object} ResponseError
 // @Failure 500 {object} ResponseError
 // @Router /items [post].
 func (h handler) CreateItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	outErr := func(status int, err error) {
 		h.Log.Error(err)
 
@@ -52,97 +71,213 @@ func (h handler) CreateItems(c *gin.Context) {
 		c.JSON(status, newResponseError(err))
 	}
 
-	var input []Item
-	if err := c.BindJSON(&input); err != nil {
+	partial, err := strconv.ParseBool(c.DefaultQuery("partial", "false"))
+	if err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: partial: %s", errBindQuery, err))
+
+		return
+	}
+
+	var raw []json.RawMessage
+	if err := c.ShouldBindJSON(&raw); err != nil {
 		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindJSON, err))
 
 		return
 	}
 
-	if len(input) == 0 {
+	if len(raw) == 0 {
 		outErr(http.StatusBadRequest, errMissingPayload)
 
 		return
 	}
 
-	var req CreateItemsRequest
-	req.Items = input
+	input, itemErrs := decodeItems(raw)
+	if len(itemErrs) > 0 && (!partial || len(input) == 0) {
+		err := fmt.Errorf("%w: %d out of %d", errInvalidItems, len(raw)-len(input), len(raw))
+		h.Log.Error(err)
 
-	if err := validator.New().Struct(req); err != nil {
-		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, ResponseError{Errs: itemErrs})
 
 		return
 	}
 
-	items, err := h.itemsFromInput(req.Items)
+	items, err := h.itemsFromInput(ctx, input)
 	if err != nil {
 		outErr(http.StatusInternalServerError, err)
 
 		return
 	}
 
-	if err := h.DB.Insert(&items); err != nil {
-		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+	err = h.Repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		if err := tx.Items().Create(ctx, items); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		for _, i := range items {
+			if err := addEvent(ctx, tx, i.SellerID, domain.EventItemCreated, webhook.NewItemCreated(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		outErr(http.StatusInternalServerError, err)
 
 		return
 	}
 
 	h.Log.Info(successMessage)
+
+	if partial {
+		c.JSON(http.StatusOK, &ResponseSuccess{CreateItemsResult{Items: items, Errors: itemErrs}})
+
+		return
+	}
+
 	c.JSON(http.StatusOK, &ResponseSuccess{items})
 }
 
-func (h handler) itemsFromInput(input []Item) ([]domain.Item, error) {
-	itemsDB := make([]domain.Item, 0, len(input))
-	sellerMap := make(map[uuid.UUID]domain.Seller)
+// decodeItems decodes and validates each item of the payload on its own,
+// it returns the valid items and an error per invalid field.
+func decodeItems(raw []json.RawMessage) ([]Item, []Error) {
+	validate := newItemValidator()
+	input := make([]Item, 0, len(raw))
+	errs := make([]Error, 0)
+
+	for i := range raw {
+		index := i
 
-	// Note: if seller does not exist, we auto-create sellers with USD as currency for development sake
-	// Not a good practice, in production, would get sellers through API or DB and discard unknown sellers.
-	retrieveOrCreateSeller := func(item Item, sellerMap map[uuid.UUID]domain.Seller) (domain.Seller, error) {
-		// cache seller to avoid unnecessary call.
-		if s, ok := sellerMap[item.SellerID]; ok {
-			return s, nil
+		var in Item
+		if err := json.Unmarshal(raw[i], &in); err != nil {
+			errs = append(errs, Error{Message: err.Error(), Code: codeInvalidJSON, Index: &index})
+
+			continue
 		}
 
-		var seller domain.Seller
+		if err := validate.Struct(in); err != nil {
+			errs = append(errs, validationErrors(err, &index)...)
 
-		err := h.DB.FindByID(&seller, item.SellerID.String())
-		if errors.Is(err, db.ErrRecordNotFound) {
-			s := domain.Seller{ID: item.SellerID, CurrencyCode: currency.USDCode}
-			sellerMap[item.SellerID] = s
+			continue
+		}
 
-			err := h.DB.Insert(&s)
-			if err != nil {
-				return domain.Seller{}, fmt.Errorf("%w: %s", db.ErrDB, err)
-			}
+		input = append(input, in)
+	}
 
-			return sellerMap[item.SellerID], nil
+	return input, errs
+}
+
+// newItemValidator returns a validator reporting fields by their JSON name.
+func newItemValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
 		}
 
-		if err != nil {
-			return domain.Seller{}, fmt.Errorf("%w: %s", db.ErrDB, err)
+		return name
+	})
+
+	return validate
+}
+
+// validationErrors turns the errors of a validator into response errors.
+func validationErrors(err error, index *int) []Error {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []Error{{Message: err.Error(), Code: codeInvalidPayload, Index: index}}
+	}
+
+	errs := make([]Error, 0, len(fieldErrs))
+
+	for _, fe := range fieldErrs {
+		field := fe.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
 		}
 
-		sellerMap[item.SellerID] = seller
+		code := codeInvalidValue
+
+		switch fe.Tag() {
+		case "required":
+			code = codeRequired
+		case "min", "max", "gt", "gte", "lt", "lte":
+			code = codeOutOfRange
+		}
 
-		return sellerMap[item.SellerID], nil
+		// or'ed rules such as eq=GBP|eq=USD already hold their params in the tag.
+		rule := fe.Tag()
+		if fe.Param() != "" && !strings.Contains(rule, "=") {
+			rule += "=" + fe.Param()
+		}
+
+		errs = append(errs, Error{
+			Message: fmt.Sprintf("%s failed on the '%s' rule", field, rule),
+			Code:    code,
+			Field:   field,
+			Index:   index,
+		})
 	}
 
+	return errs
+}
+
+func (h handler) itemsFromInput(ctx context.Context, input []Item) ([]domain.Item, error) {
+	itemsDB := make([]domain.Item, 0, len(input))
+	sellerMap := make(map[uuid.UUID]domain.Seller)
+
 	for _, item := range input {
-		seller, err := retrieveOrCreateSeller(item, sellerMap)
+		seller, err := h.retrieveOrCreateSeller(ctx, item.SellerID, sellerMap)
 		if err != nil {
 			return nil, err
 		}
 
-		itemDB := domain.Item{
-			ReferenceName: item.Name,
-			Seller:        seller,
-			SellerID:      item.SellerID,
-			CurrencyCode:  item.Currency,
-			PriceAmount:   decimal.NewFromInt(item.Amount).Round(domain.PriceDecimals),
-		}
+		itemDB := itemFromInput(item)
+		itemDB.Seller = seller
 
 		itemsDB = append(itemsDB, itemDB)
 	}
 
 	return itemsDB, nil
 }
+
+func itemFromInput(item Item) domain.Item {
+	return domain.Item{
+		ReferenceName: item.Name,
+		SellerID:      item.SellerID,
+		CurrencyCode:  item.Currency,
+		PriceAmount:   decimal.NewFromInt(item.Amount).Round(domain.PriceDecimals),
+	}
+}
+
+// Note: if seller does not exist, we auto-create sellers with USD as currency for development sake
+// Not a good practice, in production, would get sellers through API or DB and discard unknown sellers.
+func (h handler) retrieveOrCreateSeller(ctx context.Context, id uuid.UUID, sellerMap map[uuid.UUID]domain.Seller) (domain.Seller, error) {
+	// cache seller to avoid unnecessary call.
+	if s, ok := sellerMap[id]; ok {
+		return s, nil
+	}
+
+	seller, err := h.Repos.Sellers().Get(ctx, id.String())
+	if errors.Is(err, db.ErrRecordNotFound) {
+		s := domain.Seller{ID: id, CurrencyCode: currency.USDCode}
+		sellerMap[id] = s
+
+		err := h.Repos.Sellers().Create(ctx, &s)
+		if err != nil {
+			return domain.Seller{}, fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		return sellerMap[id], nil
+	}
+
+	if err != nil {
+		return domain.Seller{}, fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	sellerMap[id] = seller
+
+	return sellerMap[id], nil
+}
@@ -2,7 +2,10 @@ package http
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,11 +14,15 @@ import (
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/mock"
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type handlerCaseCreateItems struct {
 	h      handler
+	query  string
 	in     string
 	status int
 }
@@ -28,6 +35,9 @@ func TestHandler_CreateItems(t *testing.T) {
 		"fail-json":                  itemsCreateCaseFailJSON(mc),
 		"fail-empty-payload":         itemsCreateCaseFailEmptyPayload(mc),
 		"fail-validation":            itemsCreateCaseFailValidation(mc),
+		"fail-partial-query":         itemsCreateCaseFailPartialQuery(mc),
+		"fail-partial-none-valid":    itemsCreateCaseFailPartialNoneValid(mc),
+		"partial-success":            itemsCreateCasePartialOK(mc),
 		"fail-db-find-seller-by-id":  itemsCreateCaseFailDBFindSellerByID(mc),
 		"fail-db-insert-items":       itemsCreateCaseFailDBInsertItems(mc),
 		"auto-create-seller-success": itemsCreateCaseAutoCreateSeller(mc),
@@ -37,10 +47,10 @@ func TestHandler_CreateItems(t *testing.T) {
 	for tn, tc := range tests {
 		tn, tc := tn, tc
 		t.Run(tn, func(t *testing.T) {
-			router := NewServer(gin.TestMode, tc.h.Log, tc.h.DB)
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest(http.MethodPost, createItemsRoute, bytes.NewBuffer([]byte(tc.in)))
+			req, _ := http.NewRequest(http.MethodPost, createItemsRoute+tc.query, bytes.NewBuffer([]byte(tc.in)))
 			router.ServeHTTP(w, req)
 
 			if w.Result().StatusCode != tc.status {
@@ -102,11 +112,71 @@ func itemsCreateCaseFailValidation(mc *gomock.Controller) handlerCaseCreateItems
 	}
 }
 
+func itemsCreateCaseFailPartialQuery(mc *gomock.Controller) handlerCaseCreateItems {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateItems{
+		h: handler{
+			Log: ml,
+		},
+		query:  "?partial=maybe",
+		in:     validInputItems(),
+		status: http.StatusBadRequest,
+	}
+}
+
+func itemsCreateCaseFailPartialNoneValid(mc *gomock.Controller) handlerCaseCreateItems {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateItems{
+		h: handler{
+			Log: ml,
+		},
+		query:  "?partial=true",
+		in:     `[{"name": "bag", "amount": -1}]`,
+		status: http.StatusBadRequest,
+	}
+}
+
+func itemsCreateCasePartialOK(mc *gomock.Controller) handlerCaseCreateItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertItems(gomock.Any(), gomock.Any()).Do(func(_ context.Context, items []domain.Item) {
+		if len(items) != 1 {
+			panic("only the valid item should be inserted")
+		}
+	})
+	expectItemCreated(mtx, validSellerID)
+	mtx.EXPECT().Commit()
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseCreateItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		query: "?partial=true",
+		in: `[
+			{"name": "bag", "amount": 1, "currency": "GBP", "seller_id": "` + validSellerID + `"},
+			{"name": "bag", "amount": 1, "currency": "JPY", "seller_id": "` + validSellerID + `"}
+		]`,
+		status: http.StatusOK,
+	}
+}
+
 func itemsCreateCaseFailDBFindSellerByID(mc *gomock.Controller) handlerCaseCreateItems {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().FindByID(&domain.Seller{}, "78dd7916-f276-494b-84a8-83e5bbee8c11").Return(errors.New("mock"))
+	mdb.EXPECT().FindSellerByID(gomock.Any(), "78dd7916-f276-494b-84a8-83e5bbee8c11").Return(domain.Seller{}, errors.New("mock"))
 	ml.EXPECT().Error(gomock.Any())
 
 	return handlerCaseCreateItems{
@@ -122,11 +192,14 @@ func itemsCreateCaseFailDBFindSellerByID(mc *gomock.Controller) handlerCaseCreat
 func itemsCreateCaseFailDBInsertItems(mc *gomock.Controller) handlerCaseCreateItems {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
 
 	mSellerID := "78dd7916-f276-494b-84a8-83e5bbee8c11"
 
-	mdb.EXPECT().FindByID(&domain.Seller{}, mSellerID)
-	mdb.EXPECT().Insert(gomock.Any()).Return(errors.New("mock"))
+	mdb.EXPECT().FindSellerByID(gomock.Any(), mSellerID)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertItems(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+	mtx.EXPECT().Rollback()
 	ml.EXPECT().Error(gomock.Any())
 
 	return handlerCaseCreateItems{
@@ -142,12 +215,18 @@ func itemsCreateCaseFailDBInsertItems(mc *gomock.Controller) handlerCaseCreateIt
 func itemsCreateCaseAutoCreateSeller(mc *gomock.Controller) handlerCaseCreateItems {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
 
 	mSellerID := "78dd7916-f276-494b-84a8-83e5bbee8c11"
 
-	mdb.EXPECT().FindByID(&domain.Seller{}, mSellerID).Return(db.ErrRecordNotFound)
-	mdb.EXPECT().Insert(gomock.Any())
-	mdb.EXPECT().Insert(gomock.Any())
+	mdb.EXPECT().FindSellerByID(gomock.Any(), mSellerID).Return(domain.Seller{}, db.ErrRecordNotFound)
+	mdb.EXPECT().InsertSeller(gomock.Any(), gomock.Any()).Do(func(_ context.Context, s *domain.Seller) {
+		s.ID = uuid.FromStringOrNil(mSellerID)
+	})
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertItems(gomock.Any(), gomock.Any())
+	expectItemCreated(mtx, mSellerID)
+	mtx.EXPECT().Commit()
 	ml.EXPECT().Info(gomock.Any())
 
 	return handlerCaseCreateItems{
@@ -163,11 +242,15 @@ func itemsCreateCaseAutoCreateSeller(mc *gomock.Controller) handlerCaseCreateIte
 func itemsCreateCaseOK(mc *gomock.Controller) handlerCaseCreateItems {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
 
 	mSellerID := "78dd7916-f276-494b-84a8-83e5bbee8c11"
 
-	mdb.EXPECT().FindByID(&domain.Seller{}, mSellerID)
-	mdb.EXPECT().Insert(gomock.Any())
+	mdb.EXPECT().FindSellerByID(gomock.Any(), mSellerID).Return(domain.Seller{ID: uuid.FromStringOrNil(mSellerID)}, nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertItems(gomock.Any(), gomock.Any())
+	expectItemCreated(mtx, mSellerID)
+	mtx.EXPECT().Commit()
 	ml.EXPECT().Info(gomock.Any())
 
 	return handlerCaseCreateItems{
@@ -180,6 +263,15 @@ func itemsCreateCaseOK(mc *gomock.Controller) handlerCaseCreateItems {
 	}
 }
 
+// expectItemCreated expects the item.created event of the seller in the outbox of the transaction.
+func expectItemCreated(mtx *mock.MockDB, sellerID string) {
+	mtx.EXPECT().InsertOutboxMessage(gomock.Any(), gomock.Any()).Do(func(_ context.Context, m *domain.OutboxMessage) {
+		if m.EventType != domain.EventItemCreated || m.Key.String() != sellerID {
+			panic(fmt.Sprintf("unexpected outbox message %+v", m))
+		}
+	})
+}
+
 func validInputItems() string {
 	return `[
 		{
@@ -190,3 +282,36 @@ func validInputItems() string {
 		}
 	]`
 }
+
+func Test_DecodeItems(t *testing.T) {
+	raw := []json.RawMessage{
+		json.RawMessage(`{"name": "bag", "amount": 1, "currency": "GBP", "seller_id": "` + validSellerID + `"}`),
+		json.RawMessage(`{"name": "bag", "amount": -1, "currency": "JPY", "seller_id": "` + validSellerID + `"}`),
+		json.RawMessage(`{"amount": 1, "currency": "GBP", "seller_id": "` + validSellerID + `"}`),
+		json.RawMessage(`{"name": 1}`),
+	}
+
+	input, errs := decodeItems(raw)
+
+	require.Len(t, input, 1)
+	assert.Equal(t, "bag", input[0].Name)
+
+	require.Len(t, errs, 4)
+
+	for i, expected := range []struct {
+		index int
+		field string
+		code  string
+	}{
+		{1, "currency", codeInvalidValue},
+		{1, "amount", codeOutOfRange},
+		{2, "name", codeRequired},
+		{3, "", codeInvalidJSON},
+	} {
+		require.NotNil(t, errs[i].Index)
+		assert.Equal(t, expected.index, *errs[i].Index)
+		assert.Equal(t, expected.field, errs[i].Field)
+		assert.Equal(t, expected.code, errs[i].Code)
+		assert.NotEmpty(t, errs[i].Message)
+	}
+}
@@ -0,0 +1,355 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+)
+
+const (
+	importFormatCSV    = "csv"
+	importFormatNDJSON = "ndjson"
+
+	// importBatchSize is the number of rows inserted at once.
+	importBatchSize = 1000
+	// importMaxLineSize bounds the size of a NDJSON line.
+	importMaxLineSize = 1 << 20
+)
+
+var (
+	errImportFormat  = errors.New("import format should be csv or ndjson")
+	errImportItems   = errors.New("failed to import items")
+	errImportHeader  = errors.New("failed to read CSV header")
+	errImportColumns = errors.New("missing CSV columns")
+)
+
+// importColumns are the CSV columns expected in the header, in any order.
+var importColumns = []string{"name", "amount", "currency", "seller_id"}
+
+// rowError is a recoverable error scoped to one row of an import.
+type rowError struct {
+	msg string
+}
+
+func (e *rowError) Error() string {
+	return e.msg
+}
+
+// rowReader streams the items of an import, it returns io.EOF after the last row.
+type rowReader interface {
+	next() (Item, error)
+}
+
+// ImportItems method http POST
+// @Summary Endpoint to bulk import sold items.
+// @Description Streams a CSV (name,amount,currency,seller_id header) or NDJSON upload,
+// @Description valid rows are inserted by batches and rejected rows reported on the import.
+// @Tags Items
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param format query string false "Upload format (csv or ndjson), defaults to the Content-Type"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /items/imports [post].
+func (h handler) ImportItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	format, err := importFormat(c)
+	if err != nil {
+		outErr(http.StatusBadRequest, err)
+
+		return
+	}
+
+	job := domain.ItemImport{Format: format, Status: domain.ItemImportStatusProcessing}
+	if err := h.Repos.ItemImports().Create(ctx, &job); err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	importErr := h.importItems(ctx, &job, newRowReader(format, c.Request.Body))
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = domain.ItemImportStatusCompleted
+
+	if importErr != nil {
+		job.Status = domain.ItemImportStatusFailed
+		job.Error = importErr.Error()
+	}
+
+	if err := h.Repos.ItemImports().Finish(ctx, job); err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	if importErr != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(importErr, errImportHeader) || errors.Is(importErr, errImportColumns) {
+			status = http.StatusBadRequest
+		}
+
+		outErr(status, fmt.Errorf("%w (import %s): %s", errImportItems, job.ID, importErr))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{job})
+}
+
+// importItems validates and inserts rows by batches, rejected rows are recorded on the job.
+// An error is returned when the import cannot go on, batches inserted so far are kept.
+func (h handler) importItems(ctx context.Context, job *domain.ItemImport, rows rowReader) error {
+	validate := newItemValidator()
+	sellers := make(map[uuid.UUID]domain.Seller)
+	items := make([]domain.Item, 0, importBatchSize)
+	rejected := make([]domain.ItemImportError, 0, importBatchSize)
+
+	flush := func() error {
+		if len(items) > 0 {
+			if err := h.Repos.Items().Create(ctx, items); err != nil {
+				return fmt.Errorf("%w: %s", db.ErrDB, err)
+			}
+
+			job.ImportedRows += len(items)
+			items = items[:0]
+		}
+
+		if len(rejected) > 0 {
+			if err := h.Repos.ItemImports().Reject(ctx, rejected); err != nil {
+				return fmt.Errorf("%w: %s", db.ErrDB, err)
+			}
+
+			rejected = rejected[:0]
+		}
+
+		return nil
+	}
+
+	for {
+		in, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+
+		var rowErr *rowError
+		if err != nil && !errors.As(err, &rowErr) {
+			return err
+		}
+
+		job.TotalRows++
+
+		if err == nil {
+			err = validateRow(validate, in)
+		}
+
+		if err != nil {
+			job.FailedRows++
+			rejected = append(rejected, domain.ItemImportError{
+				ItemImportID: job.ID,
+				RowNumber:    job.TotalRows,
+				Message:      err.Error(),
+			})
+		} else {
+			if _, err := h.retrieveOrCreateSeller(ctx, in.SellerID, sellers); err != nil {
+				return err
+			}
+
+			items = append(items, itemFromInput(in))
+		}
+
+		if len(items) == importBatchSize || len(rejected) == importBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// validateRow reports every invalid field of a row in a single rowError.
+func validateRow(validate *validator.Validate, in Item) error {
+	err := validate.Struct(in)
+	if err == nil {
+		return nil
+	}
+
+	errs := validationErrors(err, nil)
+	msgs := make([]string, 0, len(errs))
+
+	for _, e := range errs {
+		msgs = append(msgs, e.Message)
+	}
+
+	return &rowError{strings.Join(msgs, "; ")}
+}
+
+func importFormat(c *gin.Context) (string, error) {
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+
+		switch mediaType {
+		case "text/csv":
+			format = importFormatCSV
+		case "application/x-ndjson", "application/jsonl":
+			format = importFormatNDJSON
+		}
+	}
+
+	if format != importFormatCSV && format != importFormatNDJSON {
+		return "", errImportFormat
+	}
+
+	return format, nil
+}
+
+func newRowReader(format string, r io.Reader) rowReader {
+	if format == importFormatCSV {
+		cr := csv.NewReader(r)
+		cr.ReuseRecord = true
+		cr.FieldsPerRecord = -1
+
+		return &csvRows{r: cr}
+	}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), importMaxLineSize)
+
+	return &ndjsonRows{s: s}
+}
+
+type csvRows struct {
+	r *csv.Reader
+	// columns maps importColumns to their position in the header.
+	columns map[string]int
+	// width is the minimum number of fields a record needs to hold every importColumns.
+	width int
+}
+
+func (rows *csvRows) next() (Item, error) {
+	if rows.columns == nil {
+		if err := rows.readHeader(); err != nil {
+			return Item{}, err
+		}
+	}
+
+	record, err := rows.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Item{}, &rowError{err.Error()}
+		}
+
+		return Item{}, err
+	}
+
+	if len(record) < rows.width {
+		return Item{}, &rowError{fmt.Sprintf("expected %d fields, got %d", rows.width, len(record))}
+	}
+
+	field := func(column string) string {
+		return strings.TrimSpace(record[rows.columns[column]])
+	}
+
+	in := Item{
+		Name:     field("name"),
+		Currency: field("currency"),
+	}
+
+	if in.Amount, err = strconv.ParseInt(field("amount"), 10, 64); err != nil {
+		return Item{}, &rowError{fmt.Sprintf("invalid amount: %s", err)}
+	}
+
+	if in.SellerID, err = uuid.FromString(field("seller_id")); err != nil {
+		return Item{}, &rowError{fmt.Sprintf("invalid seller_id: %s", err)}
+	}
+
+	return in, nil
+}
+
+func (rows *csvRows) readHeader() error {
+	header, err := rows.r.Read()
+	if errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", errImportHeader, err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		positions[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	rows.columns = make(map[string]int, len(importColumns))
+
+	for _, column := range importColumns {
+		i, ok := positions[column]
+		if !ok {
+			return fmt.Errorf("%w: %s", errImportColumns, column)
+		}
+
+		rows.columns[column] = i
+
+		if i >= rows.width {
+			rows.width = i + 1
+		}
+	}
+
+	return nil
+}
+
+type ndjsonRows struct {
+	s *bufio.Scanner
+}
+
+func (rows *ndjsonRows) next() (Item, error) {
+	for rows.s.Scan() {
+		line := bytes.TrimSpace(rows.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var in Item
+		if err := json.Unmarshal(line, &in); err != nil {
+			return Item{}, &rowError{err.Error()}
+		}
+
+		return in, nil
+	}
+
+	if err := rows.s.Err(); err != nil {
+		return Item{}, err
+	}
+
+	return Item{}, io.EOF
+}
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerCaseImportItems struct {
+	h           handler
+	contentType string
+	in          string
+	status      int
+}
+
+func TestHandler_ImportItems(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseImportItems{
+		"fail-format":          itemsImportCaseFailFormat(mc),
+		"fail-db-insert-job":   itemsImportCaseFailDBInsertJob(mc),
+		"fail-csv-header":      itemsImportCaseFailCSVHeader(mc),
+		"fail-db-insert-items": itemsImportCaseFailDBInsertItems(mc),
+		"success-csv":          itemsImportCaseCSVOK(mc),
+		"success-ndjson":       itemsImportCaseNDJSONOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, importItemsRoute, bytes.NewBufferString(tc.in))
+			req.Header.Set("Content-Type", tc.contentType)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func itemsImportCaseFailFormat(mc *gomock.Controller) handlerCaseImportItems {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseImportItems{
+		h: handler{
+			Log: ml,
+		},
+		contentType: "application/json",
+		in:          "[]",
+		status:      http.StatusBadRequest,
+	}
+}
+
+func itemsImportCaseFailDBInsertJob(mc *gomock.Controller) handlerCaseImportItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertItemImport(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseImportItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          validInputItemsCSV(),
+		status:      http.StatusInternalServerError,
+	}
+}
+
+func itemsImportCaseFailCSVHeader(mc *gomock.Controller) handlerCaseImportItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertItemImport(gomock.Any(), gomock.Any())
+	mdb.EXPECT().FinishItemImport(gomock.Any(), gomock.Any()).Do(func(_ context.Context, job domain.ItemImport) {
+		if job.Status != domain.ItemImportStatusFailed {
+			panic("import should have failed")
+		}
+	})
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseImportItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          "name,amount\nbag,1\n",
+		status:      http.StatusBadRequest,
+	}
+}
+
+func itemsImportCaseFailDBInsertItems(mc *gomock.Controller) handlerCaseImportItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertItemImport(gomock.Any(), gomock.Any())
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().InsertItems(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+	mdb.EXPECT().FinishItemImport(gomock.Any(), gomock.Any())
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseImportItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          validInputItemsCSV(),
+		status:      http.StatusInternalServerError,
+	}
+}
+
+func itemsImportCaseCSVOK(mc *gomock.Controller) handlerCaseImportItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertItemImport(gomock.Any(), gomock.Any())
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().InsertItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().InsertItemImportErrors(gomock.Any(), gomock.Any())
+	mdb.EXPECT().FinishItemImport(gomock.Any(), gomock.Any()).Do(func(_ context.Context, job domain.ItemImport) {
+		if job.Status != domain.ItemImportStatusCompleted || job.ImportedRows != 1 || job.FailedRows != 2 {
+			panic(fmt.Sprintf("unexpected import %+v", job))
+		}
+	})
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseImportItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv; charset=utf-8",
+		in:          validInputItemsCSV() + "bag,-1,GBP," + validSellerID + "\nbag,1,GBP,foo\n",
+		status:      http.StatusOK,
+	}
+}
+
+func itemsImportCaseNDJSONOK(mc *gomock.Controller) handlerCaseImportItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertItemImport(gomock.Any(), gomock.Any())
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().InsertItems(gomock.Any(), gomock.Any())
+	mdb.EXPECT().FinishItemImport(gomock.Any(), gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseImportItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "application/x-ndjson",
+		in:          `{"name":"bag","amount":1,"currency":"GBP","seller_id":"` + validSellerID + `"}` + "\n\n",
+		status:      http.StatusOK,
+	}
+}
+
+func validInputItemsCSV() string {
+	return "seller_id,name,currency,amount\n" + validSellerID + ",bag,GBP,1\n"
+}
+
+func Test_ImportItemsBatches(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+
+	mr := mock.NewMockUnitOfWork(mc)
+	ms := mock.NewMockSellerRepository(mc)
+	mi := mock.NewMockItemRepository(mc)
+	h := handler{Repos: mr}
+
+	var batches []int
+
+	mr.EXPECT().Sellers().Return(ms)
+	ms.EXPECT().Get(gomock.Any(), validSellerID)
+	mr.EXPECT().Items().Return(mi).Times(3)
+	mi.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, items []domain.Item) {
+		batches = append(batches, len(items))
+	}).Times(3)
+
+	var sb strings.Builder
+	sb.WriteString("name,amount,currency,seller_id\n")
+
+	rows := 2*importBatchSize + 1
+	for i := 0; i < rows; i++ {
+		sb.WriteString("bag,1,EUR," + validSellerID + "\n")
+	}
+
+	job := domain.ItemImport{ID: uuid.Must(uuid.NewV4())}
+	err := h.importItems(context.Background(), &job, newRowReader(importFormatCSV, strings.NewReader(sb.String())))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{importBatchSize, importBatchSize, 1}, batches)
+	assert.Equal(t, rows, job.TotalRows)
+	assert.Equal(t, rows, job.ImportedRows)
+}
+
+func Test_RowReaders(t *testing.T) {
+	t.Run("csv_should_report_invalid_rows_and_go_on", func(t *testing.T) {
+		rows := newRowReader(importFormatCSV, strings.NewReader(
+			"name,amount,currency,seller_id,comment\n"+
+				"bag,abc,GBP,"+validSellerID+",\n"+
+				"bag\n"+
+				"bag,2,GBP,"+validSellerID+",ok\n"))
+
+		var rowErr *rowError
+
+		_, err := rows.next()
+		assert.ErrorAs(t, err, &rowErr)
+
+		_, err = rows.next()
+		assert.ErrorAs(t, err, &rowErr)
+
+		in, err := rows.next()
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), in.Amount)
+		assert.Equal(t, validSellerID, in.SellerID.String())
+
+		_, err = rows.next()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("csv_should_fail_on_missing_columns", func(t *testing.T) {
+		_, err := newRowReader(importFormatCSV, strings.NewReader("name,amount\n")).next()
+		assert.ErrorIs(t, err, errImportColumns)
+	})
+
+	t.Run("ndjson_should_report_invalid_rows_and_go_on", func(t *testing.T) {
+		rows := newRowReader(importFormatNDJSON, strings.NewReader("{\n"+`{"name":"bag","amount":3}`))
+
+		var rowErr *rowError
+
+		_, err := rows.next()
+		assert.ErrorAs(t, err, &rowErr)
+
+		in, err := rows.next()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), in.Amount)
+
+		_, err = rows.next()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	itemStatusPaid   = "paid"
+	itemStatusUnpaid = "unpaid"
+)
+
+// ItemsQuery holds the query parameters accepted to list items.
+type ItemsQuery struct {
+	SellerID string    `form:"seller_id" validate:"omitempty,uuid"`
+	Currency string    `form:"currency" validate:"omitempty,oneof=GBP USD EUR"`
+	Status   string    `form:"status" validate:"omitempty,oneof=paid unpaid"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort     string    `form:"sort" validate:"omitempty,oneof=asc desc"`
+	Cursor   string    `form:"cursor"`
+	Limit    int       `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type item struct {
+	ID        uuid.UUID       `json:"id"`
+	Name      string          `json:"name"`
+	Amount    decimal.Decimal `json:"amount"`
+	Currency  string          `json:"currency"`
+	Status    string          `json:"status"`
+	SellerID  uuid.UUID       `json:"seller_id"`
+	PayoutID  *uuid.UUID      `json:"payout_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ReadItems method http GET
+// @Summary Endpoint to list items.
+// @Description Read items with their paid out status and payout.
+// @Tags Items
+// @Accept  json
+// @Produce  json
+// @Param seller_id query string false "Seller ID"
+// @Param currency query string false "Item currency (GBP, USD or EUR)"
+// @Param status query string false "Item status (paid or unpaid)"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created before (RFC3339)"
+// @Param sort query string false "Sort order by creation date (asc or desc)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Success 200 {object} ResponsePage
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /items [get].
+func (h handler) ReadItems(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	var query ItemsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindQuery, err))
+
+		return
+	}
+
+	if err := validator.New().Struct(query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	filter, err := query.filter()
+	if err != nil {
+		outErr(http.StatusBadRequest, err)
+
+		return
+	}
+
+	page, err := h.Repos.Items().Find(c.Request.Context(), filter)
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, newResponsePage(newItemsFromInput(page.Items), page.Next, page.Total))
+}
+
+func (q ItemsQuery) filter() (db.ItemsFilter, error) {
+	f := db.ItemsFilter{
+		SellerID:     q.SellerID,
+		CurrencyCode: q.Currency,
+		From:         q.From,
+		To:           q.To,
+		Desc:         q.Sort == sortDesc,
+		Limit:        q.Limit,
+	}
+
+	if q.Status != "" {
+		paidOut := q.Status == itemStatusPaid
+		f.PaidOut = &paidOut
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultPageLimit
+	}
+
+	if q.Cursor != "" {
+		after, err := db.DecodeCursor(q.Cursor)
+		if err != nil {
+			return db.ItemsFilter{}, err
+		}
+
+		f.After = &after
+	}
+
+	return f, nil
+}
+
+func newItemFromInput(dbItem domain.Item) item {
+	it := item{
+		ID:        dbItem.ID,
+		Name:      dbItem.ReferenceName,
+		Amount:    dbItem.PriceAmount,
+		Currency:  dbItem.CurrencyCode,
+		Status:    itemStatusUnpaid,
+		SellerID:  dbItem.SellerID,
+		CreatedAt: dbItem.CreatedAt,
+		UpdatedAt: dbItem.UpdatedAt,
+	}
+
+	if dbItem.PaidOut {
+		it.Status = itemStatusPaid
+	}
+
+	if dbItem.PayoutItem != nil {
+		payoutID := dbItem.PayoutItem.PayoutID
+		it.PayoutID = &payoutID
+	}
+
+	return it
+}
+
+func newItemsFromInput(dbItems []domain.Item) []item {
+	output := make([]item, 0, len(dbItems))
+
+	for _, dbItem := range dbItems {
+		output = append(output, newItemFromInput(dbItem))
+	}
+
+	return output
+}
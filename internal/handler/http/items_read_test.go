@@ -0,0 +1,140 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerCaseReadItems struct {
+	h      handler
+	query  string
+	status int
+}
+
+func TestHandler_ReadItems(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadItems{
+		"fail-invalid-query":  itemsReadCaseFailInvalidQuery(mc),
+		"fail-invalid-cursor": itemsReadCaseFailInvalidCursor(mc),
+		"fail-db-find-items":  itemsReadCaseFailDBFindItems(mc),
+		"success":             itemsReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, readItemsRoute+tc.query, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func itemsReadCaseOK(mc *gomock.Controller) handlerCaseReadItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	paidOut := false
+	mdb.EXPECT().FindItems(gomock.Any(), db.ItemsFilter{
+		SellerID:     validSellerID,
+		CurrencyCode: "EUR",
+		PaidOut:      &paidOut,
+		Limit:        defaultPageLimit,
+	}).Return(db.ItemsPage{}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		query:  "?seller_id=" + validSellerID + "&currency=EUR&status=unpaid",
+		status: http.StatusOK,
+	}
+}
+
+func itemsReadCaseFailDBFindItems(mc *gomock.Controller) handlerCaseReadItems {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindItems(gomock.Any(), gomock.Any()).Return(db.ItemsPage{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItems{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		status: http.StatusInternalServerError,
+	}
+}
+
+func itemsReadCaseFailInvalidCursor(mc *gomock.Controller) handlerCaseReadItems {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItems{
+		h: handler{
+			Log: ml,
+		},
+		query:  "?cursor=foo",
+		status: http.StatusBadRequest,
+	}
+}
+
+func itemsReadCaseFailInvalidQuery(mc *gomock.Controller) handlerCaseReadItems {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadItems{
+		h: handler{
+			Log: ml,
+		},
+		query:  "?status=lost",
+		status: http.StatusBadRequest,
+	}
+}
+
+func Test_NewItemFromInput(t *testing.T) {
+	payoutID := uuid.Must(uuid.NewV4())
+
+	got := newItemFromInput(domain.Item{
+		ReferenceName: "bag",
+		PriceAmount:   decimal.NewFromInt(1),
+		CreatedAt:     time.Date(2022, 2, 14, 0, 0, 0, 0, time.UTC),
+		PaidOut:       true,
+		PayoutItem:    &domain.PayoutItem{PayoutID: payoutID},
+	})
+
+	assert.Equal(t, itemStatusPaid, got.Status)
+	require.NotNil(t, got.PayoutID)
+	assert.Equal(t, payoutID, *got.PayoutID)
+
+	got = newItemFromInput(domain.Item{ReferenceName: "bag"})
+
+	assert.Equal(t, itemStatusUnpaid, got.Status)
+	assert.Nil(t, got.PayoutID)
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var errJobRunNotFound = errors.New("job run not found")
+
+// ReadJobRun method http GET
+// @Summary Endpoint to retrieve a run of a background job.
+// @Description Read a job run with its outcome, duration, counts and amounts paid out per currency.
+// @Tags Admin
+// @Produce  json
+// @Param id path string true "Job run ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /admin/jobs/{id} [get].
+func (h handler) ReadJobRun(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	run, err := h.Repos.JobRuns().Get(c.Request.Context(), id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errJobRunNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{run})
+}
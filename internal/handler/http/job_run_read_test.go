@@ -0,0 +1,114 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadJobRun struct {
+	h      handler
+	id     string
+	status int
+}
+
+func TestHandler_ReadJobRun(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadJobRun{
+		"fail-invalid-id":   jobRunReadCaseFailInvalidID(mc),
+		"fail-db-not-found": jobRunReadCaseFailDBNotFound(mc),
+		"fail-db-find":      jobRunReadCaseFailDBFind(mc),
+		"success":           jobRunReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/admin/jobs/"+tc.id, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+const validJobRunID = "8c2e4f6a-1b3d-4c5e-9f7a-2b4d6e8f0a1c"
+
+func jobRunReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadJobRun {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadJobRun{
+		h: handler{
+			Log: ml,
+		},
+		id:     "123",
+		status: http.StatusBadRequest,
+	}
+}
+
+func jobRunReadCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReadJobRun {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindJobRunByID(gomock.Any(), validJobRunID).Return(domain.JobRun{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadJobRun{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validJobRunID,
+		status: http.StatusNotFound,
+	}
+}
+
+func jobRunReadCaseFailDBFind(mc *gomock.Controller) handlerCaseReadJobRun {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindJobRunByID(gomock.Any(), validJobRunID).Return(domain.JobRun{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadJobRun{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validJobRunID,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func jobRunReadCaseOK(mc *gomock.Controller) handlerCaseReadJobRun {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindJobRunByID(gomock.Any(), validJobRunID)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadJobRun{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validJobRunID,
+		status: http.StatusOK,
+	}
+}
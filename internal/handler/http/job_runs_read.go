@@ -0,0 +1,105 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+)
+
+const sortAsc = "asc"
+
+// JobRunsQuery holds the query parameters accepted to list job runs.
+type JobRunsQuery struct {
+	Job    string `form:"job"`
+	Status string `form:"status" validate:"omitempty,oneof=running succeeded partial failed"`
+	Sort   string `form:"sort" validate:"omitempty,oneof=asc desc"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// ReadJobRuns method http GET
+// @Summary Endpoint to list the runs of the background jobs.
+// @Description Read the history of the background job runs with their outcome, duration and counts.
+// @Tags Admin
+// @Produce  json
+// @Param job query string false "Job name, e.g. create-payouts"
+// @Param status query string false "Run status (running, succeeded, partial or failed)"
+// @Param sort query string false "Sort order by start date (asc or desc, default desc)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Success 200 {object} ResponsePage
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /admin/jobs [get].
+func (h handler) ReadJobRuns(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	var query JobRunsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindQuery, err))
+
+		return
+	}
+
+	if err := validator.New().Struct(query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	filter, err := query.filter()
+	if err != nil {
+		outErr(http.StatusBadRequest, err)
+
+		return
+	}
+
+	page, err := h.Repos.JobRuns().Find(c.Request.Context(), filter)
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	runs := page.Runs
+	if runs == nil {
+		runs = []domain.JobRun{}
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, newResponsePage(runs, page.Next, page.Total))
+}
+
+// filter builds the listing filter, the most recent runs come first unless asked otherwise.
+func (q JobRunsQuery) filter() (db.JobRunsFilter, error) {
+	f := db.JobRunsFilter{
+		Job:    q.Job,
+		Status: domain.JobRunStatus(q.Status),
+		Desc:   q.Sort != sortAsc,
+		Limit:  q.Limit,
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultPageLimit
+	}
+
+	if q.Cursor != "" {
+		after, err := db.DecodeCursor(q.Cursor)
+		if err != nil {
+			return db.JobRunsFilter{}, err
+		}
+
+		f.After = &after
+	}
+
+	return f, nil
+}
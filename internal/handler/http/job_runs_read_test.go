@@ -0,0 +1,130 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadJobRuns struct {
+	h      handler
+	query  string
+	status int
+}
+
+func TestHandler_ReadJobRuns(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadJobRuns{
+		"fail-invalid-query":  jobRunsReadCaseFailInvalidQuery(mc),
+		"fail-invalid-cursor": jobRunsReadCaseFailInvalidCursor(mc),
+		"fail-db-find-runs":   jobRunsReadCaseFailDBFindRuns(mc),
+		"success":             jobRunsReadCaseOK(mc),
+		"success-sort-asc":    jobRunsReadCaseSortAsc(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, readJobRunsRoute+tc.query, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func jobRunsReadCaseFailInvalidQuery(mc *gomock.Controller) handlerCaseReadJobRuns {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadJobRuns{
+		h: handler{
+			Log: ml,
+		},
+		query:  "?status=pending",
+		status: http.StatusBadRequest,
+	}
+}
+
+func jobRunsReadCaseFailInvalidCursor(mc *gomock.Controller) handlerCaseReadJobRuns {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadJobRuns{
+		h: handler{
+			Log: ml,
+		},
+		query:  "?cursor=abc",
+		status: http.StatusBadRequest,
+	}
+}
+
+func jobRunsReadCaseFailDBFindRuns(mc *gomock.Controller) handlerCaseReadJobRuns {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindJobRuns(gomock.Any(), gomock.Any()).Return(db.JobRunsPage{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadJobRuns{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		status: http.StatusInternalServerError,
+	}
+}
+
+func jobRunsReadCaseOK(mc *gomock.Controller) handlerCaseReadJobRuns {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindJobRuns(gomock.Any(), db.JobRunsFilter{
+		Job:    "create-payouts",
+		Status: "failed",
+		Desc:   true,
+		Limit:  defaultPageLimit,
+	}).Return(db.JobRunsPage{}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadJobRuns{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		query:  "?job=create-payouts&status=failed",
+		status: http.StatusOK,
+	}
+}
+
+func jobRunsReadCaseSortAsc(mc *gomock.Controller) handlerCaseReadJobRuns {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindJobRuns(gomock.Any(), db.JobRunsFilter{Limit: 10}).Return(db.JobRunsPage{}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadJobRuns{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		query:  "?sort=asc&limit=10",
+		status: http.StatusOK,
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/webhook"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var errPayoutStatus = errors.New("payout status does not allow this operation")
+
+// ApprovePayout method http POST
+// @Summary Endpoint to approve a payout.
+// @Description Approve a created payout so that it is exported in the next bank file.
+// @Tags Payout
+// @Produce  json
+// @Param id path string true "Payout ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 409 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /payouts/{id}/approve [post].
+func (h handler) ApprovePayout(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	p, err := h.Repos.Payouts().Get(ctx, id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errPayoutNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	err = h.Repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		// the status is checked again on update in case the payout changed meanwhile.
+		err := tx.Payouts().UpdateStatus(ctx, id, domain.PayoutStatusCreated, domain.PayoutStatusApproved)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return err
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		return addEvent(ctx, tx, p.SellerID, domain.EventPayoutStatusChanged, webhook.PayoutStatusChanged{
+			PayoutID: p.ID,
+			From:     domain.PayoutStatusCreated,
+			To:       domain.PayoutStatusApproved,
+		})
+	})
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusConflict, fmt.Errorf("%w: payout is %s", errPayoutStatus, p.Status))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	p.Status = domain.PayoutStatusApproved
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{newPayoutFromInput(p)})
+}
@@ -0,0 +1,218 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/webhook"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerCaseApprovePayout struct {
+	h        handler
+	payoutID string
+	status   int
+}
+
+func TestHandler_ApprovePayout(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseApprovePayout{
+		"fail-invalid-id":       payoutApproveCaseFailInvalidID(mc),
+		"fail-db-not-found":     payoutApproveCaseFailDBNotFound(mc),
+		"fail-status-conflict":  payoutApproveCaseFailStatusConflict(mc),
+		"fail-db-update-status": payoutApproveCaseFailDBUpdateStatus(mc),
+		"success":               payoutApproveCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/payouts/"+tc.payoutID+"/approve", nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+// TestHandler_ApprovePayoutState approves a payout of the memory DB, a payout is approved once.
+func TestHandler_ApprovePayoutState(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ctx := context.Background()
+	mdb := db.NewMemory()
+
+	currencies, err := mdb.FindCurrencies(ctx)
+	require.NoError(t, err)
+
+	var usd domain.Currency
+
+	for _, c := range currencies {
+		if c.Code == "USD" {
+			usd = c
+		}
+	}
+
+	s := domain.Seller{CurrencyCode: "USD"}
+	require.NoError(t, mdb.InsertSeller(ctx, &s))
+
+	p := domain.Payout{SellerID: s.ID, CurrencyID: usd.ID, PriceTotal: decimal.NewFromInt(10)}
+	require.NoError(t, mdb.InsertPayout(ctx, &p))
+
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Info(gomock.Any())
+	ml.EXPECT().Error(gomock.Any())
+
+	router := NewServer(gin.TestMode, ml, mdb, bankfile.Originator{}, nil, nil)
+
+	approve := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/payouts/"+p.ID.String()+"/approve", nil)
+		router.ServeHTTP(w, req)
+
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, approve())
+	assert.Equal(t, http.StatusConflict, approve())
+
+	got, err := mdb.FindPayoutByID(ctx, p.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.PayoutStatusApproved, got.Status)
+
+	// the status change is recorded once, along with the approval.
+	messages, err := mdb.FindUnpublishedOutboxMessages(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, domain.EventPayoutStatusChanged, messages[0].EventType)
+	assert.Equal(t, s.ID, messages[0].Key)
+}
+
+func payoutApproveCaseFailInvalidID(mc *gomock.Controller) handlerCaseApprovePayout {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseApprovePayout{
+		h: handler{
+			Log: ml,
+		},
+		payoutID: "123",
+		status:   http.StatusBadRequest,
+	}
+}
+
+func payoutApproveCaseFailDBNotFound(mc *gomock.Controller) handlerCaseApprovePayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID).Return(domain.Payout{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseApprovePayout{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusNotFound,
+	}
+}
+
+func payoutApproveCaseFailStatusConflict(mc *gomock.Controller) handlerCaseApprovePayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID).Return(domain.Payout{Status: domain.PayoutStatusExported}, nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().UpdatePayoutStatus(gomock.Any(), validPayoutID, domain.PayoutStatusCreated, domain.PayoutStatusApproved).
+		Return(db.ErrRecordNotFound)
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseApprovePayout{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusConflict,
+	}
+}
+
+func payoutApproveCaseFailDBUpdateStatus(mc *gomock.Controller) handlerCaseApprovePayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().UpdatePayoutStatus(gomock.Any(), validPayoutID, domain.PayoutStatusCreated, domain.PayoutStatusApproved).
+		Return(errors.New("mock"))
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseApprovePayout{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusInternalServerError,
+	}
+}
+
+func payoutApproveCaseOK(mc *gomock.Controller) handlerCaseApprovePayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	p := domain.Payout{
+		ID:       uuid.FromStringOrNil(validPayoutID),
+		SellerID: uuid.FromStringOrNil(validSellerID),
+		Status:   domain.PayoutStatusCreated,
+	}
+
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID).Return(p, nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().UpdatePayoutStatus(gomock.Any(), validPayoutID, domain.PayoutStatusCreated, domain.PayoutStatusApproved)
+	mtx.EXPECT().InsertOutboxMessage(gomock.Any(), outboxMessage(validSellerID, domain.EventPayoutStatusChanged,
+		webhook.PayoutStatusChanged{
+			PayoutID: p.ID,
+			From:     domain.PayoutStatusCreated,
+			To:       domain.PayoutStatusApproved,
+		}))
+	mtx.EXPECT().Commit()
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseApprovePayout{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusOK,
+	}
+}
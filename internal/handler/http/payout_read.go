@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// legacyPayoutsLimit is the number of payouts listed by the former GET /payouts/:seller_id.
+const legacyPayoutsLimit = 200
+
+var errPayoutNotFound = errors.New("payout not found")
+
+// ReadPayout method http GET
+// @Summary Endpoint to retrieve a payout.
+// @Description Read a payout with its items, original amounts and conversions.
+// @Description Deprecated: the path used to take a seller ID, which still lists the latest 200 payouts of the seller
+// @Description with a Link header to the following ones. Use /payouts?seller_id= instead.
+// @Tags Payout
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Payout ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /payouts/{id} [get].
+func (h handler) ReadPayout(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// the path used to take a seller ID: it is resolved first, once, so that the
+	// former clients keep their listing and a payout is read with a single lookup more.
+	_, err := h.Repos.Sellers().Get(ctx, id)
+	if err == nil {
+		h.readSellerPayouts(c, id, outErr)
+
+		return
+	}
+
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	p, err := h.Repos.Payouts().Get(ctx, id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errPayoutNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{newPayoutFromInput(p)})
+}
+
+// readSellerPayouts serves the former GET /payouts/:seller_id for the clients which did not move
+// to GET /payouts?seller_id= yet. Only the latest legacyPayoutsLimit payouts are listed, the Link
+// header points to the listing and, when there are older payouts, to the page following them.
+func (h handler) readSellerPayouts(c *gin.Context, sellerID string, outErr func(status int, err error)) {
+	page, err := h.Repos.Payouts().FindBySeller(c.Request.Context(), sellerID,
+		db.PayoutsFilter{Desc: true, Limit: legacyPayoutsLimit})
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	successor := fmt.Sprintf("%s?seller_id=%s&sort=%s", readPayoutsRoute, sellerID, sortDesc)
+	links := fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)
+
+	if page.Next != nil {
+		links += fmt.Sprintf(", <%s&limit=%d&cursor=%s>; rel=\"next\"", successor, legacyPayoutsLimit, page.Next.Encode())
+	}
+
+	c.Header("Deprecation", "true")
+	c.Header("Link", links)
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{newPayoutsFromInput(page.Payouts)})
+}
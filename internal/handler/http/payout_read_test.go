@@ -0,0 +1,171 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerCaseReadPayout struct {
+	h        handler
+	payoutID string
+	status   int
+	// deprecated is whether the former seller payouts listing answered.
+	deprecated bool
+}
+
+func TestHandler_ReadPayout(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadPayout{
+		"fail-invalid-id":    payoutReadCaseFailInvalidID(mc),
+		"fail-db-not-found":  payoutReadCaseFailDBNotFound(mc),
+		"fail-db-find-by-id": payoutReadCaseFailDBFindByID(mc),
+		"fail-db-seller":     payoutReadCaseFailDBSeller(mc),
+		"success":            payoutReadCaseOK(mc),
+		"success-seller-id":  payoutReadCaseSellerID(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, router := gin.CreateTestContext(w)
+
+			router.GET(readPayoutRoute, tc.h.ReadPayout)
+
+			uri := fmt.Sprintf("/payouts/%s", tc.payoutID)
+
+			var err error
+			ctx.Request, err = http.NewRequest("GET", uri, nil)
+			require.NoError(t, err)
+
+			router.ServeHTTP(w, ctx.Request)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+
+			if deprecated := w.Header().Get("Deprecation") != ""; deprecated != tc.deprecated {
+				t.Errorf("Expected deprecated %t, got %t", tc.deprecated, deprecated)
+			}
+		})
+	}
+}
+
+const validPayoutID = "0f3bd0b6-1d0f-4a4e-8a59-3c8a1b8c3e01"
+
+func payoutReadCaseOK(mc *gomock.Controller) handlerCaseReadPayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validPayoutID).Return(domain.Seller{}, db.ErrRecordNotFound)
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID).Return(domain.Payout{}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadPayout{
+		h: handler{
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusOK,
+	}
+}
+
+func payoutReadCaseSellerID(mc *gomock.Controller) handlerCaseReadPayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, db.PayoutsFilter{Desc: true, Limit: legacyPayoutsLimit}).
+		Return(db.PayoutsPage{Payouts: []domain.Payout{{}}, Next: &db.Cursor{}}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadPayout{
+		h: handler{
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		payoutID:   validSellerID,
+		status:     http.StatusOK,
+		deprecated: true,
+	}
+}
+
+func payoutReadCaseFailDBFindByID(mc *gomock.Controller) handlerCaseReadPayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validPayoutID).Return(domain.Seller{}, db.ErrRecordNotFound)
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID).Return(domain.Payout{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayout{
+		h: handler{
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusInternalServerError,
+	}
+}
+
+func payoutReadCaseFailDBSeller(mc *gomock.Controller) handlerCaseReadPayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validPayoutID).Return(domain.Seller{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayout{
+		h: handler{
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusInternalServerError,
+	}
+}
+
+func payoutReadCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReadPayout {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validPayoutID).Return(domain.Seller{}, db.ErrRecordNotFound)
+	mdb.EXPECT().FindPayoutByID(gomock.Any(), validPayoutID).Return(domain.Payout{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayout{
+		h: handler{
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
+		},
+		payoutID: validPayoutID,
+		status:   http.StatusNotFound,
+	}
+}
+
+func payoutReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadPayout {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayout{
+		h: handler{
+			Log: ml,
+		},
+		payoutID: "123",
+		status:   http.StatusBadRequest,
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayoutRun is a payouts creation triggered on demand.
+type PayoutRun struct {
+	// SellerIDs restricts the run to some sellers, all sellers are paid out when empty.
+	SellerIDs []string `json:"seller_ids" validate:"omitempty,dive,uuid"`
+	// DryRun returns the payouts which would be created without creating them, for the sellers listed only.
+	DryRun bool `json:"dry_run"`
+}
+
+// PayoutRunResult is the run of the payouts creation, along with the payouts computed on a dry run.
+type PayoutRunResult struct {
+	Run     domain.JobRun   `json:"run"`
+	Payouts []plannedPayout `json:"payouts,omitempty"`
+}
+
+// plannedPayout is a payout which would be created by a run.
+type plannedPayout struct {
+	SellerID uuid.UUID       `json:"seller_id"`
+	Price    decimal.Decimal `json:"price"`
+	Currency string          `json:"currency"`
+	Items    []payoutItem    `json:"items"`
+}
+
+// CreatePayoutRun method http POST
+// @Summary Endpoint to create payouts on demand.
+// @Description Run the payouts creation now, for all sellers or the ones listed.
+// @Description With dry_run the payouts are computed and returned, but not created,
+// @Description for the sellers listed in seller_ids only, which is then required, 100 at most.
+// @Description Otherwise the run goes on in the background and is returned once started, to be polled on /admin/jobs/:id.
+// @Tags Admin
+// @Accept  json
+// @Produce  json
+// @Param create body PayoutRun true "Find the fields needed to run the payouts creation using the 'http' tab below."
+// @Success 200 {object} ResponseSuccess
+// @Success 202 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 409 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /admin/payout-runs [post].
+func (h handler) CreatePayoutRun(c *gin.Context) {
+	var input PayoutRun
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindJSON, err))
+
+		return
+	}
+
+	if err := validator.New().Struct(input); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	res, err := h.Payouts.Create(c.Request.Context(), payouts.Request{SellerIDs: input.SellerIDs, DryRun: input.DryRun})
+	if errors.Is(err, payouts.ErrDryRunSellers) {
+		outErr(http.StatusBadRequest, err)
+
+		return
+	}
+
+	if errors.Is(err, payouts.ErrRunning) {
+		outErr(http.StatusConflict, err)
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+
+	// the run is not over, it is read from the job runs until it is.
+	if !input.DryRun {
+		c.JSON(http.StatusAccepted, &ResponseSuccess{PayoutRunResult{Run: res.Run}})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, &ResponseSuccess{PayoutRunResult{
+		Run:     res.Run,
+		Payouts: newPlannedPayoutsFromInput(res.Payouts),
+	}})
+}
+
+func newPlannedPayoutsFromInput(ps []domain.Payout) []plannedPayout {
+	if len(ps) == 0 {
+		return nil
+	}
+
+	output := make([]plannedPayout, 0, len(ps))
+
+	for _, p := range ps {
+		output = append(output, plannedPayout{
+			SellerID: p.SellerID,
+			Price:    p.PriceTotal,
+			Currency: p.Seller.CurrencyCode,
+			Items:    newPayoutItemsFromInput(p.Lines),
+		})
+	}
+
+	return output
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerCaseCreatePayoutRun struct {
+	h      handler
+	in     string
+	status int
+}
+
+func TestHandler_CreatePayoutRun(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseCreatePayoutRun{
+		"fail-json":              payoutRunCreateCaseFailJSON(mc),
+		"fail-invalid-seller-id": payoutRunCreateCaseFailValidation(mc),
+		"fail-dry-run-sellers":   payoutRunCreateCaseFailDryRunSellers(mc),
+		"fail-already-running":   payoutRunCreateCaseFailRunning(mc),
+		"fail-create-payouts":    payoutRunCreateCaseFailCreate(mc),
+		"success":                payoutRunCreateCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodPost, createPayoutRunRoute, bytes.NewBuffer([]byte(tc.in)))
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_CreatePayoutRunDryRun(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	mp := mock.NewMockCreator(mc)
+
+	item := domain.Item{ReferenceName: "test-ref-name", PriceAmount: decimal.NewFromInt(10), CurrencyCode: "EUR"}
+	planned := domain.Payout{
+		PriceTotal: decimal.NewFromInt(20),
+		Seller:     domain.Seller{CurrencyCode: "USD"},
+		Lines:      []domain.PayoutItem{{Item: item, ExchangeRate: decimal.NewNullDecimal(decimal.NewFromInt(2))}},
+	}
+
+	mp.EXPECT().Create(gomock.Any(), payouts.Request{SellerIDs: []string{validSellerID}, DryRun: true}).
+		Return(payouts.Result{Run: domain.JobRun{PayoutsCreated: 1}, Payouts: []domain.Payout{planned}}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	router := NewServer(gin.TestMode, ml, nil, bankfile.Originator{}, mp, nil)
+	w := httptest.NewRecorder()
+
+	in := `{"seller_ids": ["` + validSellerID + `"], "dry_run": true}`
+	req, _ := http.NewRequest(http.MethodPost, createPayoutRunRoute, bytes.NewBuffer([]byte(in)))
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data struct {
+			Run     domain.JobRun   `json:"run"`
+			Payouts []plannedPayout `json:"payouts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Run.PayoutsCreated)
+	require.Len(t, resp.Data.Payouts, 1)
+	assert.Equal(t, "USD", resp.Data.Payouts[0].Currency)
+	require.Len(t, resp.Data.Payouts[0].Items, 1)
+	assert.Equal(t, "test-ref-name", resp.Data.Payouts[0].Items[0].Name)
+	assert.Equal(t, "EUR", resp.Data.Payouts[0].Items[0].Currency)
+}
+
+func payoutRunCreateCaseFailJSON(mc *gomock.Controller) handlerCaseCreatePayoutRun {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreatePayoutRun{
+		h: handler{
+			Log: ml,
+		},
+		in:     `{"dry_run": true`,
+		status: http.StatusBadRequest,
+	}
+}
+
+func payoutRunCreateCaseFailValidation(mc *gomock.Controller) handlerCaseCreatePayoutRun {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreatePayoutRun{
+		h: handler{
+			Log: ml,
+		},
+		in:     `{"seller_ids": ["123"]}`,
+		status: http.StatusBadRequest,
+	}
+}
+
+func payoutRunCreateCaseFailDryRunSellers(mc *gomock.Controller) handlerCaseCreatePayoutRun {
+	ml := mock.NewMockLogger(mc)
+	mp := mock.NewMockCreator(mc)
+
+	mp.EXPECT().Create(gomock.Any(), payouts.Request{DryRun: true}).Return(payouts.Result{}, payouts.ErrDryRunSellers)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreatePayoutRun{
+		h: handler{
+			Log:     ml,
+			Payouts: mp,
+		},
+		in:     `{"dry_run": true}`,
+		status: http.StatusBadRequest,
+	}
+}
+
+func payoutRunCreateCaseFailRunning(mc *gomock.Controller) handlerCaseCreatePayoutRun {
+	ml := mock.NewMockLogger(mc)
+	mp := mock.NewMockCreator(mc)
+
+	mp.EXPECT().Create(gomock.Any(), payouts.Request{}).Return(payouts.Result{}, payouts.ErrRunning)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreatePayoutRun{
+		h: handler{
+			Log:     ml,
+			Payouts: mp,
+		},
+		in:     `{}`,
+		status: http.StatusConflict,
+	}
+}
+
+func payoutRunCreateCaseFailCreate(mc *gomock.Controller) handlerCaseCreatePayoutRun {
+	ml := mock.NewMockLogger(mc)
+	mp := mock.NewMockCreator(mc)
+
+	mp.EXPECT().Create(gomock.Any(), payouts.Request{}).Return(payouts.Result{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreatePayoutRun{
+		h: handler{
+			Log:     ml,
+			Payouts: mp,
+		},
+		in:     `{}`,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func payoutRunCreateCaseOK(mc *gomock.Controller) handlerCaseCreatePayoutRun {
+	ml := mock.NewMockLogger(mc)
+	mp := mock.NewMockCreator(mc)
+
+	mp.EXPECT().Create(gomock.Any(), payouts.Request{SellerIDs: []string{validSellerID}}).Return(payouts.Result{}, nil)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseCreatePayoutRun{
+		h: handler{
+			Log:     ml,
+			Payouts: mp,
+		},
+		in:     `{"seller_ids": ["` + validSellerID + `"]}`,
+		status: http.StatusAccepted,
+	}
+}
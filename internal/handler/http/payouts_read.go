@@ -9,35 +9,72 @@ import (
 	"github.com/TestardR/seller-payout/internal/domain"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
 	"github.com/gofrs/uuid"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	defaultPageLimit = 50
+	sortDesc         = "desc"
+)
+
+var (
+	errBindQuery = errors.New("failed to decode query parameters")
+	errInvalidID = errors.New("identifier should be a valid UUID")
+)
+
+// PayoutsQuery holds the query parameters accepted to list payouts.
+type PayoutsQuery struct {
+	SellerID string    `form:"seller_id" validate:"required,uuid"`
+	Status   string    `form:"status" validate:"omitempty,oneof=created approved exported settled"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort     string    `form:"sort" validate:"omitempty,oneof=asc desc"`
+	Cursor   string    `form:"cursor"`
+	Limit    int       `form:"limit" validate:"omitempty,min=1,max=200"`
+}
+
 type payout struct {
 	ID        uuid.UUID       `json:"id"`
+	SellerID  uuid.UUID       `json:"seller_id"`
 	Price     decimal.Decimal `json:"price"`
+	Status    string          `json:"status"`
 	CreatedAt time.Time       `json:"created_at"`
 	Currency  string          `json:"currency"`
-	Items     []item          `json:"items"`
+	Items     []payoutItem    `json:"items"`
 }
 
-type item struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+// payoutItem details how an item contributed to a payout total.
+type payoutItem struct {
+	ID              uuid.UUID           `json:"id"`
+	Name            string              `json:"name"`
+	Amount          decimal.Decimal     `json:"amount"`
+	Currency        string              `json:"currency"`
+	ConvertedAmount decimal.NullDecimal `json:"converted_amount"`
+	ExchangeRate    decimal.NullDecimal `json:"exchange_rate"`
 }
 
 // ReadPayouts method http GET
 // @Summary Endpoint to retrieve payouts for a specific seller.
-// @Description Create Seller.
+// @Description Read payouts of a seller with the detail of their items.
 // @Tags Seller
 // @Accept  json
 // @Produce  json
 // @Param seller_id query string true "Seller ID query parameter"
-// @Success 200 {object} ResponseSuccess
+// @Param status query string false "Payout status"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created before (RFC3339)"
+// @Param sort query string false "Sort order by creation date (asc or desc)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Success 200 {object} ResponsePage
 // @Failure 400 {object} ResponseError
 // @Failure 500 {object} ResponseError
-// @Router /payouts/:seller_id [get].
+// @Router /payouts [get].
 func (h handler) ReadPayouts(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	outErr := func(status int, err error) {
 		h.Log.Error(err)
 
@@ -45,11 +82,27 @@ func (h handler) ReadPayouts(c *gin.Context) {
 		c.JSON(status, newResponseError(err))
 	}
 
-	sellerID := c.Param("seller_id")
+	var query PayoutsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindQuery, err))
 
-	var seller domain.Seller
+		return
+	}
 
-	err := h.DB.FindByID(&seller, sellerID)
+	if err := validator.New().Struct(query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	filter, err := query.filter()
+	if err != nil {
+		outErr(http.StatusBadRequest, err)
+
+		return
+	}
+
+	_, err = h.Repos.Sellers().Get(ctx, query.SellerID)
 	if errors.Is(err, db.ErrRecordNotFound) {
 		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", db.ErrDB, err))
 
@@ -62,46 +115,80 @@ func (h handler) ReadPayouts(c *gin.Context) {
 		return
 	}
 
-	payouts, err := h.DB.FindPayoutsBySellerID(sellerID)
+	page, err := h.Repos.Payouts().FindBySeller(ctx, query.SellerID, filter)
 	if err != nil {
 		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
 
 		return
 	}
 
-	p := newPayoutsFromInput(payouts)
+	p := newPayoutsFromInput(page.Payouts)
 
 	h.Log.Info(successMessage)
-	c.JSON(http.StatusOK, &ResponseSuccess{p})
+	c.JSON(http.StatusOK, newResponsePage(p, page.Next, page.Total))
 }
 
-func newItemsFromInput(items []domain.Item) []item {
-	output := make([]item, 0, len(items))
+func (q PayoutsQuery) filter() (db.PayoutsFilter, error) {
+	f := db.PayoutsFilter{
+		From:   q.From,
+		To:     q.To,
+		Status: domain.PayoutStatus(q.Status),
+		Desc:   q.Sort == sortDesc,
+		Limit:  q.Limit,
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultPageLimit
+	}
+
+	if q.Cursor != "" {
+		after, err := db.DecodeCursor(q.Cursor)
+		if err != nil {
+			return db.PayoutsFilter{}, err
+		}
+
+		f.After = &after
+	}
+
+	return f, nil
+}
 
-	for _, dbItem := range items {
-		it := item{
-			ID:   dbItem.ID,
-			Name: dbItem.ReferenceName,
+func newPayoutItemsFromInput(lines []domain.PayoutItem) []payoutItem {
+	output := make([]payoutItem, 0, len(lines))
+
+	for _, line := range lines {
+		it := payoutItem{
+			ID:              line.ItemID,
+			Name:            line.Item.ReferenceName,
+			Amount:          line.Item.PriceAmount,
+			Currency:        line.Item.CurrencyCode,
+			ConvertedAmount: line.ConvertedAmount,
+			ExchangeRate:    line.ExchangeRate,
 		}
+
 		output = append(output, it)
 	}
 
 	return output
 }
 
+func newPayoutFromInput(dbPayout domain.Payout) payout {
+	return payout{
+		ID:        dbPayout.ID,
+		SellerID:  dbPayout.SellerID,
+		Price:     dbPayout.PriceTotal,
+		Status:    string(dbPayout.Status),
+		CreatedAt: dbPayout.CreatedAt,
+		Currency:  dbPayout.Currency.Code,
+		Items:     newPayoutItemsFromInput(dbPayout.Lines),
+	}
+}
+
 func newPayoutsFromInput(dbPayouts []domain.Payout) []payout {
 	output := make([]payout, 0, len(dbPayouts))
 
-	for _, DBpayout := range dbPayouts {
-		p := payout{
-			ID:        DBpayout.ID,
-			Price:     DBpayout.PriceTotal,
-			CreatedAt: DBpayout.CreatedAt,
-			Currency:  DBpayout.Currency.Code,
-			Items:     newItemsFromInput(DBpayout.Items),
-		}
-
-		output = append(output, p)
+	for _, dbPayout := range dbPayouts {
+		output = append(output, newPayoutFromInput(dbPayout))
 	}
 
 	return output
@@ -25,6 +25,7 @@ import (
 type handlerCaseReadPayouts struct {
 	h        handler
 	sellerID string
+	query    string
 	status   int
 }
 
@@ -33,6 +34,10 @@ func TestHandler_ReadPayouts(t *testing.T) {
 	t.Cleanup(func() { mc.Finish() })
 
 	tests := map[string]handlerCaseReadPayouts{
+		"fail-missing-seller-id":   payoutsReadCaseFailMissingSellerID(mc),
+		"fail-invalid-seller-id":   payoutsReadCaseFailInvalidSellerID(mc),
+		"fail-invalid-query":       payoutsReadCaseFailInvalidQuery(mc),
+		"fail-invalid-cursor":      payoutsReadCaseFailInvalidCursor(mc),
 		"fail-db-find-by-seller":   payoutsReadCaseFailDBFindSellerByID(mc),
 		"fail-db-seller-not-found": payoutsReadCaseFailDBSellerNotFound(mc),
 		"fail-db-find-payouts":     payoutsReadCaseFailDBFindPayouts(mc),
@@ -45,8 +50,8 @@ func TestHandler_ReadPayouts(t *testing.T) {
 			w := httptest.NewRecorder()
 			ctx, router := gin.CreateTestContext(w)
 
-			router.GET("/:seller_id", tc.h.ReadPayouts)
-			uri := fmt.Sprintf("/%s", tc.sellerID)
+			router.GET(readPayoutsRoute, tc.h.ReadPayouts)
+			uri := fmt.Sprintf("%s?seller_id=%s%s", readPayoutsRoute, tc.sellerID, tc.query)
 
 			var err error
 			ctx.Request, err = http.NewRequest("GET", uri, nil)
@@ -61,20 +66,81 @@ func TestHandler_ReadPayouts(t *testing.T) {
 	}
 }
 
+const validSellerID = "78dd7916-f276-494b-84a8-83e5bbee8c11"
+
+func payoutsReadCaseFailMissingSellerID(mc *gomock.Controller) handlerCaseReadPayouts {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayouts{
+		h: handler{
+			Log: ml,
+		},
+		sellerID: "",
+		status:   http.StatusBadRequest,
+	}
+}
+
+func payoutsReadCaseFailInvalidSellerID(mc *gomock.Controller) handlerCaseReadPayouts {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayouts{
+		h: handler{
+			Log: ml,
+		},
+		sellerID: "123",
+		status:   http.StatusBadRequest,
+	}
+}
+
+func payoutsReadCaseFailInvalidQuery(mc *gomock.Controller) handlerCaseReadPayouts {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayouts{
+		h: handler{
+			Log: ml,
+		},
+		sellerID: validSellerID,
+		query:    "&limit=1000&sort=up",
+		status:   http.StatusBadRequest,
+	}
+}
+
+func payoutsReadCaseFailInvalidCursor(mc *gomock.Controller) handlerCaseReadPayouts {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadPayouts{
+		h: handler{
+			Log: ml,
+		},
+		sellerID: validSellerID,
+		query:    "&cursor=foo",
+		status:   http.StatusBadRequest,
+	}
+}
+
 func payoutsReadCaseOK(mc *gomock.Controller) handlerCaseReadPayouts {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().FindByID(gomock.Any(), "123")
-	mdb.EXPECT().FindPayoutsBySellerID("123").Return([]domain.Payout{}, nil)
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any()).Return(db.PayoutsPage{}, nil)
 	ml.EXPECT().Info(gomock.Any())
 
 	return handlerCaseReadPayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		sellerID: "123",
+		sellerID: validSellerID,
+		query:    "&status=created&sort=desc&limit=10&from=2022-01-01T00:00:00Z",
 		status:   http.StatusOK,
 	}
 }
@@ -83,16 +149,16 @@ func payoutsReadCaseFailDBFindPayouts(mc *gomock.Controller) handlerCaseReadPayo
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().FindByID(gomock.Any(), "123")
-	mdb.EXPECT().FindPayoutsBySellerID("123").Return([]domain.Payout{}, errors.New("mock"))
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID)
+	mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any()).Return(db.PayoutsPage{}, errors.New("mock"))
 	ml.EXPECT().Error(gomock.Any())
 
 	return handlerCaseReadPayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		sellerID: "123",
+		sellerID: validSellerID,
 		status:   http.StatusInternalServerError,
 	}
 }
@@ -101,15 +167,15 @@ func payoutsReadCaseFailDBSellerNotFound(mc *gomock.Controller) handlerCaseReadP
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().FindByID(gomock.Any(), "123").Return(db.ErrRecordNotFound)
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(domain.Seller{}, db.ErrRecordNotFound)
 	ml.EXPECT().Error(gomock.Any())
 
 	return handlerCaseReadPayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		sellerID: "123",
+		sellerID: validSellerID,
 		status:   http.StatusBadRequest,
 	}
 }
@@ -118,19 +184,35 @@ func payoutsReadCaseFailDBFindSellerByID(mc *gomock.Controller) handlerCaseReadP
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().FindByID(gomock.Any(), "123").Return(errors.New("mock"))
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(domain.Seller{}, errors.New("mock"))
 	ml.EXPECT().Error(gomock.Any())
 
 	return handlerCaseReadPayouts{
 		h: handler{
-			Log: ml,
-			DB:  mdb,
+			Log:   ml,
+			Repos: db.NewUnitOfWork(mdb),
 		},
-		sellerID: "123",
+		sellerID: validSellerID,
 		status:   http.StatusInternalServerError,
 	}
 }
 
+func Test_PayoutsQueryFilter(t *testing.T) {
+	after := db.Cursor{CreatedAt: time.Date(2022, 2, 14, 0, 0, 0, 0, time.UTC), ID: uuid.FromStringOrNil(validSellerID)}
+
+	f, err := PayoutsQuery{Status: "created", Sort: "desc", Cursor: after.Encode()}.filter()
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.PayoutStatusCreated, f.Status)
+	assert.True(t, f.Desc)
+	assert.Equal(t, defaultPageLimit, f.Limit)
+	require.NotNil(t, f.After)
+	assert.Equal(t, after.ID, f.After.ID)
+
+	_, err = PayoutsQuery{Cursor: "foo"}.filter()
+	assert.ErrorIs(t, err, db.ErrInvalidCursor)
+}
+
 func Test_NewPayoutsFromInput(t *testing.T) {
 
 	expected := []domain.Payout{
@@ -138,10 +220,16 @@ func Test_NewPayoutsFromInput(t *testing.T) {
 			PriceTotal: decimal.NewFromInt(1),
 			CreatedAt:  time.Date(0, 0, 0, 0, 0, 0, 0, time.UTC),
 			Currency:   domain.Currency{Code: currency.EURCode},
-			Items: []domain.Item{
+			Lines: []domain.PayoutItem{
 				{
-					ID:            uuid.FromStringOrNil("test"),
-					ReferenceName: "test",
+					ItemID: uuid.FromStringOrNil("test"),
+					Item: domain.Item{
+						ReferenceName: "test",
+						PriceAmount:   decimal.NewFromInt(2),
+						CurrencyCode:  currency.USDCode,
+					},
+					ConvertedAmount: decimal.NewNullDecimal(decimal.NewFromInt(1)),
+					ExchangeRate:    decimal.NewNullDecimal(decimal.RequireFromString("0.5")),
 				},
 			},
 		},
@@ -150,5 +238,9 @@ func Test_NewPayoutsFromInput(t *testing.T) {
 	got := newPayoutsFromInput(expected)
 
 	assert.Equal(t, got[0].ID, expected[0].ID)
-	assert.Equal(t, got[0].Items[0].ID, expected[0].Items[0].ID)
+	assert.Equal(t, got[0].Items[0].ID, expected[0].Lines[0].ItemID)
+	assert.Equal(t, currency.USDCode, got[0].Items[0].Currency)
+	assert.True(t, got[0].Items[0].Amount.Equal(decimal.NewFromInt(2)))
+	assert.True(t, got[0].Items[0].ConvertedAmount.Decimal.Equal(decimal.NewFromInt(1)))
+	assert.True(t, got[0].Items[0].ExchangeRate.Decimal.Equal(decimal.RequireFromString("0.5")))
 }
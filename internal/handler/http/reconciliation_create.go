@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/reconcile"
+	"github.com/TestardR/seller-payout/internal/webhook"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var errStatementFormat = errors.New("statement format should be camt.053 or csv")
+
+// CreateReconciliation method http POST
+// @Summary Endpoint to reconcile a bank statement against exported payouts.
+// @Description Upload a camt.053 XML or CSV (date,amount,currency,reference header, debits negative) statement.
+// @Description Debits are matched to exported payouts by reference, then by amount, currency and date,
+// @Description matched payouts are settled and the others reported as exceptions.
+// @Tags Reconciliation
+// @Accept  application/xml
+// @Accept  text/csv
+// @Produce  json
+// @Param format query string false "Statement format (camt.053 or csv), defaults to the Content-Type"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 409 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /reconciliations [post].
+func (h handler) CreateReconciliation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	format, err := statementFormat(c)
+	if err != nil {
+		outErr(http.StatusBadRequest, err)
+
+		return
+	}
+
+	parse := reconcile.ParseCSV
+	if format == reconcile.FormatCAMT053 {
+		parse = reconcile.ParseCAMT053
+	}
+
+	entries, err := parse(c.Request.Body)
+	if err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	payouts, err := h.Repos.Payouts().FindByStatus(ctx, domain.PayoutStatusExported)
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	report := reconcile.Reconcile(entries, payouts, reconcile.DefaultRules)
+
+	rec := domain.Reconciliation{
+		Format:          format,
+		EntriesCount:    report.Entries,
+		MatchedCount:    len(report.Matches),
+		ExceptionsCount: len(report.Exceptions),
+		Exceptions:      report.Exceptions,
+	}
+
+	sellers := make(map[uuid.UUID]uuid.UUID, len(payouts))
+	for _, p := range payouts {
+		sellers[p.ID] = p.SellerID
+	}
+
+	if err := h.persistReconciliation(ctx, &rec, report.Matches, sellers); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, db.ErrPayoutsChanged) {
+			status = http.StatusConflict
+		}
+
+		outErr(status, err)
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{rec})
+}
+
+// persistReconciliation records the reconciliation with its exceptions, settles the matched payouts and records
+// their status change events, in a single transaction. sellers holds the seller ID of each payout.
+func (h handler) persistReconciliation(
+	ctx context.Context, rec *domain.Reconciliation, matches []reconcile.Match, sellers map[uuid.UUID]uuid.UUID,
+) error {
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.PayoutID.String())
+	}
+
+	return h.Repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		if err := tx.Reconciliations().Create(ctx, rec); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		if err := tx.Payouts().Settle(ctx, ids, time.Now()); err != nil {
+			if errors.Is(err, db.ErrPayoutsChanged) {
+				return err
+			}
+
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		for _, m := range matches {
+			err := addEvent(ctx, tx, sellers[m.PayoutID], domain.EventPayoutStatusChanged, webhook.PayoutStatusChanged{
+				PayoutID: m.PayoutID,
+				From:     domain.PayoutStatusExported,
+				To:       domain.PayoutStatusSettled,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func statementFormat(c *gin.Context) (string, error) {
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+
+		switch mediaType {
+		case "application/xml", "text/xml":
+			format = reconcile.FormatCAMT053
+		case "text/csv":
+			format = reconcile.FormatCSV
+		}
+	}
+
+	if format != reconcile.FormatCAMT053 && format != reconcile.FormatCSV {
+		return "", errStatementFormat
+	}
+
+	return format, nil
+}
@@ -0,0 +1,203 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/webhook"
+	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+type handlerCaseCreateReconciliation struct {
+	h           handler
+	contentType string
+	in          string
+	status      int
+}
+
+func TestHandler_CreateReconciliation(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseCreateReconciliation{
+		"fail-format":          reconciliationCreateCaseFailFormat(mc),
+		"fail-parse":           reconciliationCreateCaseFailParse(mc),
+		"fail-db-find-payouts": reconciliationCreateCaseFailDBFindPayouts(mc),
+		"fail-db-insert":       reconciliationCreateCaseFailDBInsert(mc),
+		"fail-payouts-changed": reconciliationCreateCaseFailPayoutsChanged(mc),
+		"success":              reconciliationCreateCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, createReconRoute, bytes.NewBufferString(tc.in))
+			req.Header.Set("Content-Type", tc.contentType)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func validStatementCSV() string {
+	return "date,amount,currency,reference\n" +
+		"2022-02-15,-10.00,EUR," + validPayoutID + "\n" +
+		"2022-02-15,-42.50,EUR,BANK-FEES\n"
+}
+
+func exportedPayouts() []domain.Payout {
+	exportedAt := time.Date(2022, 2, 14, 0, 0, 0, 0, time.UTC)
+
+	return []domain.Payout{{
+		ID:         uuid.FromStringOrNil(validPayoutID),
+		SellerID:   uuid.FromStringOrNil(validSellerID),
+		Status:     domain.PayoutStatusExported,
+		PriceTotal: decimal.NewFromInt(10),
+		Currency:   domain.Currency{Code: currency.EURCode},
+		ExportedAt: &exportedAt,
+	}}
+}
+
+func reconciliationCreateCaseFailFormat(mc *gomock.Controller) handlerCaseCreateReconciliation {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateReconciliation{
+		h: handler{
+			Log: ml,
+		},
+		contentType: "application/json",
+		in:          "{}",
+		status:      http.StatusBadRequest,
+	}
+}
+
+func reconciliationCreateCaseFailParse(mc *gomock.Controller) handlerCaseCreateReconciliation {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateReconciliation{
+		h: handler{
+			Log: ml,
+		},
+		contentType: "application/xml",
+		in:          "<Document>",
+		status:      http.StatusBadRequest,
+	}
+}
+
+func reconciliationCreateCaseFailDBFindPayouts(mc *gomock.Controller) handlerCaseCreateReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusExported).Return(nil, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          validStatementCSV(),
+		status:      http.StatusInternalServerError,
+	}
+}
+
+func reconciliationCreateCaseFailDBInsert(mc *gomock.Controller) handlerCaseCreateReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusExported).Return(exportedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertReconciliation(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          validStatementCSV(),
+		status:      http.StatusInternalServerError,
+	}
+}
+
+func reconciliationCreateCaseFailPayoutsChanged(mc *gomock.Controller) handlerCaseCreateReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusExported).Return(exportedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertReconciliation(gomock.Any(), gomock.Any())
+	mtx.EXPECT().SettlePayouts(gomock.Any(), []string{validPayoutID}, gomock.Any()).Return(db.ErrPayoutsChanged)
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          validStatementCSV(),
+		status:      http.StatusConflict,
+	}
+}
+
+func reconciliationCreateCaseOK(mc *gomock.Controller) handlerCaseCreateReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindPayoutsByStatus(gomock.Any(), domain.PayoutStatusExported).Return(exportedPayouts(), nil)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().InsertReconciliation(gomock.Any(), gomock.Any()).Do(func(_ context.Context, rec *domain.Reconciliation) {
+		if rec.EntriesCount != 2 || rec.MatchedCount != 1 || rec.ExceptionsCount != 1 {
+			panic("unexpected reconciliation")
+		}
+	})
+	mtx.EXPECT().SettlePayouts(gomock.Any(), []string{validPayoutID}, gomock.Any())
+	mtx.EXPECT().InsertOutboxMessage(gomock.Any(), outboxMessage(validSellerID, domain.EventPayoutStatusChanged,
+		webhook.PayoutStatusChanged{
+			PayoutID: uuid.FromStringOrNil(validPayoutID),
+			From:     domain.PayoutStatusExported,
+			To:       domain.PayoutStatusSettled,
+		}))
+	mtx.EXPECT().Commit()
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseCreateReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		contentType: "text/csv",
+		in:          validStatementCSV(),
+		status:      http.StatusOK,
+	}
+}
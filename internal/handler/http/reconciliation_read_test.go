@@ -0,0 +1,114 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadReconciliation struct {
+	h      handler
+	id     string
+	status int
+}
+
+func TestHandler_ReadReconciliation(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadReconciliation{
+		"fail-invalid-id":   reconciliationReadCaseFailInvalidID(mc),
+		"fail-db-not-found": reconciliationReadCaseFailDBNotFound(mc),
+		"fail-db-find":      reconciliationReadCaseFailDBFind(mc),
+		"success":           reconciliationReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/reconciliations/"+tc.id, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+const validReconciliationID = "5b1f0c8e-2a4d-4e6f-9b3c-7d8e9f0a1b2c"
+
+func reconciliationReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadReconciliation {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadReconciliation{
+		h: handler{
+			Log: ml,
+		},
+		id:     "123",
+		status: http.StatusBadRequest,
+	}
+}
+
+func reconciliationReadCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReadReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindReconciliationByID(gomock.Any(), validReconciliationID).Return(domain.Reconciliation{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validReconciliationID,
+		status: http.StatusNotFound,
+	}
+}
+
+func reconciliationReadCaseFailDBFind(mc *gomock.Controller) handlerCaseReadReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindReconciliationByID(gomock.Any(), validReconciliationID).Return(domain.Reconciliation{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validReconciliationID,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func reconciliationReadCaseOK(mc *gomock.Controller) handlerCaseReadReconciliation {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindReconciliationByID(gomock.Any(), validReconciliationID)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadReconciliation{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validReconciliationID,
+		status: http.StatusOK,
+	}
+}
@@ -1,10 +1,66 @@
 package http
 
+import (
+	"errors"
+
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/pkg/db"
+)
+
+// Error codes, see Error.Code.
+const (
+	codeInvalidJSON    = "invalid_json"
+	codeInvalidPayload = "invalid_payload"
+	codeInvalidID      = "invalid_id"
+	codeNotFound       = "not_found"
+	codeConflict       = "conflict"
+	codeDatabase       = "database_error"
+	codeRequired       = "required"
+	codeInvalidValue   = "invalid_value"
+	codeOutOfRange     = "out_of_range"
+)
+
+// errorCodes maps sentinel errors to the code returned to clients, first match wins.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{errBindJSON, codeInvalidJSON},
+	{errBindQuery, codeInvalidPayload},
+	{errValidatePayload, codeInvalidPayload},
+	{errMissingPayload, codeInvalidPayload},
+	{db.ErrInvalidCursor, codeInvalidPayload},
+	{errInvalidID, codeInvalidID},
+	{errPayoutNotFound, codeNotFound},
+	{errItemNotFound, codeNotFound},
+	{errItemImportNotFound, codeNotFound},
+	{errSellerNotFound, codeNotFound},
+	{errBankFileNotFound, codeNotFound},
+	{errReconciliationNotFound, codeNotFound},
+	{errDeadLetterNotFound, codeNotFound},
+	{errJobRunNotFound, codeNotFound},
+	{errStatementFormat, codeInvalidPayload},
+	{errPayoutStatus, codeConflict},
+	{errDeadLetterReplayed, codeConflict},
+	{payouts.ErrRunning, codeConflict},
+	{db.ErrPayoutsChanged, codeConflict},
+	{db.ErrDB, codeDatabase},
+}
+
 // ResponseSuccess describes an generic API response for success.
 type ResponseSuccess struct {
 	Data interface{} `json:"data"`
 }
 
+// ResponsePage describes an generic API response for a paginated listing.
+type ResponsePage struct {
+	Data interface{} `json:"data"`
+	// NextCursor is to be sent back as cursor query parameter to fetch the next page,
+	// it is omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
 // ResponseError describes an generic API response for error.
 type ResponseError struct {
 	Errs []Error `json:"errors,omitempty"`
@@ -13,13 +69,39 @@ type ResponseError struct {
 // Error describes an error field in a Response.
 type Error struct {
 	Message string `json:"message"`
+	// Code is a machine readable identifier of the error, e.g. required or out_of_range.
+	Code string `json:"code,omitempty"`
+	// Field is the path of the invalid field within the payload, e.g. amount.
+	Field string `json:"field,omitempty"`
+	// Index is the position of the invalid element when the payload is a list.
+	Index *int `json:"index,omitempty"`
 }
 
 func newResponseError(errs ...error) ResponseError {
 	var resp ResponseError
 
 	for _, err := range errs {
-		resp.Errs = append(resp.Errs, Error{err.Error()})
+		resp.Errs = append(resp.Errs, Error{Message: err.Error(), Code: errorCode(err)})
+	}
+
+	return resp
+}
+
+func errorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+
+	return ""
+}
+
+func newResponsePage(data interface{}, next *db.Cursor, total int64) ResponsePage {
+	resp := ResponsePage{Data: data, Total: total}
+
+	if next != nil {
+		resp.NextCursor = next.Encode()
 	}
 
 	return resp
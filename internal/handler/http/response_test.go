@@ -2,8 +2,10 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,3 +19,15 @@ func TestNewResponseError(t *testing.T) {
 	assert.Equal(t, "foo", respError.Errs[0].Message)
 	assert.Equal(t, "bar", respError.Errs[1].Message)
 }
+
+func TestNewResponseErrorCode(t *testing.T) {
+	respError := newResponseError(
+		fmt.Errorf("%w: %s", errBindJSON, "mock"),
+		fmt.Errorf("%w: %s", db.ErrDB, "mock"),
+		errors.New("foo"),
+	)
+
+	assert.Equal(t, codeInvalidJSON, respError.Errs[0].Code)
+	assert.Equal(t, codeDatabase, respError.Errs[1].Code)
+	assert.Empty(t, respError.Errs[2].Code)
+}
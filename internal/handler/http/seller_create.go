@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/TestardR/seller-payout/internal/domain"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
 )
 
 var (
@@ -19,6 +21,26 @@ var (
 // Seller is the item owner with a desired currency for payouts.
 type Seller struct {
 	Currency string `required:"true" validate:"eq=GBP|eq=USD|eq=EUR"`
+	// Bank details, checked when the payouts are exported to a bank file.
+	Name          string `json:"name" validate:"max=140"`
+	IBAN          string `json:"iban" validate:"max=34"`
+	BIC           string `json:"bic" validate:"max=11"`
+	RoutingNumber string `json:"routing_number" validate:"max=9"`
+	AccountNumber string `json:"account_number" validate:"max=17"`
+}
+
+// accountVisibleDigits is the number of trailing characters of the account identifiers returned.
+const accountVisibleDigits = 4
+
+// seller is a seller as returned by the API, its IBAN and account number are masked.
+type seller struct {
+	ID            uuid.UUID `json:"id"`
+	Currency      string    `json:"currency_code"`
+	Name          string    `json:"name,omitempty"`
+	IBAN          string    `json:"iban,omitempty"`
+	BIC           string    `json:"bic,omitempty"`
+	RoutingNumber string    `json:"routing_number,omitempty"`
+	AccountNumber string    `json:"account_number,omitempty"`
 }
 
 // CreateSeller method http POST
@@ -27,7 +49,7 @@ type Seller struct {
 // @Tags Seller
 // @Accept  json
 // @Produce  json
-// @Param create body handler.Seller true "Find the fields needed to create a seller using the 'handler' tab below."
+// @Param create body Seller true "Find the fields needed to create a seller using the 'http' tab below."
 // @Success 200 {object} ResponseSuccess
 // @Failure 400 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -54,16 +76,42 @@ func (h handler) CreateSeller(c *gin.Context) {
 		return
 	}
 
-	seller := domain.Seller{
-		CurrencyCode: input.Currency,
+	s := domain.Seller{
+		CurrencyCode:  input.Currency,
+		Name:          input.Name,
+		IBAN:          input.IBAN,
+		BIC:           input.BIC,
+		RoutingNumber: input.RoutingNumber,
+		AccountNumber: input.AccountNumber,
 	}
 
-	if err := h.DB.Insert(&seller); err != nil {
+	if err := h.Repos.Sellers().Create(c.Request.Context(), &s); err != nil {
 		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
 
 		return
 	}
 
 	h.Log.Info(successMessage)
-	c.JSON(http.StatusOK, &ResponseSuccess{seller})
+	c.JSON(http.StatusOK, &ResponseSuccess{newSellerFromInput(s)})
+}
+
+func newSellerFromInput(s domain.Seller) seller {
+	return seller{
+		ID:            s.ID,
+		Currency:      s.CurrencyCode,
+		Name:          s.Name,
+		IBAN:          maskAccount(s.IBAN),
+		BIC:           s.BIC,
+		RoutingNumber: s.RoutingNumber,
+		AccountNumber: maskAccount(s.AccountNumber),
+	}
+}
+
+// maskAccount hides an account identifier but its last digits, enough for the seller to recognize it.
+func maskAccount(v string) string {
+	if len(v) <= accountVisibleDigits {
+		return strings.Repeat("*", len(v))
+	}
+
+	return strings.Repeat("*", len(v)-accountVisibleDigits) + v[len(v)-accountVisibleDigits:]
 }
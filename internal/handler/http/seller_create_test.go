@@ -2,14 +2,18 @@ package http
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/TestardR/seller-payout/internal/domain"
 	"github.com/TestardR/seller-payout/pkg/mock"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type handlerCaseCreateSeller struct {
@@ -32,7 +36,7 @@ func TestHandler_CreateSeller(t *testing.T) {
 	for tn, tc := range tests {
 		tn, tc := tn, tc
 		t.Run(tn, func(t *testing.T) {
-			router := NewServer(gin.TestMode, tc.h.Log, tc.h.DB)
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
 			w := httptest.NewRecorder()
 
 			req, _ := http.NewRequest(http.MethodPost, createSellersRoute, bytes.NewBuffer([]byte(tc.in)))
@@ -49,7 +53,7 @@ func sellerCreateCaseFailDBInsertSeller(mc *gomock.Controller) handlerCaseCreate
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().Insert(gomock.Any()).Return(errors.New("mock"))
+	mdb.EXPECT().InsertSeller(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
 	ml.EXPECT().Error(gomock.Any())
 
 	return handlerCaseCreateSeller{
@@ -94,7 +98,7 @@ func sellerCreateCaseOK(mc *gomock.Controller) handlerCaseCreateSeller {
 	ml := mock.NewMockLogger(mc)
 	mdb := mock.NewMockDB(mc)
 
-	mdb.EXPECT().Insert(gomock.Any())
+	mdb.EXPECT().InsertSeller(gomock.Any(), gomock.Any())
 	ml.EXPECT().Info(gomock.Any())
 
 	return handlerCaseCreateSeller{
@@ -112,3 +116,21 @@ func validInputSeller() string {
 		"currency": "EUR"
 	}`
 }
+
+func TestNewSellerFromInput(t *testing.T) {
+	s := newSellerFromInput(domain.Seller{
+		CurrencyCode:  "EUR",
+		IBAN:          "FR7630006000011234567890189",
+		BIC:           "AGRIFRPP",
+		AccountNumber: "123",
+	})
+
+	assert.Equal(t, "***********************0189", s.IBAN)
+	assert.Equal(t, "AGRIFRPP", s.BIC)
+	assert.Equal(t, "***", s.AccountNumber)
+
+	out, err := json.Marshal(domain.Item{Seller: domain.Seller{IBAN: "FR7630006000011234567890189", AccountNumber: "123456789"}})
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "0189")
+	assert.NotContains(t, string(out), "123456789")
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/internal/statement"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+	"github.com/gofrs/uuid"
+)
+
+// statementPageSize is the number of payouts fetched at once to build a statement.
+const statementPageSize = 200
+
+var (
+	errSellerNotFound = errors.New("seller not found")
+	errStatement      = errors.New("failed to generate statement")
+)
+
+// statementContentTypes maps the statement formats to their content type.
+var statementContentTypes = map[string]string{
+	statement.FormatCSV: "text/csv",
+	statement.FormatPDF: "application/pdf",
+}
+
+// StatementQuery holds the query parameters accepted to export a statement.
+type StatementQuery struct {
+	From   time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" validate:"required"`
+	To     time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" validate:"required,gtfield=From"`
+	Format string    `form:"format" validate:"omitempty,oneof=csv pdf"`
+}
+
+// ReadSellerStatement method http GET
+// @Summary Endpoint to export the payout statement of a seller.
+// @Description Export the payouts of a seller over a period with their items, conversions and fees,
+// @Description along with the opening and closing balance (cumulated amount paid out).
+// @Tags Seller
+// @Produce  text/csv
+// @Produce  application/pdf
+// @Param id path string true "Seller ID"
+// @Param from query string true "Period start, included (RFC3339)"
+// @Param to query string true "Period end, excluded (RFC3339)"
+// @Param format query string false "Statement format (csv or pdf), defaults to csv"
+// @Success 200 {file} file
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /sellers/{id}/statements [get].
+func (h handler) ReadSellerStatement(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	var query StatementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindQuery, err))
+
+		return
+	}
+
+	if err := validator.New().Struct(query); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	if query.Format == "" {
+		query.Format = statement.FormatCSV
+	}
+
+	seller, err := h.Repos.Sellers().Get(ctx, id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errSellerNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	s, err := h.sellerStatement(ctx, seller, query.From, query.To)
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	var buf bytes.Buffer
+
+	write := statement.WriteCSV
+	if query.Format == statement.FormatPDF {
+		write = statement.WritePDF
+	}
+
+	if err := write(&buf, s); err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", errStatement, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.Filename(query.Format)))
+	c.Data(http.StatusOK, statementContentTypes[query.Format], buf.Bytes())
+}
+
+// sellerStatement gathers the payouts of the period page by page,
+// the opening balance sums every payout made before the period.
+func (h handler) sellerStatement(ctx context.Context, seller domain.Seller, from, to time.Time) (statement.Statement, error) {
+	id := seller.ID.String()
+
+	opening, err := h.Repos.Payouts().SumBySeller(ctx, id, db.PayoutsFilter{To: from})
+	if err != nil {
+		return statement.Statement{}, err
+	}
+
+	var payouts []domain.Payout
+
+	filter := db.PayoutsFilter{From: from, To: to, Limit: statementPageSize}
+
+	for {
+		page, err := h.Repos.Payouts().FindBySeller(ctx, id, filter)
+		if err != nil {
+			return statement.Statement{}, err
+		}
+
+		payouts = append(payouts, page.Payouts...)
+
+		if page.Next == nil {
+			break
+		}
+
+		filter.After = page.Next
+	}
+
+	return statement.New(seller, from, to, opening, payouts), nil
+}
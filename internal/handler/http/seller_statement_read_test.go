@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+type handlerCaseReadSellerStatement struct {
+	h           handler
+	uri         string
+	status      int
+	contentType string
+}
+
+const statementPeriod = "?from=2022-01-01T00:00:00Z&to=2022-02-01T00:00:00Z"
+
+func TestHandler_ReadSellerStatement(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadSellerStatement{
+		"fail-invalid-id":          sellerStatementReadCaseFailInvalidID(mc),
+		"fail-invalid-query":       sellerStatementReadCaseFailInvalidQuery(mc),
+		"fail-db-seller-not-found": sellerStatementReadCaseFailDBSellerNotFound(mc),
+		"fail-db-sum-payouts":      sellerStatementReadCaseFailDBSumPayouts(mc),
+		"fail-db-find-payouts":     sellerStatementReadCaseFailDBFindPayouts(mc),
+		"success-csv":              sellerStatementReadCaseCSVOK(mc),
+		"success-pdf":              sellerStatementReadCasePDFOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tc.uri, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+
+			if tc.contentType != "" && w.Header().Get("Content-Type") != tc.contentType {
+				t.Errorf("Expected content type %s, got %s", tc.contentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func sellerStatementReadCaseFailInvalidID(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+		},
+		uri:    "/sellers/123/statements" + statementPeriod,
+		status: http.StatusBadRequest,
+	}
+}
+
+func sellerStatementReadCaseFailInvalidQuery(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+		},
+		uri:    "/sellers/" + validSellerID + "/statements?from=2022-02-01T00:00:00Z&to=2022-01-01T00:00:00Z",
+		status: http.StatusBadRequest,
+	}
+}
+
+func sellerStatementReadCaseFailDBSellerNotFound(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(domain.Seller{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/sellers/" + validSellerID + "/statements" + statementPeriod,
+		status: http.StatusNotFound,
+	}
+}
+
+func sellerStatementReadCaseFailDBSumPayouts(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(validSeller(), nil)
+	mdb.EXPECT().SumPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any()).Return(decimal.Zero, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/sellers/" + validSellerID + "/statements" + statementPeriod,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func sellerStatementReadCaseFailDBFindPayouts(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(validSeller(), nil)
+	mdb.EXPECT().SumPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any())
+	mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any()).Return(db.PayoutsPage{}, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:    "/sellers/" + validSellerID + "/statements" + statementPeriod,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func sellerStatementReadCaseCSVOK(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	next := &db.Cursor{CreatedAt: from}
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(validSeller(), nil)
+	mdb.EXPECT().SumPayoutsBySellerID(gomock.Any(), validSellerID, db.PayoutsFilter{To: from}).Return(decimal.NewFromInt(10), nil)
+	gomock.InOrder(
+		mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any()).Return(db.PayoutsPage{Next: next}, nil),
+		mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, f db.PayoutsFilter) (db.PayoutsPage, error) {
+				if f.After != next || f.Limit != statementPageSize {
+					panic("statement should fetch the next page")
+				}
+
+				return db.PayoutsPage{}, nil
+			}),
+	)
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:         "/sellers/" + validSellerID + "/statements" + statementPeriod,
+		status:      http.StatusOK,
+		contentType: "text/csv",
+	}
+}
+
+func sellerStatementReadCasePDFOK(mc *gomock.Controller) handlerCaseReadSellerStatement {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindSellerByID(gomock.Any(), validSellerID).Return(validSeller(), nil)
+	mdb.EXPECT().SumPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any())
+	mdb.EXPECT().FindPayoutsBySellerID(gomock.Any(), validSellerID, gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadSellerStatement{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		uri:         "/sellers/" + validSellerID + "/statements" + statementPeriod + "&format=pdf",
+		status:      http.StatusOK,
+		contentType: "application/pdf",
+	}
+}
+
+func validSeller() domain.Seller {
+	return domain.Seller{ID: uuid.FromStringOrNil(validSellerID), CurrencyCode: "USD"}
+}
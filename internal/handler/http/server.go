@@ -4,6 +4,9 @@ import (
 	// swagger docs.
 
 	_ "github.com/TestardR/seller-payout/docs"
+	"github.com/TestardR/seller-payout/internal/bankfile"
+	"github.com/TestardR/seller-payout/internal/payouts"
+	"github.com/TestardR/seller-payout/internal/scheduler"
 	"github.com/TestardR/seller-payout/pkg/db"
 	"github.com/TestardR/seller-payout/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -16,10 +19,28 @@ import (
 )
 
 const (
-	healthRoute        = "/health"
-	createItemsRoute   = "/items"
-	readPayoutsRoute   = "/payouts/:seller_id"
-	createSellersRoute = "/seller"
+	healthRoute           = "/health"
+	createItemsRoute      = "/items"
+	readItemsRoute        = "/items"
+	readItemRoute         = "/items/:id"
+	importItemsRoute      = "/items/imports"
+	readImportRoute       = "/items/imports/:id"
+	readImportErrsRoute   = "/items/imports/:id/errors"
+	readPayoutsRoute      = "/payouts"
+	readPayoutRoute       = "/payouts/:id"
+	approvePayoutRoute    = "/payouts/:id/approve"
+	createBankFileRoute   = "/bank-files"
+	readBankFileRoute     = "/bank-files/:id"
+	createReconRoute      = "/reconciliations"
+	readReconRoute        = "/reconciliations/:id"
+	createSellersRoute    = "/seller"
+	readStatementRoute    = "/sellers/:id/statements"
+	createWebhookRoute    = "/webhooks"
+	readDeadLettersRoute  = "/webhooks/dead-letters"
+	replayDeadLetterRoute = "/webhooks/dead-letters/:id/replay"
+	readJobRunsRoute      = "/admin/jobs"
+	readJobRunRoute       = "/admin/jobs/:id"
+	createPayoutRunRoute  = "/admin/payout-runs"
 )
 
 // @title SellerPayout Rest Server
@@ -32,10 +53,20 @@ const (
 // @host localhost:3000
 
 // NewServer instantiates an HTTP server.
-func NewServer(env string, log logger.Logger, db db.DB) *gin.Engine {
+func NewServer(
+	env string,
+	log logger.Logger,
+	database db.DB,
+	bank bankfile.Originator,
+	payouts payouts.Creator,
+	jobs scheduler.Monitor) *gin.Engine {
 	h := handler{
-		Log: log,
-		DB:  db,
+		Log:     log,
+		DB:      database,
+		Repos:   db.NewUnitOfWork(database),
+		Bank:    bank,
+		Payouts: payouts,
+		Jobs:    jobs,
 	}
 
 	gin.SetMode(env)
@@ -51,12 +82,38 @@ func NewServer(env string, log logger.Logger, db db.DB) *gin.Engine {
 
 	// Payouts
 	router.GET(readPayoutsRoute, h.ReadPayouts)
+	router.GET(readPayoutRoute, h.ReadPayout)
+	router.POST(approvePayoutRoute, h.ApprovePayout)
+
+	// Bank files
+	router.POST(createBankFileRoute, h.CreateBankFiles)
+	router.GET(readBankFileRoute, h.ReadBankFile)
+
+	// Reconciliations
+	router.POST(createReconRoute, h.CreateReconciliation)
+	router.GET(readReconRoute, h.ReadReconciliation)
 
 	// Items
 	router.POST(createItemsRoute, h.CreateItems)
+	router.GET(readItemsRoute, h.ReadItems)
+	router.GET(readItemRoute, h.ReadItem)
+	router.POST(importItemsRoute, h.ImportItems)
+	router.GET(readImportRoute, h.ReadItemImport)
+	router.GET(readImportErrsRoute, h.ReadItemImportErrors)
 
 	// Sellers
 	router.POST(createSellersRoute, h.CreateSeller)
+	router.GET(readStatementRoute, h.ReadSellerStatement)
+
+	// Webhooks
+	router.POST(createWebhookRoute, h.CreateWebhook)
+	router.GET(readDeadLettersRoute, h.ReadWebhookDeadLetters)
+	router.POST(replayDeadLetterRoute, h.ReplayWebhookDeadLetter)
+
+	// Admin
+	router.GET(readJobRunsRoute, h.ReadJobRuns)
+	router.GET(readJobRunRoute, h.ReadJobRun)
+	router.POST(createPayoutRunRoute, h.CreatePayoutRun)
 
 	return router
 }
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator"
+)
+
+// secretSize is the number of random bytes of a subscription secret.
+const secretSize = 32
+
+// Webhook is a subscription to events.
+type Webhook struct {
+	URL    string   `json:"url" validate:"required,url,startswith=http"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=item.created payout.created payout.status_changed"`
+}
+
+// CreatedWebhook is the subscription created, along with the secret signing its payloads.
+type CreatedWebhook struct {
+	domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// CreateWebhook method http POST
+// @Summary Endpoint to subscribe to events.
+// @Description Register a URL notified of events. The secret returned signs the payloads and is not shown again.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Param create body Webhook true "Find the fields needed to create a webhook using the 'http' tab below."
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /webhooks [post].
+func (h handler) CreateWebhook(c *gin.Context) {
+	var input Webhook
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errBindJSON, err))
+
+		return
+	}
+
+	if err := validator.New().Struct(input); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errValidatePayload, err))
+
+		return
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		outErr(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	sub := domain.WebhookSubscription{
+		URL:    input.URL,
+		Secret: hex.EncodeToString(secret),
+		Events: strings.Join(input.Events, ","),
+		Active: true,
+	}
+
+	if err := h.Repos.Webhooks().Subscribe(c.Request.Context(), &sub); err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{CreatedWebhook{sub, sub.Secret}})
+}
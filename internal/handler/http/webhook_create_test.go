@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerCaseCreateWebhook struct {
+	h      handler
+	in     string
+	status int
+}
+
+func TestHandler_CreateWebhook(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseCreateWebhook{
+		"fail-json":              webhookCreateCaseFailJSON(mc),
+		"fail-invalid-url":       webhookCreateCaseFailValidation(mc, `{"url": "ftp://x", "events": ["item.created"]}`),
+		"fail-unknown-event":     webhookCreateCaseFailValidation(mc, `{"url": "https://x.io", "events": ["item.deleted"]}`),
+		"fail-no-event":          webhookCreateCaseFailValidation(mc, `{"url": "https://x.io", "events": []}`),
+		"fail-db-insert-webhook": webhookCreateCaseFailDBInsert(mc),
+		"success":                webhookCreateCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodPost, createWebhookRoute, bytes.NewBuffer([]byte(tc.in)))
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_CreateWebhookReturnsSecret(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tc := webhookCreateCaseOK(mc)
+	router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+	w := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodPost, createWebhookRoute, bytes.NewBuffer([]byte(tc.in)))
+	router.ServeHTTP(w, req)
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data["secret"], 2*secretSize)
+	assert.Equal(t, "item.created,payout.status_changed", resp.Data["events"])
+}
+
+func webhookCreateCaseFailJSON(mc *gomock.Controller) handlerCaseCreateWebhook {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateWebhook{
+		h: handler{
+			Log: ml,
+		},
+		in:     `{"url": "https://x.io",`,
+		status: http.StatusBadRequest,
+	}
+}
+
+func webhookCreateCaseFailValidation(mc *gomock.Controller, in string) handlerCaseCreateWebhook {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateWebhook{
+		h: handler{
+			Log: ml,
+		},
+		in:     in,
+		status: http.StatusBadRequest,
+	}
+}
+
+func webhookCreateCaseFailDBInsert(mc *gomock.Controller) handlerCaseCreateWebhook {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertWebhookSubscription(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseCreateWebhook{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		in:     validInputWebhook(),
+		status: http.StatusInternalServerError,
+	}
+}
+
+func webhookCreateCaseOK(mc *gomock.Controller) handlerCaseCreateWebhook {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().InsertWebhookSubscription(gomock.Any(), gomock.Any()).Do(func(_ context.Context, sub *domain.WebhookSubscription) {
+		if !sub.Active || sub.Secret == "" {
+			panic("unexpected webhook subscription")
+		}
+	})
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseCreateWebhook{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		in:     validInputWebhook(),
+		status: http.StatusOK,
+	}
+}
+
+func validInputWebhook() string {
+	return `{"url": "https://example.com/hooks", "events": ["item.created", "payout.status_changed"]}`
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+var (
+	errDeadLetterNotFound = errors.New("dead letter not found")
+	errDeadLetterReplayed = errors.New("dead letter already replayed")
+)
+
+// ReplayWebhookDeadLetter method http POST
+// @Summary Endpoint to replay a webhook dead letter.
+// @Description Schedule the delivery of a dead letter for a new round of attempts.
+// @Tags Webhook
+// @Produce  json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} ResponseSuccess
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 409 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /webhooks/dead-letters/{id}/replay [post].
+func (h handler) ReplayWebhookDeadLetter(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.FromString(id); err != nil {
+		outErr(http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidID, err))
+
+		return
+	}
+
+	dl, err := h.Repos.Webhooks().GetDeadLetter(ctx, id)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusNotFound, fmt.Errorf("%w: %s", errDeadLetterNotFound, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	if dl.ReplayedAt != nil {
+		outErr(http.StatusConflict, fmt.Errorf("%w: %s", errDeadLetterReplayed, id))
+
+		return
+	}
+
+	now := time.Now()
+
+	// the replayed_at is checked again on update in case the dead letter was replayed meanwhile.
+	err = h.replayDeadLetter(ctx, id, now)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		outErr(http.StatusConflict, fmt.Errorf("%w: %s", errDeadLetterReplayed, id))
+
+		return
+	}
+
+	if err != nil {
+		outErr(http.StatusInternalServerError, err)
+
+		return
+	}
+
+	dl.ReplayedAt = &now
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{dl})
+}
+
+func (h handler) replayDeadLetter(ctx context.Context, id string, at time.Time) error {
+	return h.Repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		err := tx.Webhooks().ReplayDeadLetter(ctx, id, at)
+		if err == nil || errors.Is(err, db.ErrRecordNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	})
+}
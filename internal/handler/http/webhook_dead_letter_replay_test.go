@@ -0,0 +1,166 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+const validDeadLetterID = "5b0c3c1e-2d4f-4a8e-9c6b-7e1f0a2b3c4d"
+
+type handlerCaseReplayDeadLetter struct {
+	h      handler
+	id     string
+	status int
+}
+
+func TestHandler_ReplayWebhookDeadLetter(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReplayDeadLetter{
+		"fail-invalid-id":         deadLetterReplayCaseFailInvalidID(mc),
+		"fail-db-not-found":       deadLetterReplayCaseFailDBNotFound(mc),
+		"fail-already-replayed":   deadLetterReplayCaseFailReplayed(mc),
+		"fail-replayed-meanwhile": deadLetterReplayCaseFailReplayedMeanwhile(mc),
+		"fail-db-replay":          deadLetterReplayCaseFailDBReplay(mc),
+		"success":                 deadLetterReplayCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/webhooks/dead-letters/"+tc.id+"/replay", nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func deadLetterReplayCaseFailInvalidID(mc *gomock.Controller) handlerCaseReplayDeadLetter {
+	ml := mock.NewMockLogger(mc)
+
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReplayDeadLetter{
+		h: handler{
+			Log: ml,
+		},
+		id:     "123",
+		status: http.StatusBadRequest,
+	}
+}
+
+func deadLetterReplayCaseFailDBNotFound(mc *gomock.Controller) handlerCaseReplayDeadLetter {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindWebhookDeadLetterByID(gomock.Any(), validDeadLetterID).Return(domain.WebhookDeadLetter{}, db.ErrRecordNotFound)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReplayDeadLetter{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validDeadLetterID,
+		status: http.StatusNotFound,
+	}
+}
+
+func deadLetterReplayCaseFailReplayed(mc *gomock.Controller) handlerCaseReplayDeadLetter {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	replayedAt := time.Now()
+
+	mdb.EXPECT().FindWebhookDeadLetterByID(gomock.Any(), validDeadLetterID).
+		Return(domain.WebhookDeadLetter{ReplayedAt: &replayedAt}, nil)
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReplayDeadLetter{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validDeadLetterID,
+		status: http.StatusConflict,
+	}
+}
+
+func deadLetterReplayCaseFailReplayedMeanwhile(mc *gomock.Controller) handlerCaseReplayDeadLetter {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindWebhookDeadLetterByID(gomock.Any(), validDeadLetterID)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().ReplayWebhookDeadLetter(gomock.Any(), validDeadLetterID, gomock.Any()).Return(db.ErrRecordNotFound)
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReplayDeadLetter{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validDeadLetterID,
+		status: http.StatusConflict,
+	}
+}
+
+func deadLetterReplayCaseFailDBReplay(mc *gomock.Controller) handlerCaseReplayDeadLetter {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindWebhookDeadLetterByID(gomock.Any(), validDeadLetterID)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().ReplayWebhookDeadLetter(gomock.Any(), validDeadLetterID, gomock.Any()).Return(errors.New("mock"))
+	mtx.EXPECT().Rollback()
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReplayDeadLetter{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validDeadLetterID,
+		status: http.StatusInternalServerError,
+	}
+}
+
+func deadLetterReplayCaseOK(mc *gomock.Controller) handlerCaseReplayDeadLetter {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+	mtx := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindWebhookDeadLetterByID(gomock.Any(), validDeadLetterID)
+	mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+	mtx.EXPECT().ReplayWebhookDeadLetter(gomock.Any(), validDeadLetterID, gomock.Any())
+	mtx.EXPECT().Commit()
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReplayDeadLetter{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		id:     validDeadLetterID,
+		status: http.StatusOK,
+	}
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gin-gonic/gin"
+)
+
+// ReadWebhookDeadLetters method http GET
+// @Summary Endpoint to list the webhook dead letters.
+// @Description Read the deliveries which ran out of attempts and were not replayed yet.
+// @Tags Webhook
+// @Produce  json
+// @Success 200 {object} ResponseSuccess
+// @Failure 500 {object} ResponseError
+// @Router /webhooks/dead-letters [get].
+func (h handler) ReadWebhookDeadLetters(c *gin.Context) {
+	outErr := func(status int, err error) {
+		h.Log.Error(err)
+
+		c.Error(err)
+		c.JSON(status, newResponseError(err))
+	}
+
+	dls, err := h.Repos.Webhooks().FindDeadLetters(c.Request.Context())
+	if err != nil {
+		outErr(http.StatusInternalServerError, fmt.Errorf("%w: %s", db.ErrDB, err))
+
+		return
+	}
+
+	h.Log.Info(successMessage)
+	c.JSON(http.StatusOK, &ResponseSuccess{dls})
+}
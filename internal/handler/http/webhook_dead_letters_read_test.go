@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type handlerCaseReadDeadLetters struct {
+	h      handler
+	status int
+}
+
+func TestHandler_ReadWebhookDeadLetters(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	tests := map[string]handlerCaseReadDeadLetters{
+		"fail-db-find-dead-letters": deadLettersReadCaseFailDB(mc),
+		"success":                   deadLettersReadCaseOK(mc),
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			router := NewServer(gin.TestMode, tc.h.Log, serverDB(tc.h), tc.h.Bank, tc.h.Payouts, tc.h.Jobs)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, readDeadLettersRoute, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Result().StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func deadLettersReadCaseFailDB(mc *gomock.Controller) handlerCaseReadDeadLetters {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindWebhookDeadLetters(gomock.Any()).Return(nil, errors.New("mock"))
+	ml.EXPECT().Error(gomock.Any())
+
+	return handlerCaseReadDeadLetters{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		status: http.StatusInternalServerError,
+	}
+}
+
+func deadLettersReadCaseOK(mc *gomock.Controller) handlerCaseReadDeadLetters {
+	ml := mock.NewMockLogger(mc)
+	mdb := mock.NewMockDB(mc)
+
+	mdb.EXPECT().FindWebhookDeadLetters(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	return handlerCaseReadDeadLetters{
+		h: handler{
+			Log: ml,
+			DB:  mdb,
+		},
+		status: http.StatusOK,
+	}
+}
@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+)
+
+var errEncodeEvent = errors.New("failed to encode event")
+
+// Publisher sends the outbox messages to other systems. A message may be published more than once,
+// consumers deduplicate them by ID.
+type Publisher interface {
+	Publish(ctx context.Context, m domain.OutboxMessage) error
+}
+
+// Envelope is the representation of a message sent to other systems.
+type Envelope struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      domain.EventType `json:"type"`
+	Key       uuid.UUID        `json:"key"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      json.RawMessage  `json:"data"`
+}
+
+// NewEnvelope returns the representation of a message sent to other systems.
+func NewEnvelope(m domain.OutboxMessage) Envelope {
+	return Envelope{
+		ID:        m.ID,
+		Type:      m.EventType,
+		Key:       m.Key,
+		CreatedAt: m.CreatedAt,
+		Data:      m.Payload,
+	}
+}
+
+// NewMessage returns a message to insert in the transaction of the change it is about.
+// Messages of a same key are published in order.
+func NewMessage(key uuid.UUID, t domain.EventType, data interface{}) (domain.OutboxMessage, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.OutboxMessage{}, fmt.Errorf("%w: %s", errEncodeEvent, err)
+	}
+
+	return domain.OutboxMessage{
+		Key:       key,
+		EventType: t,
+		Payload:   payload,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testNow   = time.Date(2022, 2, 14, 13, 24, 21, 0, time.UTC)
+	sellerOne = uuid.FromStringOrNil("78dd7916-f276-494b-84a8-83e5bbee8c11")
+	sellerTwo = uuid.FromStringOrNil("78dd7916-f276-494b-84a8-83e5bbee8c27")
+)
+
+// failingPublisher fails the messages of a key and records the others.
+type failingPublisher struct {
+	MemoryPublisher
+	key uuid.UUID
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, m domain.OutboxMessage) error {
+	if m.Key == p.key {
+		return errors.New("mock")
+	}
+
+	return p.MemoryPublisher.Publish(ctx, m)
+}
+
+func testMessage(seq int64, key uuid.UUID) domain.OutboxMessage {
+	return domain.OutboxMessage{
+		ID:        uuid.Must(uuid.NewV4()),
+		Seq:       seq,
+		Key:       key,
+		EventType: domain.EventPayoutCreated,
+		Payload:   []byte(`{}`),
+	}
+}
+
+func testRelay(mdb *mock.MockDB, pub Publisher) *Relay {
+	r := NewRelay(mdb, pub)
+	r.now = func() time.Time { return testNow }
+
+	return r
+}
+
+func TestNewMessage(t *testing.T) {
+	m, err := NewMessage(sellerOne, domain.EventPayoutCreated, map[string]int{"a": 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, sellerOne, m.Key)
+	assert.Equal(t, domain.EventPayoutCreated, m.EventType)
+	assert.JSONEq(t, `{"a":1}`, string(m.Payload))
+}
+
+func TestRelay_Flush(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	t.Run("success", func(t *testing.T) {
+		messages := []domain.OutboxMessage{testMessage(1, sellerOne), testMessage(2, sellerTwo)}
+		pub := &MemoryPublisher{}
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindUnpublishedOutboxMessages(gomock.Any(), batchSize).Return(messages, nil)
+		mdb.EXPECT().UpdateOutboxMessage(gomock.Any(), gomock.Any()).Times(2).Do(func(_ context.Context, m domain.OutboxMessage) {
+			assert.Equal(t, testNow, *m.PublishedAt)
+			assert.Equal(t, 1, m.Attempts)
+			assert.Nil(t, m.DeadAt)
+		})
+
+		require.NoError(t, testRelay(mdb, pub).Flush(context.Background()))
+
+		published := pub.Messages()
+		require.Len(t, published, 2)
+
+		for i, m := range published {
+			assert.Equal(t, messages[i].ID, m.ID)
+		}
+	})
+
+	t.Run("failed-key-does-not-block-others", func(t *testing.T) {
+		messages := []domain.OutboxMessage{testMessage(1, sellerOne), testMessage(2, sellerTwo)}
+		pub := &failingPublisher{key: sellerOne}
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindUnpublishedOutboxMessages(gomock.Any(), batchSize).Return(messages, nil)
+		mdb.EXPECT().UpdateOutboxMessage(gomock.Any(), gomock.Any()).Do(func(_ context.Context, m domain.OutboxMessage) {
+			assert.Equal(t, messages[0].ID, m.ID)
+			assert.Nil(t, m.PublishedAt)
+			assert.Nil(t, m.DeadAt)
+			assert.Equal(t, "mock", m.LastError)
+		})
+		mdb.EXPECT().UpdateOutboxMessage(gomock.Any(), gomock.Any()).Do(func(_ context.Context, m domain.OutboxMessage) {
+			assert.Equal(t, messages[1].ID, m.ID)
+			assert.NotNil(t, m.PublishedAt)
+		})
+
+		err := testRelay(mdb, pub).Flush(context.Background())
+		assert.ErrorIs(t, err, errPublish)
+
+		require.Len(t, pub.Messages(), 1)
+		assert.Equal(t, sellerTwo, pub.Messages()[0].Key)
+	})
+
+	t.Run("dead-after-max-attempts", func(t *testing.T) {
+		m := testMessage(1, sellerOne)
+		m.Attempts = maxAttempts - 1
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindUnpublishedOutboxMessages(gomock.Any(), batchSize).Return([]domain.OutboxMessage{m}, nil)
+		mdb.EXPECT().UpdateOutboxMessage(gomock.Any(), gomock.Any()).Do(func(_ context.Context, got domain.OutboxMessage) {
+			assert.Equal(t, maxAttempts, got.Attempts)
+			assert.Nil(t, got.PublishedAt)
+			require.NotNil(t, got.DeadAt)
+			assert.Equal(t, testNow, *got.DeadAt)
+		})
+
+		err := testRelay(mdb, &failingPublisher{key: sellerOne}).Flush(context.Background())
+		assert.ErrorIs(t, err, errPublish)
+	})
+
+	t.Run("fail-db-find-messages", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindUnpublishedOutboxMessages(gomock.Any(), batchSize).Return(nil, errors.New("mock"))
+
+		assert.Error(t, testRelay(mdb, &MemoryPublisher{}).Flush(context.Background()))
+	})
+
+	t.Run("fail-db-update-message", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindUnpublishedOutboxMessages(gomock.Any(), batchSize).
+			Return([]domain.OutboxMessage{testMessage(1, sellerOne)}, nil)
+		mdb.EXPECT().UpdateOutboxMessage(gomock.Any(), gomock.Any()).Return(errors.New("mock"))
+
+		assert.Error(t, testRelay(mdb, &MemoryPublisher{}).Flush(context.Background()))
+	})
+}
+
+func TestHTTPPublisher_Publish(t *testing.T) {
+	var got Envelope
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &got))
+
+		if got.Key == sellerTwo {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	m := testMessage(1, sellerOne)
+	m.Payload = []byte(`{"payout_id":"1"}`)
+
+	require.NoError(t, NewHTTPPublisher(ts.URL).Publish(context.Background(), m))
+	assert.Equal(t, m.ID, got.ID)
+	assert.Equal(t, domain.EventPayoutCreated, got.Type)
+	assert.JSONEq(t, `{"payout_id":"1"}`, string(got.Data))
+
+	assert.ErrorIs(t, NewHTTPPublisher(ts.URL).Publish(context.Background(), testMessage(2, sellerTwo)), errStatus)
+}
+
+func TestNewPublisher(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+
+	p, err := NewPublisher(PublisherLog, "", ml)
+	require.NoError(t, err)
+	assert.IsType(t, LogPublisher{}, p)
+
+	_, err = NewPublisher(PublisherHTTP, "", ml)
+	assert.ErrorIs(t, err, errPublisherURL)
+
+	_, err = NewPublisher("kafka", "", ml)
+	assert.ErrorIs(t, err, errPublisherKind)
+}
@@ -0,0 +1,138 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/logger"
+)
+
+const (
+	// PublisherLog is the kind of publisher writing the messages to the logs.
+	PublisherLog = "log"
+	// PublisherHTTP is the kind of publisher posting the messages to an URL.
+	PublisherHTTP = "http"
+
+	timeout = 10 * time.Second
+)
+
+var (
+	errPublisherKind = errors.New("unknown publisher kind")
+	errPublisherURL  = errors.New("publisher URL is required")
+	errStatus        = errors.New("unexpected response status")
+)
+
+// NewPublisher returns a publisher of a kind, url is only used by the HTTP one.
+func NewPublisher(kind, url string, log logger.Logger) (Publisher, error) {
+	switch kind {
+	case PublisherLog:
+		return LogPublisher{Log: log}, nil
+	case PublisherHTTP:
+		if url == "" {
+			return nil, errPublisherURL
+		}
+
+		return NewHTTPPublisher(url), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errPublisherKind, kind)
+	}
+}
+
+// Publishers publishes the messages to each of its publishers in turn.
+// When one fails, the message is published again to all of them.
+type Publishers []Publisher
+
+// Publish publishes the message to each publisher.
+func (ps Publishers) Publish(ctx context.Context, m domain.OutboxMessage) error {
+	for _, p := range ps {
+		if err := p.Publish(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LogPublisher writes the messages to the logs.
+type LogPublisher struct {
+	Log logger.Logger
+}
+
+// Publish logs the message.
+func (p LogPublisher) Publish(_ context.Context, m domain.OutboxMessage) error {
+	p.Log.Info(fmt.Sprintf("event %s %s published for %s: %s", m.EventType, m.ID, m.Key, m.Payload))
+
+	return nil
+}
+
+// HTTPPublisher posts the messages as JSON envelopes to an URL.
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher returns a publisher posting the messages to url.
+func NewHTTPPublisher(url string) HTTPPublisher {
+	return HTTPPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish posts the message, any status but 2xx is a failure.
+func (p HTTPPublisher) Publish(ctx context.Context, m domain.OutboxMessage) error {
+	body, err := json.Marshal(NewEnvelope(m))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errEncodeEvent, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %d", errStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// MemoryPublisher keeps the messages in memory, it is meant for tests and local runs.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []domain.OutboxMessage
+}
+
+// Publish records the message.
+func (p *MemoryPublisher) Publish(_ context.Context, m domain.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, m)
+
+	return nil
+}
+
+// Messages returns the messages published so far.
+func (p *MemoryPublisher) Messages() []domain.OutboxMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]domain.OutboxMessage(nil), p.messages...)
+}
@@ -0,0 +1,81 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+)
+
+const (
+	// batchSize is the maximum number of messages published by a Flush call.
+	batchSize = 100
+	// maxAttempts is the number of attempts before a message is set aside as dead.
+	maxAttempts = 8
+)
+
+var errPublish = errors.New("failed to publish outbox messages")
+
+// Relay publishes the outbox messages.
+type Relay struct {
+	db          db.DB
+	pub         Publisher
+	now         func() time.Time
+	maxAttempts int
+}
+
+// NewRelay returns a Relay publishing the messages of db to pub.
+func NewRelay(db db.DB, pub Publisher) *Relay {
+	return &Relay{
+		db:          db,
+		pub:         pub,
+		now:         time.Now,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Flush publishes the oldest pending message of each key, in the order they were recorded. A message is marked
+// published once Publish succeeded, so it is published at least once. When a message fails, the next ones
+// of its key are held back until it is published or, after maxAttempts, set aside as dead: the next ones
+// of its key are then published. The messages left once ctx is done are published on the next call.
+func (r *Relay) Flush(ctx context.Context) error {
+	messages, err := r.db.FindUnpublishedOutboxMessages(ctx, batchSize)
+	if err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	var failed int
+
+	for _, m := range messages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		m.Attempts++
+		now := r.now()
+
+		if err := r.pub.Publish(ctx, m); err != nil {
+			failed++
+			m.LastError = err.Error()
+
+			if m.Attempts >= r.maxAttempts {
+				m.DeadAt = &now
+			}
+		} else {
+			m.PublishedAt = &now
+			m.LastError = ""
+		}
+
+		if err := r.db.UpdateOutboxMessage(ctx, m); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d keys held back", errPublish, failed)
+	}
+
+	return nil
+}
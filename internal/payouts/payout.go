@@ -0,0 +1,232 @@
+// Package payouts splits the unpaid out items of a seller into payouts in the seller currency.
+package payouts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// TotalPriceLimit is the maximum total price of a payout, items above it go to another payout.
+	TotalPriceLimit = 1_000_000
+	// MaxDryRunSellers is the maximum number of sellers of a dry run, whose planned payouts are all returned at once.
+	MaxDryRunSellers = 100
+)
+
+var (
+	// ErrRunning is returned when the payouts are being created by another run.
+	ErrRunning = errors.New("payouts creation already running")
+	// ErrDryRunSellers is returned when a dry run does not list its sellers or lists more than MaxDryRunSellers.
+	ErrDryRunSellers = errors.New("a dry run should list its sellers, 100 at most")
+)
+
+//go:generate mockgen -source=payout.go -destination=$MOCK_FOLDER/payouts.go -package=mock
+
+// Creator creates payouts on demand, outside of the schedule.
+type Creator interface {
+	Create(ctx context.Context, r Request) (Result, error)
+}
+
+// Request scopes a payouts creation.
+type Request struct {
+	// SellerIDs restricts the creation to some sellers, all sellers are paid out when empty.
+	SellerIDs []string
+	// DryRun computes the payouts without persisting them, it requires SellerIDs.
+	DryRun bool
+}
+
+// Result is the outcome of a payouts creation.
+type Result struct {
+	// Run counts the sellers processed and the payouts created, it is not recorded on a dry run.
+	Run domain.JobRun
+	// Payouts are the payouts which would be created, only filled on a dry run.
+	Payouts []domain.Payout
+}
+
+// Plan returns the payouts of a seller, without persisting them.
+func Plan(seller domain.Seller, currencies map[string]domain.Currency) []domain.Payout {
+	done := make(chan struct{})
+	defer close(done)
+
+	var payouts []domain.Payout
+	for p := range Generate(done, seller, currencies, Items(done, seller.Items)) {
+		payouts = append(payouts, p)
+	}
+
+	return payouts
+}
+
+// Generate runs the first stages of the payouts creation pipeline: the items of the seller received
+// on itemC until it is closed are batched under TotalPriceLimit, then each batch is turned into a payout
+// sent on the returned channel. Closing done stops the stages.
+func Generate(
+	done <-chan struct{},
+	seller domain.Seller,
+	currencies map[string]domain.Currency,
+	itemC <-chan domain.Item) <-chan domain.Payout {
+	// Stage 1. creates batch of items
+	itemsBatchC := generateItemsBatch(done, seller, currencies, itemC)
+	// Stage 2. creates payouts
+	return generatePayouts(done, seller, currencies, itemsBatchC)
+}
+
+// Items sends the items on the returned channel, which is closed once they are all sent or done is closed.
+func Items(done <-chan struct{}, items []domain.Item) <-chan domain.Item {
+	itemC := make(chan domain.Item)
+
+	go func() {
+		defer close(itemC)
+
+		for _, item := range items {
+			select {
+			case itemC <- item:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return itemC
+}
+
+type itemsBatch struct {
+	items      []domain.Item
+	lines      []domain.PayoutItem
+	totalPrice decimal.Decimal
+}
+
+func generateItemsBatch(
+	done <-chan struct{},
+	seller domain.Seller,
+	currencies map[string]domain.Currency,
+	itemC <-chan domain.Item) <-chan itemsBatch {
+	itemsBatchC := make(chan itemsBatch)
+
+	go func() {
+		defer close(itemsBatchC)
+
+		var (
+			batch []domain.Item
+			lines []domain.PayoutItem
+		)
+
+		totalPrice := decimal.NewFromInt(0)
+
+		for item := range itemC {
+			price := convertToSellerCurrency(
+				seller.CurrencyCode,
+				item.CurrencyCode,
+				currencies,
+				item.PriceAmount)
+
+			if totalPrice.Add(price).GreaterThan(decimal.NewFromInt(TotalPriceLimit)) {
+				itemsBatchC <- itemsBatch{
+					batch,
+					lines,
+					totalPrice,
+				}
+
+				batch = nil
+				lines = nil
+				totalPrice = decimal.NewFromInt(0)
+			}
+
+			totalPrice = totalPrice.Add(price)
+
+			batch = append(batch, item)
+			lines = append(lines, domain.PayoutItem{
+				ItemID:          item.ID,
+				ConvertedAmount: decimal.NewNullDecimal(price),
+				ExchangeRate:    decimal.NewNullDecimal(exchangeRate(seller.CurrencyCode, item.CurrencyCode, currencies)),
+			})
+		}
+
+		// no payout is created for a seller whose items were all paid out meanwhile.
+		if len(batch) == 0 {
+			return
+		}
+
+		ib := itemsBatch{
+			batch,
+			lines,
+			totalPrice,
+		}
+
+		select {
+		case itemsBatchC <- ib:
+		case <-done:
+		}
+	}()
+
+	return itemsBatchC
+}
+
+func generatePayouts(
+	done <-chan struct{},
+	seller domain.Seller,
+	currencies map[string]domain.Currency,
+	itemsBatchC <-chan itemsBatch) <-chan domain.Payout {
+	payoutC := make(chan domain.Payout)
+	sellerCurrency := seller.CurrencyCode
+
+	go func() {
+		defer close(payoutC)
+
+		for batch := range itemsBatchC {
+			p := domain.Payout{
+				PriceTotal: batch.totalPrice.Round(domain.PriceDecimals),
+				Status:     domain.PayoutStatusCreated,
+				Items:      batch.items,
+				Lines:      batch.lines,
+				SellerID:   seller.ID,
+				Seller:     seller,
+				CurrencyID: currencies[sellerCurrency].ID,
+				Currency:   currencies[sellerCurrency],
+			}
+
+			select {
+			case payoutC <- p:
+			case <-done:
+			}
+		}
+	}()
+
+	return payoutC
+}
+
+func convertToSellerCurrency(
+	sellerCode, itemCode string,
+	currencies map[string]domain.Currency,
+	price decimal.Decimal) decimal.Decimal {
+	if itemCode == sellerCode {
+		return price
+	}
+
+	if sellerCode == currency.USDCode {
+		price = price.Div(currencies[itemCode].USDExchRate)
+	} else {
+		price = price.Div(currencies[itemCode].USDExchRate).Mul(currencies[sellerCode].USDExchRate)
+	}
+
+	return price
+}
+
+// exchangeRate returns the rate applied by convertToSellerCurrency
+// to convert one unit of itemCode into sellerCode.
+func exchangeRate(sellerCode, itemCode string, currencies map[string]domain.Currency) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+
+	if itemCode == sellerCode {
+		return one
+	}
+
+	if sellerCode == currency.USDCode {
+		return one.Div(currencies[itemCode].USDExchRate)
+	}
+
+	return currencies[sellerCode].USDExchRate.Div(currencies[itemCode].USDExchRate)
+}
@@ -0,0 +1,92 @@
+package payouts
+
+import (
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_convertToSellerCurrency(t *testing.T) {
+	currencies := make(map[string]domain.Currency)
+
+	currencies["USD"] = domain.Currency{
+		USDExchRate: decimal.NewFromInt(1),
+	}
+	currencies["EUR"] = domain.Currency{
+		USDExchRate: decimal.NewFromInt(1),
+	}
+
+	got := convertToSellerCurrency("USD", "EUR", currencies, decimal.NewFromInt(1))
+	assert.True(t, got.Equal(decimal.NewFromInt(1)))
+
+	got = convertToSellerCurrency("EUR", "USD", currencies, decimal.NewFromInt(1))
+	assert.True(t, got.Equal(decimal.NewFromInt(1)))
+}
+
+func Test_exchangeRate(t *testing.T) {
+	currencies := map[string]domain.Currency{
+		"USD": {USDExchRate: decimal.NewFromInt(1)},
+		"EUR": {USDExchRate: decimal.RequireFromString("0.8")},
+		"GBP": {USDExchRate: decimal.RequireFromString("0.5")},
+	}
+
+	assert.True(t, exchangeRate("USD", "USD", currencies).Equal(decimal.NewFromInt(1)))
+	assert.True(t, exchangeRate("USD", "EUR", currencies).Equal(decimal.RequireFromString("1.25")))
+	assert.True(t, exchangeRate("GBP", "EUR", currencies).Equal(decimal.RequireFromString("0.625")))
+}
+
+func Test_generatePayoutsRecordsLines(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+
+	seller := sellerAboveMaxPrice()
+	currencies := map[string]domain.Currency{"USD": {USDExchRate: decimal.NewFromInt(1)}}
+
+	var payouts []domain.Payout
+	for p := range generatePayouts(done, seller, currencies, generateItemsBatch(done, seller, currencies, Items(done, seller.Items))) {
+		payouts = append(payouts, p)
+	}
+
+	require.Len(t, payouts, 2)
+
+	for _, p := range payouts {
+		require.Len(t, p.Lines, 1)
+		assert.True(t, p.Lines[0].ConvertedAmount.Decimal.Equal(p.PriceTotal))
+		assert.True(t, p.Lines[0].ExchangeRate.Decimal.Equal(decimal.NewFromInt(1)))
+	}
+}
+
+func TestPlan(t *testing.T) {
+	currencies := map[string]domain.Currency{
+		"USD": {Code: "USD", USDExchRate: decimal.NewFromInt(1)},
+		"EUR": {Code: "EUR", USDExchRate: decimal.RequireFromString("0.5")},
+	}
+
+	seller := sellerAboveMaxPrice()
+	seller.Items = append(seller.Items, domain.Item{PriceAmount: decimal.NewFromInt(10), CurrencyCode: "EUR"})
+
+	payouts := Plan(seller, currencies)
+
+	require.Len(t, payouts, 3)
+	assert.True(t, payouts[0].PriceTotal.Equal(decimal.NewFromInt(TotalPriceLimit)))
+	assert.True(t, payouts[1].PriceTotal.Equal(decimal.NewFromInt(TotalPriceLimit)))
+	assert.True(t, payouts[2].PriceTotal.Equal(decimal.NewFromInt(20)))
+	assert.Equal(t, "EUR", payouts[2].Items[0].CurrencyCode)
+	assert.Equal(t, "USD", payouts[2].Currency.Code)
+}
+
+func sellerAboveMaxPrice() domain.Seller {
+	item := domain.Item{
+		ReferenceName: "test-ref-name",
+		PriceAmount:   decimal.NewFromInt(TotalPriceLimit),
+		CurrencyCode:  "USD",
+	}
+
+	return domain.Seller{
+		CurrencyCode: "USD",
+		Items:        []domain.Item{item, item},
+	}
+}
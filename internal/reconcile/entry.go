@@ -0,0 +1,230 @@
+package reconcile
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// FormatCAMT053 is the ISO 20022 camt.053 bank to customer statement format.
+	FormatCAMT053 = "camt.053"
+	// FormatCSV is a CSV statement, see ParseCSV.
+	FormatCSV = "csv"
+
+	debit = "DBIT"
+)
+
+var (
+	errParseStatement = errors.New("failed to parse bank statement")
+	errCSVColumns     = errors.New("missing CSV columns")
+)
+
+// csvColumns are the CSV columns expected in the header, in any order.
+var csvColumns = []string{"date", "amount", "currency", "reference"}
+
+// Entry is a debit booked on a bank statement.
+type Entry struct {
+	// Reference is the end to end reference of the transfer, or its remittance information.
+	Reference string
+	Amount    decimal.Decimal
+	Currency  string
+	Date      time.Time
+}
+
+// camtDocument holds the camt.053 elements needed to reconcile debits.
+type camtDocument struct {
+	XMLName    xml.Name `xml:"Document"`
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	BookgDt   camtDate   `xml:"BookgDt"`
+	ValDt     camtDate   `xml:"ValDt"`
+	AcctSvcr  string     `xml:"AcctSvcrRef"`
+	TxDtls    []struct {
+		EndToEndID string      `xml:"Refs>EndToEndId"`
+		Amt        *camtAmount `xml:"Amt"`
+		TxAmt      *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+		Ustrd      []string    `xml:"RmtInf>Ustrd"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Dt   string `xml:"Dt"`
+	DtTm string `xml:"DtTm"`
+}
+
+// ParseCAMT053 reads the debit entries of a camt.053 statement,
+// batch booked entries are split into their transactions.
+func ParseCAMT053(r io.Reader) ([]Entry, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", errParseStatement, err)
+	}
+
+	var entries []Entry
+
+	for _, stmt := range doc.Statements {
+		for i, ntry := range stmt.Entries {
+			if ntry.CdtDbtInd != debit {
+				continue
+			}
+
+			date, err := ntry.date()
+			if err != nil {
+				return nil, fmt.Errorf("%w: entry %d: %s", errParseStatement, i+1, err)
+			}
+
+			if len(ntry.TxDtls) == 0 {
+				e, err := newEntry(ntry.AcctSvcr, ntry.Amt, date)
+				if err != nil {
+					return nil, fmt.Errorf("%w: entry %d: %s", errParseStatement, i+1, err)
+				}
+
+				entries = append(entries, e)
+
+				continue
+			}
+
+			for _, tx := range ntry.TxDtls {
+				amount := ntry.Amt
+				if tx.Amt != nil {
+					amount = *tx.Amt
+				} else if tx.TxAmt != nil {
+					amount = *tx.TxAmt
+				}
+
+				ref := tx.EndToEndID
+				if ref == "" || ref == "NOTPROVIDED" {
+					ref = strings.Join(tx.Ustrd, " ")
+				}
+
+				e, err := newEntry(ref, amount, date)
+				if err != nil {
+					return nil, fmt.Errorf("%w: entry %d: %s", errParseStatement, i+1, err)
+				}
+
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func (e camtEntry) date() (time.Time, error) {
+	for _, d := range []camtDate{e.BookgDt, e.ValDt} {
+		if d.Dt != "" {
+			return time.Parse("2006-01-02", d.Dt)
+		}
+
+		if d.DtTm != "" {
+			return time.Parse(time.RFC3339, d.DtTm)
+		}
+	}
+
+	return time.Time{}, errors.New("missing booking date")
+}
+
+func newEntry(ref string, amount camtAmount, date time.Time) (Entry, error) {
+	value, err := decimal.NewFromString(strings.TrimSpace(amount.Value))
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{
+		Reference: strings.TrimSpace(ref),
+		Amount:    value.Abs(),
+		Currency:  amount.Ccy,
+		Date:      date,
+	}, nil
+}
+
+// ParseCSV reads the debits of a CSV statement with a date (YYYY-MM-DD), amount, currency and reference header.
+// Debits are negative amounts, credits are ignored.
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errParseStatement, err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		positions[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	width := 0
+
+	for _, column := range csvColumns {
+		i, ok := positions[column]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errCSVColumns, column)
+		}
+
+		if i >= width {
+			width = i + 1
+		}
+	}
+
+	var entries []Entry
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errParseStatement, err)
+		}
+
+		if len(record) < width {
+			return nil, fmt.Errorf("%w: line %d: expected %d fields, got %d", errParseStatement, line, width, len(record))
+		}
+
+		field := func(column string) string {
+			return strings.TrimSpace(record[positions[column]])
+		}
+
+		amount, err := decimal.NewFromString(field("amount"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid amount: %s", errParseStatement, line, err)
+		}
+
+		if !amount.IsNegative() {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid date: %s", errParseStatement, line, err)
+		}
+
+		entries = append(entries, Entry{
+			Reference: field("reference"),
+			Amount:    amount.Abs(),
+			Currency:  strings.ToUpper(field("currency")),
+			Date:      date,
+		})
+	}
+}
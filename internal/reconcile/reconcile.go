@@ -0,0 +1,223 @@
+package reconcile
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// nachaReferenceSize is the number of characters of the payout ID kept in NACHA entries.
+const nachaReferenceSize = 15
+
+// referencePattern finds payout IDs, or their NACHA prefix, in a reference stripped of its dashes.
+var referencePattern = regexp.MustCompile(`[0-9a-f]{15,32}`)
+
+// DefaultRules are the tolerances applied when none are configured.
+var DefaultRules = Rules{
+	AmountTolerance: decimal.New(1, -2),
+	DateWindow:      5 * 24 * time.Hour,
+}
+
+// Rules are the tolerances applied when matching entries to payouts.
+type Rules struct {
+	// AmountTolerance is the accepted difference between an entry and a payout amount.
+	AmountTolerance decimal.Decimal
+	// DateWindow is how long after its export a payout is expected on a statement.
+	DateWindow time.Duration
+}
+
+// Match is a statement entry matched to a payout.
+type Match struct {
+	PayoutID uuid.UUID
+	Entry    Entry
+}
+
+// Report is the outcome of a reconciliation.
+type Report struct {
+	Entries    int
+	Matches    []Match
+	Exceptions []domain.ReconciliationException
+}
+
+// Reconcile matches statement entries to exported payouts.
+// Entries are matched by the payout reference first, then by amount, currency and date.
+// Payouts expected before the last entry of the statement and not found are reported.
+func Reconcile(entries []Entry, payouts []domain.Payout, rules Rules) Report {
+	r := reconciler{
+		rules:    rules,
+		byID:     make(map[string]*domain.Payout, len(payouts)),
+		byPrefix: make(map[string]*domain.Payout, len(payouts)),
+		matched:  make(map[uuid.UUID]bool, len(payouts)),
+		report:   Report{Entries: len(entries)},
+	}
+
+	for i := range payouts {
+		id := compactID(payouts[i].ID)
+		r.byID[id] = &payouts[i]
+		r.byPrefix[id[:nachaReferenceSize]] = &payouts[i]
+	}
+
+	var unreferenced []Entry
+
+	for _, e := range entries {
+		if p := r.referenced(e); p != nil {
+			r.matchReferenced(e, p)
+
+			continue
+		}
+
+		unreferenced = append(unreferenced, e)
+	}
+
+	for _, e := range unreferenced {
+		r.matchByAmount(e, payouts)
+	}
+
+	// payouts are expected on the statement when their date window ends before its last entry.
+	var last time.Time
+
+	for _, e := range entries {
+		if e.Date.After(last) {
+			last = e.Date
+		}
+	}
+
+	for i := range payouts {
+		p := &payouts[i]
+		if r.matched[p.ID] || last.IsZero() || !exportedAt(p).Add(rules.DateWindow).Before(last) {
+			continue
+		}
+
+		id := p.ID
+		r.report.Exceptions = append(r.report.Exceptions, domain.ReconciliationException{
+			Kind:     domain.ExceptionUnmatchedPayout,
+			Message:  fmt.Sprintf("payout of %s %s exported on %s not found on the statement", amount(p), p.Currency.Code, exportedAt(p).Format("2006-01-02")),
+			PayoutID: &id,
+		})
+	}
+
+	return r.report
+}
+
+type reconciler struct {
+	rules Rules
+	// byID and byPrefix index payouts by their compact ID and its NACHA prefix.
+	byID     map[string]*domain.Payout
+	byPrefix map[string]*domain.Payout
+	matched  map[uuid.UUID]bool
+	report   Report
+}
+
+// referenced finds the payout an entry refers to, nil if none.
+func (r *reconciler) referenced(e Entry) *domain.Payout {
+	ref := strings.ToLower(strings.ReplaceAll(e.Reference, "-", ""))
+
+	for _, token := range referencePattern.FindAllString(ref, -1) {
+		if p, ok := r.byID[token]; ok {
+			return p
+		}
+
+		if p, ok := r.byPrefix[token[:nachaReferenceSize]]; ok {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (r *reconciler) matchReferenced(e Entry, p *domain.Payout) {
+	switch {
+	case r.matched[p.ID]:
+		r.exception(e, p, domain.ExceptionDuplicateEntry, "payout already matched by another entry")
+	case e.Currency != p.Currency.Code:
+		r.exception(e, p, domain.ExceptionCurrencyMismatch,
+			fmt.Sprintf("payout currency is %s, entry currency is %s", p.Currency.Code, e.Currency))
+	case !r.sameAmount(e, p):
+		r.exception(e, p, domain.ExceptionAmountMismatch,
+			fmt.Sprintf("payout amount is %s, entry amount is %s", amount(p), e.Amount))
+	default:
+		r.match(e, p)
+	}
+}
+
+// matchByAmount matches an entry without reference to the single payout of same amount and currency
+// exported within the date window.
+func (r *reconciler) matchByAmount(e Entry, payouts []domain.Payout) {
+	var candidates []*domain.Payout
+
+	for i := range payouts {
+		p := &payouts[i]
+		if r.matched[p.ID] || e.Currency != p.Currency.Code || !r.sameAmount(e, p) {
+			continue
+		}
+
+		// the bank may book the transfer the day before the export time, in its own timezone.
+		from := exportedAt(p).Add(-24 * time.Hour)
+		if e.Date.Before(from) || e.Date.After(from.Add(24*time.Hour+r.rules.DateWindow)) {
+			continue
+		}
+
+		candidates = append(candidates, p)
+	}
+
+	switch len(candidates) {
+	case 0:
+		r.exception(e, nil, domain.ExceptionUnmatchedEntry, "no payout matches the entry")
+	case 1:
+		r.match(e, candidates[0])
+	default:
+		r.exception(e, nil, domain.ExceptionAmbiguousEntry,
+			fmt.Sprintf("%d payouts match the entry amount and date", len(candidates)))
+	}
+}
+
+func (r *reconciler) sameAmount(e Entry, p *domain.Payout) bool {
+	return e.Amount.Sub(amount(p)).Abs().LessThanOrEqual(r.rules.AmountTolerance)
+}
+
+func (r *reconciler) match(e Entry, p *domain.Payout) {
+	r.matched[p.ID] = true
+	r.report.Matches = append(r.report.Matches, Match{PayoutID: p.ID, Entry: e})
+}
+
+func (r *reconciler) exception(e Entry, p *domain.Payout, kind domain.ReconciliationExceptionKind, msg string) {
+	date := e.Date
+
+	ex := domain.ReconciliationException{
+		Kind:           kind,
+		Message:        msg,
+		EntryReference: e.Reference,
+		EntryAmount:    decimal.NewNullDecimal(e.Amount),
+		EntryCurrency:  e.Currency,
+		EntryDate:      &date,
+	}
+
+	if p != nil {
+		id := p.ID
+		ex.PayoutID = &id
+	}
+
+	r.report.Exceptions = append(r.report.Exceptions, ex)
+}
+
+// amount is the payout amount as sent to the bank, rounded to the cent.
+func amount(p *domain.Payout) decimal.Decimal {
+	return p.PriceTotal.Round(2)
+}
+
+func exportedAt(p *domain.Payout) time.Time {
+	if p.ExportedAt != nil {
+		return *p.ExportedAt
+	}
+
+	return p.CreatedAt
+}
+
+func compactID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
@@ -0,0 +1,131 @@
+package reconcile
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exported = time.Date(2022, 2, 14, 13, 24, 21, 0, time.UTC)
+
+func testPayout(id, code, price string, exportedAt time.Time) domain.Payout {
+	return domain.Payout{
+		ID:         uuid.FromStringOrNil(id),
+		Status:     domain.PayoutStatusExported,
+		PriceTotal: decimal.RequireFromString(price),
+		Currency:   domain.Currency{Code: code},
+		ExportedAt: &exportedAt,
+	}
+}
+
+func TestParseCAMT053(t *testing.T) {
+	f, err := os.Open("testdata/camt053.xml")
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+
+	entries, err := ParseCAMT053(f)
+	require.NoError(t, err)
+
+	// the batch booked entry is split, the credit is ignored.
+	require.Len(t, entries, 3)
+	assert.Equal(t, "155e94968890426bb53058bf76867350", entries[0].Reference)
+	assert.Equal(t, "1082.89", entries[0].Amount.StringFixed(2))
+	assert.Equal(t, "EUR", entries[0].Currency)
+	assert.Equal(t, time.Date(2022, 2, 15, 0, 0, 0, 0, time.UTC), entries[0].Date)
+	assert.Equal(t, "Payout b55a1eae-f24b-4e61-acec-90d75bdb51b2", entries[1].Reference)
+	assert.Equal(t, "BANK-FEES-0222", entries[2].Reference)
+
+	_, err = ParseCAMT053(strings.NewReader("<Document>"))
+	assert.ErrorIs(t, err, errParseStatement)
+}
+
+func TestParseCSV(t *testing.T) {
+	entries, err := ParseCSV(strings.NewReader(
+		"reference,date,amount,currency,balance\n" +
+			"7BA5B75943B344F,2022-02-15,-1.35,usd,100\n" +
+			"refund,2022-02-15,20.00,USD,120\n"))
+	require.NoError(t, err)
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, "7BA5B75943B344F", entries[0].Reference)
+	assert.Equal(t, "1.35", entries[0].Amount.String())
+	assert.Equal(t, "USD", entries[0].Currency)
+
+	_, err = ParseCSV(strings.NewReader("date,amount\n"))
+	assert.ErrorIs(t, err, errCSVColumns)
+
+	_, err = ParseCSV(strings.NewReader("date,amount,currency,reference\n2022-02-15,abc,USD,x\n"))
+	assert.ErrorIs(t, err, errParseStatement)
+}
+
+func TestReconcile(t *testing.T) {
+	payouts := []domain.Payout{
+		// matched by end to end reference.
+		testPayout("155e9496-8890-426b-b530-58bf76867350", "EUR", "1082.8915", exported),
+		// matched by the remittance information.
+		testPayout("b55a1eae-f24b-4e61-acec-90d75bdb51b2", "EUR", "10", exported),
+		// matched by the NACHA individual identification.
+		testPayout("7ba5b759-43b3-44f4-9c25-6377975836b7", "USD", "1.354", exported),
+		// matched by amount and date.
+		testPayout("7cee6ac2-116a-418c-887e-5c46e7db2c2a", "USD", "99.999", exported),
+		// referenced with a wrong amount.
+		testPayout("4d1cea0f-e45d-4773-891e-4543c99dab62", "EUR", "30", exported),
+		// same amount and date, cannot be told apart.
+		testPayout("3c8c3d1c-5d9a-4a43-9bb4-2a0e5f1c9f10", "GBP", "5", exported),
+		testPayout("9f2b7c1e-3d4a-4b5c-8d6e-7f8091a2b3c4", "GBP", "5", exported),
+		// exported long before the statement and never booked.
+		testPayout("0f3bd0b6-1d0f-4a4e-8a59-3c8a1b8c3e01", "EUR", "7", exported.AddDate(0, 0, -10)),
+		// exported after the statement, not expected yet.
+		testPayout("a8f3d5e2-4c1b-4f7a-9d2e-6b5c4a3f2e10", "EUR", "8", exported.AddDate(0, 0, 8)),
+	}
+
+	day := time.Date(2022, 2, 15, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Reference: "155e94968890426bb53058bf76867350", Amount: decimal.RequireFromString("1082.89"), Currency: "EUR", Date: day},
+		{Reference: "Payout B55A1EAE-F24B-4E61-ACEC-90D75BDB51B2", Amount: decimal.RequireFromString("10"), Currency: "EUR", Date: day},
+		{Reference: "7BA5B75943B344F", Amount: decimal.RequireFromString("1.35"), Currency: "USD", Date: day},
+		{Reference: "155e94968890426bb53058bf76867350", Amount: decimal.RequireFromString("1082.89"), Currency: "EUR", Date: day},
+		{Reference: "ACH CREDIT", Amount: decimal.RequireFromString("100.00"), Currency: "USD", Date: day},
+		{Reference: "4d1cea0fe45d4773891e4543c99dab62", Amount: decimal.RequireFromString("3"), Currency: "EUR", Date: day},
+		{Reference: "", Amount: decimal.RequireFromString("5"), Currency: "GBP", Date: day},
+		{Reference: "BANK-FEES-0222", Amount: decimal.RequireFromString("42.50"), Currency: "EUR", Date: day},
+		// booked before the export of the payout of same amount.
+		{Reference: "", Amount: decimal.RequireFromString("8"), Currency: "EUR", Date: day},
+	}
+
+	report := Reconcile(entries, payouts, DefaultRules)
+
+	assert.Equal(t, len(entries), report.Entries)
+
+	matched := make([]uuid.UUID, 0, len(report.Matches))
+	for _, m := range report.Matches {
+		matched = append(matched, m.PayoutID)
+	}
+
+	assert.Equal(t, []uuid.UUID{payouts[0].ID, payouts[1].ID, payouts[2].ID, payouts[3].ID}, matched)
+
+	kinds := make([]domain.ReconciliationExceptionKind, 0, len(report.Exceptions))
+	for _, ex := range report.Exceptions {
+		kinds = append(kinds, ex.Kind)
+	}
+
+	assert.Equal(t, []domain.ReconciliationExceptionKind{
+		domain.ExceptionDuplicateEntry,
+		domain.ExceptionAmountMismatch,
+		domain.ExceptionAmbiguousEntry,
+		domain.ExceptionUnmatchedEntry,
+		domain.ExceptionUnmatchedEntry,
+		domain.ExceptionUnmatchedPayout,
+	}, kinds)
+
+	require.NotNil(t, report.Exceptions[1].PayoutID)
+	assert.Equal(t, payouts[4].ID, *report.Exceptions[1].PayoutID)
+	assert.Equal(t, payouts[7].ID, *report.Exceptions[5].PayoutID)
+}
@@ -0,0 +1,257 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/logger"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	errInvalidJob   = errors.New("invalid job")
+	errDuplicateJob = errors.New("job already registered")
+	errInvalidSpec  = errors.New("invalid job schedule")
+	errTimezone     = errors.New("invalid job timezone")
+	errJobFailed    = errors.New("job failed")
+	errCircuitOpen  = errors.New("job circuit opened, runs are skipped")
+	// ErrStopped is returned when a run is started once the scheduler stopped.
+	ErrStopped = errors.New("scheduler stopped")
+)
+
+// Job is a task run on a schedule.
+type Job struct {
+	Name string
+	// Spec is a cron expression, e.g. "0 2 * * 1-5" for 02:00 on business days,
+	// or a descriptor such as "@hourly" or "@every 10s".
+	Spec string
+	// Timezone is the IANA name of the time zone Spec is expressed in, UTC by default.
+	Timezone string
+	// RunOnStart runs the job once when the scheduler starts, before its first scheduled run.
+	RunOnStart bool
+	// Jitter delays each run by a random duration up to Jitter, spreading the load of replicas.
+	Jitter time.Duration
+	// Retry is how a failed run is attempted again before the run is considered failed.
+	Retry Retry
+	// Breaker stops running the job for a while after consecutive failed runs.
+	Breaker Breaker
+	// Run is given a context cancelled when the scheduler stops, it should return soon after.
+	Run func(ctx context.Context) error
+}
+
+// Retry is the retry policy of a failed run.
+type Retry struct {
+	// Attempts is the maximum number of attempts of a run, a single attempt is made when zero.
+	Attempts int
+	// Backoff is the delay before the second attempt, doubled on every following attempt.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts, the delay is not capped when zero.
+	MaxBackoff time.Duration
+}
+
+// backoff returns the delay before the attempt following the given failed attempt, counted from 1.
+func (r Retry) backoff(attempt int) time.Duration {
+	d := r.Backoff << (attempt - 1)
+	if r.MaxBackoff > 0 && (d > r.MaxBackoff || d <= 0) {
+		return r.MaxBackoff
+	}
+
+	return d
+}
+
+// Breaker is the circuit breaker of a job.
+type Breaker struct {
+	// Threshold is the number of consecutive failed runs opening the circuit, it never opens when zero.
+	Threshold int
+	// Cooldown is how long the runs are skipped once the circuit is open, the next run then closes it
+	// if it succeeds, or opens it again.
+	Cooldown time.Duration
+}
+
+type entry struct {
+	Job
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// next returns the time of the run following t, without jitter.
+func (e entry) next(t time.Time) time.Time {
+	return e.schedule.Next(t.In(e.location))
+}
+
+// Scheduler is a registry of jobs, each run on its own schedule.
+// A run of a job never overlaps the previous one, a late run is started as soon as the previous one ends.
+// A failed run is logged and reported in the job state, it does not stop the scheduler.
+type Scheduler struct {
+	log     logger.Logger
+	entries []entry
+	now     func() time.Time
+	jitter  func(max time.Duration) time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu     sync.Mutex
+	states map[string]*domain.JobState
+}
+
+// New returns an empty Scheduler logging the job failures.
+func New(log logger.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		log:    log,
+		now:    time.Now,
+		jitter: randomJitter,
+		ctx:    ctx,
+		cancel: cancel,
+		states: make(map[string]*domain.JobState),
+	}
+}
+
+func randomJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	//nolint:gosec // jitter does not need a cryptographically secure source.
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// Register adds a job, it must be called before Start.
+func (s *Scheduler) Register(j Job) error {
+	if j.Name == "" || j.Run == nil {
+		return fmt.Errorf("%w: name and run are required", errInvalidJob)
+	}
+
+	for _, e := range s.entries {
+		if e.Name == j.Name {
+			return fmt.Errorf("%w: %s", errDuplicateJob, j.Name)
+		}
+	}
+
+	schedule, err := cron.ParseStandard(j.Spec)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %s", errInvalidSpec, j.Name, err)
+	}
+
+	location := time.UTC
+
+	if j.Timezone != "" {
+		if location, err = time.LoadLocation(j.Timezone); err != nil {
+			return fmt.Errorf("%w: %s: %s", errTimezone, j.Name, err)
+		}
+	}
+
+	s.entries = append(s.entries, entry{Job: j, schedule: schedule, location: location})
+	s.states[j.Name] = &domain.JobState{Job: j.Name}
+
+	return nil
+}
+
+// Start runs the registered jobs in the background until Stop is called.
+func (s *Scheduler) Start() {
+	for _, e := range s.entries {
+		s.wg.Add(1)
+
+		go s.run(e)
+	}
+}
+
+// Stop stops scheduling runs, cancels the context of the runs in progress and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Go runs an unscheduled run in the background, e.g. a run triggered on demand, with the context
+// of the scheduled runs: Stop cancels it and waits for it to return as well.
+// ErrStopped is returned once the scheduler stopped.
+func (s *Scheduler) Go(run func(ctx context.Context)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return ErrStopped
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		run(s.ctx)
+	}()
+
+	return nil
+}
+
+func (s *Scheduler) run(e entry) {
+	defer s.wg.Done()
+
+	if e.RunOnStart {
+		s.exec(e)
+	}
+
+	for {
+		timer := time.NewTimer(s.delay(e))
+
+		select {
+		case <-timer.C:
+			s.exec(e)
+		case <-s.ctx.Done():
+			timer.Stop()
+
+			return
+		}
+	}
+}
+
+// delay returns the time to wait for the next run of a job.
+func (s *Scheduler) delay(e entry) time.Duration {
+	now := s.now()
+
+	return e.next(now).Sub(now) + s.jitter(e.Jitter)
+}
+
+func (s *Scheduler) exec(e entry) {
+	if !s.begin(e) {
+		return
+	}
+
+	err := s.attempt(e)
+
+	s.end(e, err)
+}
+
+// attempt runs a job until it succeeds or its retry policy is exhausted,
+// the attempts left are abandoned when the scheduler stops.
+func (s *Scheduler) attempt(e entry) error {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if err = e.Run(s.ctx); err == nil || attempt >= e.Retry.Attempts {
+			return err
+		}
+
+		s.log.Error(fmt.Errorf("%w: %s: attempt %d: %s", errJobFailed, e.Name, attempt, err))
+
+		timer := time.NewTimer(e.Retry.backoff(attempt))
+
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noop(context.Context) error { return nil }
+
+func TestScheduler_Register(t *testing.T) {
+	s := New(nil)
+
+	require.NoError(t, s.Register(Job{Name: "payouts", Spec: "0 2 * * 1-5", Timezone: "Europe/Paris", Run: noop}))
+
+	tests := map[string]struct {
+		job Job
+		err error
+	}{
+		"missing-name":     {Job{Spec: "@hourly", Run: noop}, errInvalidJob},
+		"missing-run":      {Job{Name: "a", Spec: "@hourly"}, errInvalidJob},
+		"duplicate":        {Job{Name: "payouts", Spec: "@hourly", Run: noop}, errDuplicateJob},
+		"invalid-spec":     {Job{Name: "a", Spec: "0 25 * * *", Run: noop}, errInvalidSpec},
+		"invalid-timezone": {Job{Name: "a", Spec: "@hourly", Timezone: "Mars/Olympus", Run: noop}, errTimezone},
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			assert.ErrorIs(t, s.Register(tc.job), tc.err)
+		})
+	}
+}
+
+func TestEntry_Next(t *testing.T) {
+	s := New(nil)
+	require.NoError(t, s.Register(Job{Name: "utc", Spec: "0 2 * * 1-5", Run: noop}))
+	require.NoError(t, s.Register(Job{Name: "paris", Spec: "0 2 * * 1-5", Timezone: "Europe/Paris", Run: noop}))
+	require.NoError(t, s.Register(Job{Name: "every", Spec: "@every 10s", Run: noop}))
+
+	// Friday 2022-02-18 03:00 UTC.
+	now := time.Date(2022, 2, 18, 3, 0, 0, 0, time.UTC)
+
+	// the next business day is Monday.
+	assert.True(t, time.Date(2022, 2, 21, 2, 0, 0, 0, time.UTC).Equal(s.entries[0].next(now)))
+	// 02:00 in Paris is 01:00 UTC in winter.
+	assert.True(t, time.Date(2022, 2, 21, 1, 0, 0, 0, time.UTC).Equal(s.entries[1].next(now)))
+	assert.True(t, now.Add(10*time.Second).Equal(s.entries[2].next(now)))
+}
+
+func TestRandomJitter(t *testing.T) {
+	assert.Zero(t, randomJitter(0))
+
+	for i := 0; i < 100; i++ {
+		j := randomJitter(time.Minute)
+		assert.True(t, j >= 0 && j < time.Minute)
+	}
+}
+
+func TestScheduler_RunOnStart(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	ml.EXPECT().Error(gomock.Any())
+
+	ran := make(chan string, 2)
+
+	s := New(ml)
+	require.NoError(t, s.Register(Job{Name: "ok", Spec: "@yearly", RunOnStart: true, Run: func(context.Context) error {
+		ran <- "ok"
+
+		return nil
+	}}))
+	require.NoError(t, s.Register(Job{Name: "ko", Spec: "@yearly", RunOnStart: true, Run: func(context.Context) error {
+		ran <- "ko"
+
+		return errors.New("mock")
+	}}))
+	require.NoError(t, s.Register(Job{Name: "later", Spec: "@yearly", Run: func(context.Context) error {
+		ran <- "later"
+
+		return nil
+	}}))
+
+	s.Start()
+
+	got := []string{<-ran, <-ran}
+	assert.ElementsMatch(t, []string{"ok", "ko"}, got)
+
+	s.Stop()
+	assert.Empty(t, ran)
+}
+
+func TestScheduler_Delay(t *testing.T) {
+	s := New(nil)
+	s.now = func() time.Time { return time.Date(2022, 2, 18, 3, 0, 59, 0, time.UTC) }
+	s.jitter = func(max time.Duration) time.Duration { return max / 2 }
+
+	require.NoError(t, s.Register(Job{Name: "a", Spec: "* * * * *", Run: noop}))
+	require.NoError(t, s.Register(Job{Name: "b", Spec: "* * * * *", Jitter: time.Hour, Run: noop}))
+
+	assert.Equal(t, time.Second, s.delay(s.entries[0]))
+	assert.Equal(t, time.Second+30*time.Minute, s.delay(s.entries[1]))
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	r := Retry{Attempts: 5, Backoff: time.Second, MaxBackoff: 3 * time.Second}
+
+	assert.Equal(t, time.Second, r.backoff(1))
+	assert.Equal(t, 2*time.Second, r.backoff(2))
+	assert.Equal(t, 3*time.Second, r.backoff(3))
+	assert.Equal(t, 3*time.Second, r.backoff(64))
+	assert.Equal(t, 8*time.Second, Retry{Backoff: time.Second}.backoff(4))
+}
+
+func TestScheduler_Retry(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	// the first two attempts fail.
+	ml.EXPECT().Error(gomock.Any()).Times(2)
+
+	attempts := 0
+
+	s := New(ml)
+	require.NoError(t, s.Register(Job{
+		Name:  "flaky",
+		Spec:  "@yearly",
+		Retry: Retry{Attempts: 3, Backoff: time.Millisecond},
+		Run: func(context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("mock")
+			}
+
+			return nil
+		},
+	}))
+
+	s.exec(s.entries[0])
+
+	assert.Equal(t, 3, attempts)
+
+	st := s.States()[0]
+	assert.Zero(t, st.ConsecutiveFailures)
+	assert.NotNil(t, st.LastSuccessAt)
+	assert.False(t, st.Running)
+}
+
+func TestScheduler_RetryStopped(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	// the failed attempt is logged, the run interrupted by the stop is not a failure of the job.
+	ml.EXPECT().Error(gomock.Any())
+	ml.EXPECT().Info(gomock.Any())
+
+	attempts := 0
+
+	s := New(ml)
+	require.NoError(t, s.Register(Job{
+		Name:  "flaky",
+		Spec:  "@yearly",
+		Retry: Retry{Attempts: 3, Backoff: time.Hour},
+		Run: func(context.Context) error {
+			attempts++
+
+			return errors.New("mock")
+		},
+	}))
+
+	s.cancel()
+	s.exec(s.entries[0])
+
+	st := s.States()[0]
+	assert.Equal(t, 1, attempts)
+	assert.Zero(t, st.ConsecutiveFailures)
+	assert.Equal(t, "mock", st.LastError)
+}
+
+func TestScheduler_StopCancelsRuns(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	ml := mock.NewMockLogger(mc)
+	ml.EXPECT().Info(gomock.Any())
+
+	started := make(chan struct{})
+
+	s := New(ml)
+	require.NoError(t, s.Register(Job{Name: "long", Spec: "@yearly", RunOnStart: true, Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+
+		return ctx.Err()
+	}}))
+
+	s.Start()
+	<-started
+	s.Stop()
+
+	st := s.States()[0]
+	assert.False(t, st.Running)
+	assert.Zero(t, st.ConsecutiveFailures)
+	assert.Equal(t, context.Canceled.Error(), st.LastError)
+}
+
+func TestScheduler_Breaker(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	now := time.Date(2022, 2, 18, 3, 0, 0, 0, time.UTC)
+	fail := true
+	runs := 0
+
+	ml := mock.NewMockLogger(mc)
+
+	s := New(ml)
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.Register(Job{
+		Name:    "currencies",
+		Spec:    "@hourly",
+		Breaker: Breaker{Threshold: 2, Cooldown: time.Hour},
+		Run: func(context.Context) error {
+			runs++
+			if fail {
+				return errors.New("mock")
+			}
+
+			return nil
+		},
+	}))
+
+	e := s.entries[0]
+
+	// two failed runs, the second opens the circuit.
+	ml.EXPECT().Error(gomock.Any()).Times(3)
+	s.exec(e)
+	s.exec(e)
+
+	st := s.States()[0]
+	require.NotNil(t, st.OpenUntil)
+	assert.True(t, now.Add(time.Hour).Equal(*st.OpenUntil))
+	assert.Equal(t, "mock", st.LastError)
+
+	// runs are skipped while the circuit is open.
+	ml.EXPECT().Info(gomock.Any())
+	now = now.Add(30 * time.Minute)
+	s.exec(e)
+	assert.Equal(t, 2, runs)
+
+	// after the cooldown, a successful run closes the circuit.
+	fail = false
+	now = now.Add(time.Hour)
+	s.exec(e)
+
+	st = s.States()[0]
+	assert.Equal(t, 3, runs)
+	assert.Nil(t, st.OpenUntil)
+	assert.Zero(t, st.ConsecutiveFailures)
+	assert.Empty(t, st.LastError)
+}
+
+func TestScheduler_Go(t *testing.T) {
+	s := New(nil)
+
+	started, stopped := make(chan struct{}), make(chan struct{})
+
+	require.NoError(t, s.Go(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(stopped)
+	}))
+
+	<-started
+	s.Stop()
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("Stop returned before the run")
+	}
+
+	assert.ErrorIs(t, s.Go(func(context.Context) { t.Error("run started once stopped") }), ErrStopped)
+}
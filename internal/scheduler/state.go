@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+)
+
+//go:generate mockgen -source=state.go -destination=$MOCK_FOLDER/scheduler.go -package=mock
+
+// Monitor reports the state of the jobs.
+type Monitor interface {
+	States() []domain.JobState
+}
+
+// States returns the state of the jobs sorted by name.
+func (s *Scheduler) States() []domain.JobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]domain.JobState, 0, len(s.states))
+	for _, st := range s.states {
+		states = append(states, *st)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].Job < states[j].Job })
+
+	return states
+}
+
+// begin marks a job as running, false is returned when its circuit is open and the run is skipped.
+func (s *Scheduler) begin(e entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.states[e.Name]
+	now := s.now()
+
+	if st.OpenUntil != nil && now.Before(*st.OpenUntil) {
+		s.log.Info(fmt.Sprintf("job %s skipped, circuit open until %s", e.Name, st.OpenUntil.Format(time.RFC3339)))
+
+		return false
+	}
+
+	st.Running = true
+	st.LastRunAt = &now
+
+	return true
+}
+
+// end records the outcome of a run and opens the circuit of the job after too many failed runs.
+func (s *Scheduler) end(e entry, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.states[e.Name]
+	now := s.now()
+
+	st.Running = false
+
+	if err == nil {
+		st.ConsecutiveFailures = 0
+		st.LastSuccessAt = &now
+		st.LastError = ""
+		st.OpenUntil = nil
+
+		return
+	}
+
+	st.LastError = err.Error()
+
+	// a run interrupted by the scheduler stopping is not a failure of the job.
+	if s.ctx.Err() != nil {
+		s.log.Info(fmt.Sprintf("job %s interrupted by the scheduler stopping: %s", e.Name, err))
+
+		return
+	}
+
+	st.ConsecutiveFailures++
+	s.log.Error(fmt.Errorf("%w: %s: %s", errJobFailed, e.Name, err))
+
+	if e.Breaker.Threshold > 0 && st.ConsecutiveFailures >= e.Breaker.Threshold {
+		until := now.Add(e.Breaker.Cooldown)
+		st.OpenUntil = &until
+		s.log.Error(fmt.Errorf("%w: %s: %d consecutive failures, until %s",
+			errCircuitOpen, e.Name, st.ConsecutiveFailures, until.Format(time.RFC3339)))
+	}
+}
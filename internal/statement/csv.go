@@ -0,0 +1,52 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+var csvHeader = []string{
+	"date", "payout_id", "item_id", "item_name", "amount", "currency",
+	"exchange_rate", "converted_amount", "payout_total", "payout_currency",
+}
+
+// WriteCSV writes the statement as CSV, one row per paid out item.
+// Opening and closing balances are written as the first and last rows.
+func WriteCSV(w io.Writer, s Statement) error {
+	cw := csv.NewWriter(w)
+
+	balance := func(label string, at time.Time, amount string) []string {
+		row := make([]string, len(csvHeader))
+		row[0], row[1], row[8], row[9] = at.Format(dateLayout), label, amount, s.Currency
+
+		return row
+	}
+
+	rows := [][]string{csvHeader, balance("opening_balance", s.From, s.OpeningBalance.String())}
+
+	for _, p := range s.Payouts {
+		for _, line := range p.Lines {
+			rows = append(rows, []string{
+				p.CreatedAt.Format(dateLayout),
+				p.ID.String(),
+				line.ItemID.String(),
+				line.Item.ReferenceName,
+				line.Item.PriceAmount.String(),
+				line.Item.CurrencyCode,
+				nullDecimalString(line.ExchangeRate),
+				nullDecimalString(line.ConvertedAmount),
+				p.PriceTotal.String(),
+				p.Currency.Code,
+			})
+		}
+	}
+
+	rows = append(rows, balance("closing_balance", s.To, s.ClosingBalance.String()))
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
@@ -0,0 +1,75 @@
+package statement
+
+import (
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pdfFont       = "Helvetica"
+	pdfLineHeight = 6
+)
+
+// pdfColumns are the item table columns with their width in mm, they fill an A4 landscape page.
+var pdfColumns = []struct {
+	title string
+	width float64
+}{
+	{"Date", 22}, {"Payout", 62}, {"Item", 79}, {"Amount", 28},
+	{"Rate", 28}, {"Converted", 28},
+}
+
+// WritePDF writes the statement as an A4 PDF document, items are grouped by payout.
+func WritePDF(w io.Writer, s Statement) error {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle("Payout statement", true)
+	pdf.AddPage()
+
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.CellFormat(0, 10, "Payout statement", "", 1, "", false, 0, "")
+
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.CellFormat(0, pdfLineHeight, "Seller: "+s.SellerID.String(), "", 1, "", false, 0, "")
+	pdf.CellFormat(0, pdfLineHeight, "Period: "+s.From.Format(dateLayout)+" to "+s.To.Format(dateLayout)+" (excluded)", "", 1, "", false, 0, "")
+	pdf.CellFormat(0, pdfLineHeight, "Opening balance: "+s.OpeningBalance.String()+" "+s.Currency, "", 1, "", false, 0, "")
+	pdf.Ln(pdfLineHeight)
+
+	pdf.SetFont(pdfFont, "B", 9)
+
+	for _, c := range pdfColumns {
+		pdf.CellFormat(c.width, pdfLineHeight, c.title, "B", 0, "", false, 0, "")
+	}
+
+	pdf.Ln(-1)
+
+	for _, p := range s.Payouts {
+		pdf.SetFont(pdfFont, "", 9)
+
+		for _, line := range p.Lines {
+			cells := []string{
+				p.CreatedAt.Format(dateLayout),
+				p.ID.String(),
+				line.Item.ReferenceName,
+				line.Item.PriceAmount.String() + " " + line.Item.CurrencyCode,
+				nullDecimalString(line.ExchangeRate),
+				nullDecimalString(line.ConvertedAmount),
+			}
+
+			for i, c := range pdfColumns {
+				pdf.CellFormat(c.width, pdfLineHeight, cells[i], "", 0, "", false, 0, "")
+			}
+
+			pdf.Ln(-1)
+		}
+
+		pdf.SetFont(pdfFont, "B", 9)
+		pdf.CellFormat(0, pdfLineHeight, "Payout total: "+p.PriceTotal.String()+" "+p.Currency.Code, "T", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(pdfLineHeight)
+	pdf.SetFont(pdfFont, "B", 10)
+	pdf.CellFormat(0, pdfLineHeight, "Closing balance: "+s.ClosingBalance.String()+" "+s.Currency, "", 1, "", false, 0, "")
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,64 @@
+package statement
+
+import (
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// FormatCSV is the CSV statement format.
+	FormatCSV = "csv"
+	// FormatPDF is the PDF statement format.
+	FormatPDF = "pdf"
+
+	dateLayout = "2006-01-02"
+)
+
+// Statement lists the payouts of a seller over a period.
+// Balances are the cumulated amount paid out to the seller, in the seller currency.
+type Statement struct {
+	SellerID uuid.UUID
+	Currency string
+	// From is inclusive and To exclusive.
+	From time.Time
+	To   time.Time
+
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	Payouts        []domain.Payout
+}
+
+// New builds the statement of a seller out of the payouts of the period,
+// opening is the balance before the period.
+func New(seller domain.Seller, from, to time.Time, opening decimal.Decimal, payouts []domain.Payout) Statement {
+	closing := opening
+	for _, p := range payouts {
+		closing = closing.Add(p.PriceTotal)
+	}
+
+	return Statement{
+		SellerID:       seller.ID,
+		Currency:       seller.CurrencyCode,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ClosingBalance: closing,
+		Payouts:        payouts,
+	}
+}
+
+// Filename is the name under which the statement is downloaded.
+func (s Statement) Filename(format string) string {
+	return "statement_" + s.SellerID.String() + "_" + s.From.Format(dateLayout) + "_" + s.To.Format(dateLayout) + "." + format
+}
+
+func nullDecimalString(d decimal.NullDecimal) string {
+	if !d.Valid {
+		return ""
+	}
+
+	return d.Decimal.String()
+}
@@ -0,0 +1,87 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/currency"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatement() Statement {
+	seller := domain.Seller{ID: uuid.Must(uuid.NewV4()), CurrencyCode: currency.USDCode}
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	payouts := []domain.Payout{
+		{
+			ID:         uuid.Must(uuid.NewV4()),
+			CreatedAt:  from.Add(time.Hour),
+			PriceTotal: decimal.RequireFromString("2.708"),
+			Currency:   domain.Currency{Code: currency.USDCode},
+			Lines: []domain.PayoutItem{
+				{
+					ItemID:          uuid.Must(uuid.NewV4()),
+					Item:            domain.Item{ReferenceName: "bag", PriceAmount: decimal.NewFromInt(1), CurrencyCode: currency.GBPCode},
+					ConvertedAmount: decimal.NewNullDecimal(decimal.RequireFromString("1.354")),
+					ExchangeRate:    decimal.NewNullDecimal(decimal.RequireFromString("1.354")),
+				},
+				{
+					ItemID: uuid.Must(uuid.NewV4()),
+					Item:   domain.Item{ReferenceName: "shoes", PriceAmount: decimal.NewFromInt(1), CurrencyCode: currency.GBPCode},
+				},
+			},
+		},
+		{
+			ID:         uuid.Must(uuid.NewV4()),
+			CreatedAt:  from.Add(2 * time.Hour),
+			PriceTotal: decimal.NewFromInt(10),
+			Currency:   domain.Currency{Code: currency.USDCode},
+			Lines:      []domain.PayoutItem{{Item: domain.Item{ReferenceName: "hat", PriceAmount: decimal.NewFromInt(10)}}},
+		},
+	}
+
+	return New(seller, from, from.AddDate(0, 1, 0), decimal.NewFromInt(100), payouts)
+}
+
+func TestNew(t *testing.T) {
+	s := testStatement()
+
+	assert.True(t, s.OpeningBalance.Equal(decimal.NewFromInt(100)))
+	assert.True(t, s.ClosingBalance.Equal(decimal.RequireFromString("112.708")))
+	assert.Equal(t, currency.USDCode, s.Currency)
+	assert.Equal(t, "statement_"+s.SellerID.String()+"_2022-01-01_2022-02-01.csv", s.Filename(FormatCSV))
+}
+
+func TestWriteCSV(t *testing.T) {
+	s := testStatement()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, s))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+
+	// header, opening balance, 3 items and closing balance.
+	require.Len(t, rows, 6)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"2022-01-01", "opening_balance", "", "", "", "", "", "", "100", "USD"}, rows[1])
+	assert.Equal(t, []string{
+		"2022-01-01", s.Payouts[0].ID.String(), s.Payouts[0].Lines[0].ItemID.String(),
+		"bag", "1", "GBP", "1.354", "1.354", "2.708", "USD",
+	}, rows[2])
+	assert.Equal(t, "", rows[3][6], "missing conversions should be left empty")
+	assert.Equal(t, []string{"2022-02-01", "closing_balance", "", "", "", "", "", "", "112.708", "USD"}, rows[5])
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, testStatement()))
+
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+}
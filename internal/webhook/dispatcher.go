@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+)
+
+const (
+	// maxAttempts is the number of attempts before a delivery is dead lettered.
+	maxAttempts = 8
+	// backoff is the delay before the second attempt, doubled on every following one.
+	backoff = 30 * time.Second
+	// batchSize is the maximum number of deliveries sent by a Deliver call.
+	batchSize = 100
+	timeout   = 10 * time.Second
+)
+
+var (
+	errEncodeEvent = errors.New("failed to encode event")
+	errStatus      = errors.New("unexpected response status")
+)
+
+// Dispatcher stores the events published as deliveries and sends them to the subscriptions.
+type Dispatcher struct {
+	repos       db.UnitOfWork
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+}
+
+// New returns a Dispatcher storing its deliveries with the webhooks repository of repos.
+func New(repos db.UnitOfWork) *Dispatcher {
+	return &Dispatcher{
+		repos:       repos,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		now:         time.Now,
+	}
+}
+
+// Publish creates a delivery of an outbox message for each active subscription to its type,
+// the message ID is the event ID so that receivers can deduplicate messages published twice.
+func (d *Dispatcher) Publish(ctx context.Context, m domain.OutboxMessage) error {
+	return d.enqueue(ctx, Event{ID: m.ID, Type: m.EventType, CreatedAt: m.CreatedAt, Data: json.RawMessage(m.Payload)})
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, e Event) error {
+	subs, err := d.repos.Webhooks().FindActiveSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errEncodeEvent, err)
+	}
+
+	var deliveries []domain.WebhookDelivery
+
+	for _, s := range subs {
+		if !s.Subscribes(e.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			EventID:        e.ID,
+			EventType:      e.Type,
+			Payload:        payload,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  d.now(),
+			SubscriptionID: s.ID,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := d.repos.Webhooks().Enqueue(ctx, deliveries); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	return nil
+}
+
+// Deliver sends the due deliveries. A failed delivery is retried with an exponential backoff
+// until it runs out of attempts and is dead lettered. The deliveries left once ctx is done are sent on the next call.
+func (d *Dispatcher) Deliver(ctx context.Context) error {
+	deliveries, err := d.repos.Webhooks().FindDueDeliveries(ctx, d.now(), batchSize)
+	if err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	for _, w := range deliveries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := d.deliver(ctx, w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, w domain.WebhookDelivery) error {
+	err := d.send(ctx, w)
+	now := d.now()
+	w.Attempts++
+
+	if err == nil {
+		w.Status = domain.WebhookDeliveryDelivered
+		w.DeliveredAt = &now
+		w.LastError = ""
+
+		return d.update(ctx, w)
+	}
+
+	w.LastError = err.Error()
+
+	if w.Attempts < d.maxAttempts {
+		w.NextAttemptAt = now.Add(d.backoff << (w.Attempts - 1))
+
+		return d.update(ctx, w)
+	}
+
+	w.Status = domain.WebhookDeliveryDead
+
+	return d.repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+		if err := tx.Webhooks().UpdateDelivery(ctx, w); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		if err := tx.Webhooks().AddDeadLetter(ctx, &domain.WebhookDeadLetter{
+			DeliveryID: w.ID,
+			Attempts:   w.Attempts,
+			LastError:  w.LastError,
+		}); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrDB, err)
+		}
+
+		return nil
+	})
+}
+
+func (d *Dispatcher) update(ctx context.Context, w domain.WebhookDelivery) error {
+	if err := d.repos.Webhooks().UpdateDelivery(ctx, w); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrDB, err)
+	}
+
+	return nil
+}
+
+// send posts the signed payload, any status but 2xx is a failure.
+func (d *Dispatcher) send(ctx context.Context, w domain.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.Subscription.URL, bytes.NewReader(w.Payload))
+	if err != nil {
+		return err
+	}
+
+	ts := strconv.FormatInt(d.now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, w.EventID.String())
+	req.Header.Set(EventHeader, string(w.EventType))
+	req.Header.Set(SignatureHeader, fmt.Sprintf("t=%s,v1=%s", ts, Sign(w.Subscription.Secret, ts, w.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %d", errStatus, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// SignatureHeader holds the timestamp and the signature of a payload: t=<unix time>,v1=<hex HMAC-SHA256>.
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader holds the event type.
+	EventHeader = "X-Webhook-Event"
+	// IDHeader holds the event ID, the same on every attempt so that receivers can deduplicate.
+	IDHeader = "X-Webhook-ID"
+)
+
+// Event is the payload sent to subscriptions.
+type Event struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      domain.EventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      interface{}      `json:"data"`
+}
+
+// ItemCreated is the data of an item.created event.
+type ItemCreated struct {
+	ItemID        uuid.UUID       `json:"item_id"`
+	SellerID      uuid.UUID       `json:"seller_id"`
+	ReferenceName string          `json:"reference_name"`
+	PriceAmount   decimal.Decimal `json:"price_amount"`
+	CurrencyCode  string          `json:"currency_code"`
+}
+
+// NewItemCreated returns the data of an item.created event.
+func NewItemCreated(i domain.Item) ItemCreated {
+	return ItemCreated{
+		ItemID:        i.ID,
+		SellerID:      i.SellerID,
+		ReferenceName: i.ReferenceName,
+		PriceAmount:   i.PriceAmount,
+		CurrencyCode:  i.CurrencyCode,
+	}
+}
+
+// PayoutCreated is the data of a payout.created event.
+type PayoutCreated struct {
+	PayoutID     uuid.UUID       `json:"payout_id"`
+	SellerID     uuid.UUID       `json:"seller_id"`
+	PriceTotal   decimal.Decimal `json:"price_total"`
+	CurrencyCode string          `json:"currency_code"`
+	ItemIDs      []uuid.UUID     `json:"item_ids"`
+}
+
+// NewPayoutCreated returns the data of a payout.created event, the payout Currency must be loaded.
+func NewPayoutCreated(p domain.Payout) PayoutCreated {
+	ids := make([]uuid.UUID, 0, len(p.Lines))
+	for _, l := range p.Lines {
+		ids = append(ids, l.ItemID)
+	}
+
+	return PayoutCreated{
+		PayoutID:     p.ID,
+		SellerID:     p.SellerID,
+		PriceTotal:   p.PriceTotal,
+		CurrencyCode: p.Currency.Code,
+		ItemIDs:      ids,
+	}
+}
+
+// PayoutStatusChanged is the data of a payout.status_changed event.
+type PayoutStatusChanged struct {
+	PayoutID uuid.UUID           `json:"payout_id"`
+	From     domain.PayoutStatus `json:"from"`
+	To       domain.PayoutStatus `json:"to"`
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and the payload joined by a dot.
+// The timestamp is signed so that receivers can reject replayed requests.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
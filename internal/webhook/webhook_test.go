@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/mock"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "s3cr3t"
+
+var testNow = time.Date(2022, 2, 14, 13, 24, 21, 0, time.UTC)
+
+func testDispatcher(mdb *mock.MockDB) *Dispatcher {
+	d := New(db.NewUnitOfWork(mdb))
+	d.now = func() time.Time { return testNow }
+
+	return d
+}
+
+// testMessage returns an outbox message of the event type.
+func testMessage(t domain.EventType) domain.OutboxMessage {
+	return domain.OutboxMessage{ID: uuid.Must(uuid.NewV4()), CreatedAt: testNow, EventType: t, Payload: []byte("{}")}
+}
+
+// receiver is a subscriber endpoint answering with status and recording the requests it got.
+func receiver(t *testing.T, status int) (*httptest.Server, *[]*http.Request) {
+	t.Helper()
+
+	var got []*http.Request
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		ts, sig := parseSignature(r.Header.Get(SignatureHeader))
+		assert.Equal(t, Sign(testSecret, ts, body), sig)
+		assert.Equal(t, strconv.FormatInt(testNow.Unix(), 10), ts)
+
+		got = append(got, r)
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &got
+}
+
+func parseSignature(h string) (string, string) {
+	var ts, sig string
+
+	_, _ = fmt.Sscanf(h, "t=%10s,v1=%s", &ts, &sig)
+
+	return ts, sig
+}
+
+func testDelivery(url string, attempts int) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:        uuid.Must(uuid.NewV4()),
+		EventID:   uuid.Must(uuid.NewV4()),
+		EventType: domain.EventPayoutCreated,
+		Payload:   []byte(`{"type":"payout.created"}`),
+		Status:    domain.WebhookDeliveryPending,
+		Attempts:  attempts,
+		Subscription: domain.WebhookSubscription{
+			URL:    url,
+			Secret: testSecret,
+		},
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1644845061.{}' | openssl dgst -sha256 -hmac s3cr3t
+	assert.Equal(t,
+		"889cb6dcc92eb5a9f04c2518062cc28f62ed36a122f494bed294953ea947ab2c",
+		Sign(testSecret, "1644845061", []byte("{}")))
+	assert.NotEqual(t, Sign(testSecret, "1644845061", []byte("{}")), Sign(testSecret, "1644845062", []byte("{}")))
+	assert.NotEqual(t, Sign(testSecret, "1644845061", []byte("{}")), Sign("other", "1644845061", []byte("{}")))
+}
+
+func TestDispatcher_Publish(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	subs := []domain.WebhookSubscription{
+		{ID: uuid.Must(uuid.NewV4()), Events: "item.created, payout.created"},
+		{ID: uuid.Must(uuid.NewV4()), Events: "payout.status_changed"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindActiveWebhookSubscriptions(gomock.Any()).Return(subs, nil)
+		mdb.EXPECT().InsertWebhookDeliveries(gomock.Any(), gomock.Any()).Do(func(_ context.Context, deliveries []domain.WebhookDelivery) {
+			require.Len(t, deliveries, 1)
+
+			w := deliveries[0]
+			assert.Equal(t, subs[0].ID, w.SubscriptionID)
+			assert.Equal(t, domain.EventPayoutCreated, w.EventType)
+			assert.Equal(t, testNow, w.NextAttemptAt)
+
+			var e Event
+			require.NoError(t, json.Unmarshal(w.Payload, &e))
+			assert.Equal(t, w.EventID, e.ID)
+			assert.Equal(t, domain.EventPayoutCreated, e.Type)
+		})
+
+		err := testDispatcher(mdb).Publish(context.Background(), testMessage(domain.EventPayoutCreated))
+		assert.NoError(t, err)
+	})
+
+	t.Run("publish-outbox-message", func(t *testing.T) {
+		m := domain.OutboxMessage{
+			ID:        uuid.Must(uuid.NewV4()),
+			CreatedAt: testNow,
+			EventType: domain.EventItemCreated,
+			Payload:   []byte(`{"item_id":"1"}`),
+		}
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindActiveWebhookSubscriptions(gomock.Any()).Return(subs, nil)
+		mdb.EXPECT().InsertWebhookDeliveries(gomock.Any(), gomock.Any()).Do(func(_ context.Context, deliveries []domain.WebhookDelivery) {
+			require.Len(t, deliveries, 1)
+			assert.Equal(t, m.ID, deliveries[0].EventID)
+			assert.JSONEq(t,
+				`{"id":"`+m.ID.String()+`","type":"item.created","created_at":"2022-02-14T13:24:21Z","data":{"item_id":"1"}}`,
+				string(deliveries[0].Payload))
+		})
+
+		assert.NoError(t, testDispatcher(mdb).Publish(context.Background(), m))
+	})
+
+	t.Run("no-subscription", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindActiveWebhookSubscriptions(gomock.Any()).Return(subs, nil)
+
+		err := testDispatcher(mdb).Publish(context.Background(), testMessage(domain.EventItemCreated+"x"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("fail-db-find-subscriptions", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindActiveWebhookSubscriptions(gomock.Any()).Return(nil, errors.New("mock"))
+
+		err := testDispatcher(mdb).Publish(context.Background(), testMessage(domain.EventPayoutCreated))
+		assert.Error(t, err)
+	})
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	mc := gomock.NewController(t)
+	t.Cleanup(func() { mc.Finish() })
+
+	t.Run("delivered", func(t *testing.T) {
+		ts, got := receiver(t, http.StatusNoContent)
+		w := testDelivery(ts.URL, 0)
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindDueWebhookDeliveries(gomock.Any(), testNow, batchSize).Return([]domain.WebhookDelivery{w}, nil)
+		mdb.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Do(func(_ context.Context, u domain.WebhookDelivery) {
+			assert.Equal(t, domain.WebhookDeliveryDelivered, u.Status)
+			assert.Equal(t, 1, u.Attempts)
+			assert.Equal(t, testNow, *u.DeliveredAt)
+		})
+
+		require.NoError(t, testDispatcher(mdb).Deliver(context.Background()))
+		require.Len(t, *got, 1)
+
+		r := (*got)[0]
+		assert.Equal(t, w.EventID.String(), r.Header.Get(IDHeader))
+		assert.Equal(t, string(domain.EventPayoutCreated), r.Header.Get(EventHeader))
+	})
+
+	t.Run("retried-with-backoff", func(t *testing.T) {
+		ts, _ := receiver(t, http.StatusInternalServerError)
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindDueWebhookDeliveries(gomock.Any(), testNow, batchSize).
+			Return([]domain.WebhookDelivery{testDelivery(ts.URL, 0), testDelivery(ts.URL, 3)}, nil)
+		mdb.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Do(func(_ context.Context, u domain.WebhookDelivery) {
+			assert.Equal(t, domain.WebhookDeliveryPending, u.Status)
+			assert.Equal(t, testNow.Add(backoff), u.NextAttemptAt)
+			assert.Contains(t, u.LastError, "500")
+		})
+		mdb.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Do(func(_ context.Context, u domain.WebhookDelivery) {
+			assert.Equal(t, testNow.Add(8*backoff), u.NextAttemptAt)
+		})
+
+		require.NoError(t, testDispatcher(mdb).Deliver(context.Background()))
+	})
+
+	t.Run("dead-lettered", func(t *testing.T) {
+		ts, _ := receiver(t, http.StatusGone)
+		w := testDelivery(ts.URL, maxAttempts-1)
+
+		mdb := mock.NewMockDB(mc)
+		mtx := mock.NewMockDB(mc)
+		mdb.EXPECT().FindDueWebhookDeliveries(gomock.Any(), testNow, batchSize).Return([]domain.WebhookDelivery{w}, nil)
+		mdb.EXPECT().Begin(gomock.Any()).Return(mtx, nil)
+		mtx.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Do(func(_ context.Context, u domain.WebhookDelivery) {
+			assert.Equal(t, domain.WebhookDeliveryDead, u.Status)
+		})
+		mtx.EXPECT().InsertWebhookDeadLetter(gomock.Any(), gomock.Any()).Do(func(_ context.Context, dl *domain.WebhookDeadLetter) {
+			assert.Equal(t, w.ID, dl.DeliveryID)
+			assert.Equal(t, maxAttempts, dl.Attempts)
+		})
+		mtx.EXPECT().Commit()
+
+		require.NoError(t, testDispatcher(mdb).Deliver(context.Background()))
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		ts, _ := receiver(t, http.StatusOK)
+		ts.Close()
+
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindDueWebhookDeliveries(gomock.Any(), testNow, batchSize).
+			Return([]domain.WebhookDelivery{testDelivery(ts.URL, 0)}, nil)
+		mdb.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Do(func(_ context.Context, u domain.WebhookDelivery) {
+			assert.Equal(t, domain.WebhookDeliveryPending, u.Status)
+			assert.NotEmpty(t, u.LastError)
+		})
+
+		require.NoError(t, testDispatcher(mdb).Deliver(context.Background()))
+	})
+
+	t.Run("fail-db-find-deliveries", func(t *testing.T) {
+		mdb := mock.NewMockDB(mc)
+		mdb.EXPECT().FindDueWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("mock"))
+
+		assert.Error(t, testDispatcher(mdb).Deliver(context.Background()))
+	})
+}
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE payout_items
+    DROP COLUMN IF EXISTS converted_amount,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS fee_amount;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE payout_items
+    ADD COLUMN converted_amount NUMERIC,
+    ADD COLUMN exchange_rate    NUMERIC,
+    ADD COLUMN fee_amount       NUMERIC NOT NULL DEFAULT 0;
+
+COMMIT;
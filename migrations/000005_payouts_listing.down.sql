@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS payout_items_payout_id_idx;
+DROP INDEX IF EXISTS payouts_seller_id_status_created_at_id_idx;
+DROP INDEX IF EXISTS payouts_seller_id_created_at_id_idx;
+
+ALTER TABLE payouts DROP COLUMN IF EXISTS status;
+
+COMMIT;
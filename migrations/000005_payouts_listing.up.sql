@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE payouts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'created';
+
+CREATE INDEX payouts_seller_id_created_at_id_idx ON payouts (seller_id, created_at, id);
+CREATE INDEX payouts_seller_id_status_created_at_id_idx ON payouts (seller_id, status, created_at, id);
+CREATE INDEX payout_items_payout_id_idx ON payout_items (payout_id);
+
+COMMIT;
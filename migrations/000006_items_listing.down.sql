@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS payout_items_item_id_idx;
+DROP INDEX IF EXISTS items_seller_id_created_at_id_idx;
+DROP INDEX IF EXISTS items_created_at_id_idx;
+
+COMMIT;
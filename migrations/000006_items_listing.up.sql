@@ -0,0 +1,7 @@
+BEGIN;
+
+CREATE INDEX items_created_at_id_idx ON items (created_at, id);
+CREATE INDEX items_seller_id_created_at_id_idx ON items (seller_id, created_at, id);
+CREATE INDEX payout_items_item_id_idx ON payout_items (item_id);
+
+COMMIT;
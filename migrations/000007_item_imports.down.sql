@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS item_import_errors;
+DROP TABLE IF EXISTS item_imports;
+
+COMMIT;
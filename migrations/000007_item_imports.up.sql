@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE item_imports (
+    id            UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at    TIMESTAMPTZ DEFAULT (now()),
+    updated_at    TIMESTAMPTZ,
+
+    format        VARCHAR(10) NOT NULL,
+    status        VARCHAR(20) NOT NULL,
+    total_rows    INTEGER     NOT NULL DEFAULT 0,
+    imported_rows INTEGER     NOT NULL DEFAULT 0,
+    failed_rows   INTEGER     NOT NULL DEFAULT 0,
+    error         TEXT,
+    finished_at   TIMESTAMPTZ
+);
+
+CREATE TABLE item_import_errors (
+    id             UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at     TIMESTAMPTZ DEFAULT (now()),
+    updated_at     TIMESTAMPTZ,
+
+    row_number     INTEGER NOT NULL,
+    message        TEXT    NOT NULL,
+
+    item_import_id UUID NOT NULL REFERENCES item_imports(id) ON DELETE CASCADE
+);
+
+CREATE INDEX item_import_errors_item_import_id_row_number_idx ON item_import_errors (item_import_id, row_number);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP INDEX IF EXISTS payouts_bank_file_id_idx;
+DROP INDEX IF EXISTS payouts_status_created_at_idx;
+
+ALTER TABLE payouts DROP COLUMN IF EXISTS bank_file_id;
+
+DROP TABLE IF EXISTS bank_files;
+
+ALTER TABLE sellers
+    DROP COLUMN IF EXISTS account_number,
+    DROP COLUMN IF EXISTS routing_number,
+    DROP COLUMN IF EXISTS bic,
+    DROP COLUMN IF EXISTS iban,
+    DROP COLUMN IF EXISTS name;
+
+COMMIT;
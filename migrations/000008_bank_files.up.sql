@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE sellers
+    ADD COLUMN name           VARCHAR(140),
+    ADD COLUMN iban           VARCHAR(34),
+    ADD COLUMN bic            VARCHAR(11),
+    ADD COLUMN routing_number VARCHAR(9),
+    ADD COLUMN account_number VARCHAR(17);
+
+CREATE TABLE bank_files (
+    id             UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at     TIMESTAMPTZ DEFAULT (now()),
+    updated_at     TIMESTAMPTZ,
+
+    format         VARCHAR(20) NOT NULL,
+    currency_code  VARCHAR(3)  NOT NULL,
+    message_id     VARCHAR(35) NOT NULL UNIQUE,
+    payments_count INTEGER     NOT NULL,
+    control_sum    NUMERIC     NOT NULL,
+    content        BYTEA       NOT NULL
+);
+
+ALTER TABLE payouts ADD COLUMN bank_file_id UUID REFERENCES bank_files(id);
+
+CREATE INDEX payouts_status_created_at_idx ON payouts (status, created_at);
+CREATE INDEX payouts_bank_file_id_idx ON payouts (bank_file_id);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reconciliation_exceptions;
+DROP TABLE IF EXISTS reconciliations;
+
+ALTER TABLE payouts
+    DROP COLUMN IF EXISTS settled_at,
+    DROP COLUMN IF EXISTS exported_at;
+
+COMMIT;
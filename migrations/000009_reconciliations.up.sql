@@ -0,0 +1,36 @@
+BEGIN;
+
+ALTER TABLE payouts
+    ADD COLUMN exported_at TIMESTAMPTZ,
+    ADD COLUMN settled_at  TIMESTAMPTZ;
+
+CREATE TABLE reconciliations (
+    id               UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at       TIMESTAMPTZ DEFAULT (now()),
+    updated_at       TIMESTAMPTZ,
+
+    format           VARCHAR(10) NOT NULL,
+    entries_count    INTEGER     NOT NULL DEFAULT 0,
+    matched_count    INTEGER     NOT NULL DEFAULT 0,
+    exceptions_count INTEGER     NOT NULL DEFAULT 0
+);
+
+CREATE TABLE reconciliation_exceptions (
+    id                UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at        TIMESTAMPTZ DEFAULT (now()),
+    updated_at        TIMESTAMPTZ,
+
+    kind              VARCHAR(20) NOT NULL,
+    message           TEXT        NOT NULL,
+    entry_reference   VARCHAR(140),
+    entry_amount      NUMERIC,
+    entry_currency    VARCHAR(3),
+    entry_date        TIMESTAMPTZ,
+
+    payout_id         UUID REFERENCES payouts(id),
+    reconciliation_id UUID NOT NULL REFERENCES reconciliations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX reconciliation_exceptions_reconciliation_id_idx ON reconciliation_exceptions (reconciliation_id);
+
+COMMIT;
@@ -0,0 +1,45 @@
+BEGIN;
+
+CREATE TABLE webhook_subscriptions (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ DEFAULT (now()),
+    updated_at TIMESTAMPTZ,
+
+    url        TEXT        NOT NULL,
+    secret     VARCHAR(64) NOT NULL,
+    events     TEXT        NOT NULL,
+    active     BOOLEAN     NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE webhook_deliveries (
+    id              UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at      TIMESTAMPTZ DEFAULT (now()),
+    updated_at      TIMESTAMPTZ,
+
+    event_id        UUID        NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    payload         BYTEA       NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT (now()),
+    last_error      TEXT,
+    delivered_at    TIMESTAMPTZ,
+
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_deliveries_status_next_attempt_at_idx ON webhook_deliveries (status, next_attempt_at);
+
+CREATE TABLE webhook_dead_letters (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  TIMESTAMPTZ DEFAULT (now()),
+    updated_at  TIMESTAMPTZ,
+
+    attempts    INTEGER     NOT NULL,
+    last_error  TEXT        NOT NULL,
+    replayed_at TIMESTAMPTZ,
+
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE outbox (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at   TIMESTAMPTZ DEFAULT (now()),
+    updated_at   TIMESTAMPTZ,
+
+    seq          BIGSERIAL   NOT NULL UNIQUE,
+    key          UUID        NOT NULL,
+    event_type   VARCHAR(50) NOT NULL,
+    payload      BYTEA       NOT NULL,
+    published_at TIMESTAMPTZ,
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    last_error   TEXT
+);
+
+CREATE INDEX outbox_unpublished_seq_idx ON outbox (seq) WHERE published_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS job_run_totals;
+DROP TABLE IF EXISTS job_runs;
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+CREATE TABLE job_runs (
+    id                UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at        TIMESTAMPTZ DEFAULT (now()),
+    updated_at        TIMESTAMPTZ,
+
+    job               VARCHAR(50) NOT NULL,
+    status            VARCHAR(20) NOT NULL DEFAULT 'running',
+    started_at        TIMESTAMPTZ NOT NULL,
+    finished_at       TIMESTAMPTZ,
+    duration_ms       BIGINT      NOT NULL DEFAULT 0,
+    error             TEXT,
+    sellers_processed INTEGER     NOT NULL DEFAULT 0,
+    payouts_created   INTEGER     NOT NULL DEFAULT 0
+);
+
+CREATE INDEX job_runs_job_created_at_idx ON job_runs (job, created_at, id);
+
+CREATE TABLE job_run_totals (
+    id            UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at    TIMESTAMPTZ DEFAULT (now()),
+    updated_at    TIMESTAMPTZ,
+
+    currency_code VARCHAR(3)  NOT NULL,
+    payouts_count INTEGER     NOT NULL DEFAULT 0,
+    amount        NUMERIC     NOT NULL DEFAULT 0,
+
+    job_run_id    UUID NOT NULL REFERENCES job_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX job_run_totals_job_run_id_idx ON job_run_totals (job_run_id);
+
+COMMIT;
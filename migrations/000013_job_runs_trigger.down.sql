@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE job_runs
+    DROP COLUMN IF EXISTS trigger;
+
+COMMIT;
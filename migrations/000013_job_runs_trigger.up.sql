@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE job_runs
+    ADD COLUMN trigger VARCHAR(20) NOT NULL DEFAULT 'schedule';
+
+COMMIT;
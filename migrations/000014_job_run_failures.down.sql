@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS job_run_failures;
+
+ALTER TABLE job_runs
+    DROP COLUMN IF EXISTS sellers_failed;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE job_runs
+    ADD COLUMN sellers_failed INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE job_run_failures (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ DEFAULT (now()),
+    updated_at TIMESTAMPTZ,
+
+    seller_id  UUID        NOT NULL,
+    error      TEXT        NOT NULL,
+
+    job_run_id UUID NOT NULL REFERENCES job_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX job_run_failures_job_run_id_idx ON job_run_failures (job_run_id);
+
+COMMIT;
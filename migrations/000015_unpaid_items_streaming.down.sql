@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS items_unpaid_seller_id_created_at_id_idx;
+DROP INDEX IF EXISTS sellers_created_at_id_idx;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- the payouts creation pages through the sellers, then through the unpaid out items of each seller.
+CREATE INDEX sellers_created_at_id_idx ON sellers (created_at, id);
+CREATE INDEX items_unpaid_seller_id_created_at_id_idx ON items (seller_id, created_at, id) WHERE paid_out = false;
+
+COMMIT;
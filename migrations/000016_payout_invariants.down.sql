@@ -0,0 +1,32 @@
+BEGIN;
+
+ALTER TABLE payout_items
+    DROP CONSTRAINT IF EXISTS payout_items_fee_amount_check,
+    DROP CONSTRAINT IF EXISTS payout_items_exchange_rate_check,
+    DROP CONSTRAINT IF EXISTS payout_items_converted_amount_check;
+
+ALTER TABLE payouts
+    DROP CONSTRAINT IF EXISTS payouts_price_total_check,
+    ALTER COLUMN price_total DROP NOT NULL;
+
+ALTER TABLE items
+    DROP CONSTRAINT IF EXISTS items_currency_code_fkey,
+    DROP CONSTRAINT IF EXISTS items_price_amount_check,
+    ALTER COLUMN currency_code DROP NOT NULL,
+    ALTER COLUMN paid_out      DROP NOT NULL,
+    ALTER COLUMN price_amount  DROP NOT NULL;
+
+ALTER TABLE sellers
+    DROP CONSTRAINT IF EXISTS sellers_currency_code_fkey,
+    ALTER COLUMN currency_code DROP NOT NULL;
+
+ALTER TABLE currencies
+    DROP CONSTRAINT IF EXISTS currencies_usd_exch_rate_check,
+    DROP CONSTRAINT IF EXISTS currencies_code_check,
+    ALTER COLUMN USD_exch_rate DROP NOT NULL,
+    ALTER COLUMN code          DROP NOT NULL;
+
+DROP INDEX IF EXISTS currencies_code_key;
+DROP INDEX IF EXISTS payout_items_item_id_key;
+
+COMMIT;
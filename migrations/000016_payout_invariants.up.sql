@@ -0,0 +1,78 @@
+BEGIN;
+
+-- preflight: the rows breaking the invariants below are counted first, so that the migration fails
+-- with a report of them rather than on the first constraint. See "Migrating to the payout invariants" in the README.
+DO $preflight$
+DECLARE
+    c        RECORD;
+    n        BIGINT;
+    problems TEXT[] := '{}';
+BEGIN
+    FOR c IN SELECT * FROM (VALUES
+        ('items in several payouts',
+            $q$SELECT count(*) FROM (SELECT item_id FROM payout_items GROUP BY item_id HAVING count(*) > 1) d$q$),
+        ('duplicated currency codes',
+            $q$SELECT count(*) FROM (SELECT code FROM currencies GROUP BY code HAVING count(*) > 1) d$q$),
+        ('invalid currencies',
+            $q$SELECT count(*) FROM currencies
+               WHERE code IS NULL OR code !~ '^[A-Z]{3}$' OR USD_exch_rate IS NULL OR USD_exch_rate <= 0$q$),
+        ('sellers without a known currency',
+            $q$SELECT count(*) FROM sellers s
+               WHERE s.currency_code IS NULL OR NOT EXISTS (SELECT 1 FROM currencies c WHERE c.code = s.currency_code)$q$),
+        ('items without a known currency',
+            $q$SELECT count(*) FROM items i
+               WHERE i.currency_code IS NULL OR NOT EXISTS (SELECT 1 FROM currencies c WHERE c.code = i.currency_code)$q$),
+        ('invalid items',
+            $q$SELECT count(*) FROM items WHERE price_amount IS NULL OR price_amount <= 0 OR paid_out IS NULL$q$),
+        ('invalid payouts',
+            $q$SELECT count(*) FROM payouts WHERE price_total IS NULL OR price_total < 0$q$),
+        ('invalid payout items',
+            $q$SELECT count(*) FROM payout_items
+               WHERE converted_amount < 0 OR exchange_rate <= 0 OR fee_amount < 0$q$)
+    ) AS checks (label, query) LOOP
+        EXECUTE c.query INTO n;
+
+        IF n > 0 THEN
+            problems := problems || format('%s %s', n, c.label);
+        END IF;
+    END LOOP;
+
+    IF cardinality(problems) > 0 THEN
+        RAISE EXCEPTION 'payout invariants broken by existing rows: %', array_to_string(problems, ', ')
+            USING HINT = 'fix the rows, see "Migrating to the payout invariants" in the README, then run the migration again';
+    END IF;
+END
+$preflight$;
+
+-- an item is paid out by a single payout, whatever the paid_out flag says.
+CREATE UNIQUE INDEX payout_items_item_id_key ON payout_items (item_id);
+
+CREATE UNIQUE INDEX currencies_code_key ON currencies (code);
+
+ALTER TABLE currencies
+    ALTER COLUMN code          SET NOT NULL,
+    ALTER COLUMN USD_exch_rate SET NOT NULL,
+    ADD CONSTRAINT currencies_code_check          CHECK (code ~ '^[A-Z]{3}$'),
+    ADD CONSTRAINT currencies_usd_exch_rate_check CHECK (USD_exch_rate > 0);
+
+ALTER TABLE sellers
+    ALTER COLUMN currency_code SET NOT NULL,
+    ADD CONSTRAINT sellers_currency_code_fkey FOREIGN KEY (currency_code) REFERENCES currencies (code);
+
+ALTER TABLE items
+    ALTER COLUMN price_amount  SET NOT NULL,
+    ALTER COLUMN paid_out      SET NOT NULL,
+    ALTER COLUMN currency_code SET NOT NULL,
+    ADD CONSTRAINT items_price_amount_check CHECK (price_amount > 0),
+    ADD CONSTRAINT items_currency_code_fkey FOREIGN KEY (currency_code) REFERENCES currencies (code);
+
+ALTER TABLE payouts
+    ALTER COLUMN price_total SET NOT NULL,
+    ADD CONSTRAINT payouts_price_total_check CHECK (price_total >= 0);
+
+ALTER TABLE payout_items
+    ADD CONSTRAINT payout_items_converted_amount_check CHECK (converted_amount >= 0),
+    ADD CONSTRAINT payout_items_exchange_rate_check    CHECK (exchange_rate > 0),
+    ADD CONSTRAINT payout_items_fee_amount_check       CHECK (fee_amount >= 0);
+
+COMMIT;
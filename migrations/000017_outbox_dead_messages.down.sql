@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS outbox_pending_key_seq_idx;
+CREATE INDEX outbox_unpublished_seq_idx ON outbox (seq) WHERE published_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS dead_at;
+
+COMMIT;
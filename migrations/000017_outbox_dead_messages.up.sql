@@ -0,0 +1,10 @@
+BEGIN;
+
+-- a message failing maxAttempts times is set aside with dead_at, the following ones of its key go on.
+ALTER TABLE outbox ADD COLUMN dead_at TIMESTAMPTZ;
+
+-- the relay reads the oldest pending message of each key.
+DROP INDEX IF EXISTS outbox_unpublished_seq_idx;
+CREATE INDEX outbox_pending_key_seq_idx ON outbox (key, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE payout_items
+    ADD COLUMN fee_amount NUMERIC NOT NULL DEFAULT 0,
+    ADD CONSTRAINT payout_items_fee_amount_check CHECK (fee_amount >= 0);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- no fee was ever charged on a payout item, the column only held zeros.
+ALTER TABLE payout_items DROP COLUMN IF EXISTS fee_amount;
+
+COMMIT;
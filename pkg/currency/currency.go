@@ -1,6 +1,7 @@
 package currency
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -34,7 +35,7 @@ var (
 // Exchanger is the currency exchange interface.
 type Exchanger interface {
 	// GetConversionRate takes in a currency and returns its exchange rate against 1 unit of in the base currency.
-	GetConversionRate(currency string) (decimal.Decimal, error)
+	GetConversionRate(ctx context.Context, currency string) (decimal.Decimal, error)
 }
 
 type exchanger struct {
@@ -48,7 +49,12 @@ func New() Exchanger {
 	}
 }
 
-func (e exchanger) GetConversionRate(currency string) (decimal.Decimal, error) {
+// GetConversionRate does not call the API once ctx is done, the API client does not take a context.
+func (e exchanger) GetConversionRate(ctx context.Context, currency string) (decimal.Decimal, error) {
+	if err := ctx.Err(); err != nil {
+		return decimal.Decimal{}, err
+	}
+
 	if _, ok := supportedCurrency[currency]; !ok {
 		return decimal.Decimal{}, fmt.Errorf("%w (format: %s, accepted: %v)", errInvalidCurrency, currency, supportedCurrency)
 	}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPayoutsChanged is raised when payouts changed status while being updated.
+var ErrPayoutsChanged = errors.New("payouts changed concurrently")
+
+// FindPayoutsByStatus finds the payouts in a status with their seller and currency, oldest first.
+func (d database) FindPayoutsByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error) {
+	var payouts []domain.Payout
+
+	err := d.driver.WithContext(ctx).
+		Preload("Seller").
+		Preload("Currency").
+		Where("status = ?", status).
+		Order("created_at ASC, id ASC").
+		Find(&payouts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
+
+// UpdatePayoutStatus moves a payout from a status to another,
+// ErrRecordNotFound is returned when the payout is not in the from status.
+func (d database) UpdatePayoutStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.Payout{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ExportPayouts marks approved payouts as exported in a bank file,
+// ErrPayoutsChanged is returned when some of them are no longer approved.
+func (d database) ExportPayouts(ctx context.Context, bankFileID string, payoutIDs []string) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.Payout{}).
+		Where("id IN ? AND status = ?", payoutIDs, domain.PayoutStatusApproved).
+		Updates(map[string]interface{}{
+			"status":       domain.PayoutStatusExported,
+			"bank_file_id": bankFileID,
+			"exported_at":  time.Now(),
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected != int64(len(payoutIDs)) {
+		return ErrPayoutsChanged
+	}
+
+	return nil
+}
+
+// InsertBankFile inserts a bank file, its payouts are linked to it by ExportPayouts.
+func (d database) InsertBankFile(ctx context.Context, f *domain.BankFile) error {
+	return d.driver.WithContext(ctx).Omit(clause.Associations).Create(f).Error
+}
+
+// FindBankFileByID finds a bank file by id, along with its content.
+func (d database) FindBankFileByID(ctx context.Context, id string) (domain.BankFile, error) {
+	var f domain.BankFile
+
+	if err := d.driver.WithContext(ctx).Take(&f, "id = ?", id).Error; err != nil {
+		return domain.BankFile{}, err
+	}
+
+	return f, nil
+}
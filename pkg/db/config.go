@@ -0,0 +1,116 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrReplicasWithDSN is raised when read replicas are set along with a DSN, their DSN cannot be derived from it.
+var ErrReplicasWithDSN = errors.New("replica hosts cannot be used along with a DSN")
+
+// defaultSSLMode keeps the connections unencrypted unless asked otherwise, as the local postgres is.
+const defaultSSLMode = "disable"
+
+// Config holds our database configuration.
+type Config struct {
+	// DSN is a full connection string, key/value or URL, overriding the connection fields below.
+	DSN string
+
+	User     string
+	Name     string
+	Password string
+	Host     string
+	// Port is the postgres default one when zero.
+	Port int
+	// SSLMode is one of disable, allow, prefer, require, verify-ca or verify-full, disable when empty.
+	SSLMode string
+	// SSLRootCert is the path of the CA certificates the server certificate is verified against.
+	SSLRootCert string
+	// SSLCert and SSLKey are the paths of the client certificate and of its key.
+	SSLCert string
+	SSLKey  string
+	// ApplicationName tells the connections of the service apart in pg_stat_activity.
+	ApplicationName string
+	// StatementTimeout aborts the statements running longer, no timeout when zero.
+	StatementTimeout time.Duration
+
+	// Pool sizing, the database/sql defaults are kept when zero.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// ReplicaHosts are the read replicas of Host, sharing its other connection fields.
+	ReplicaHosts []string
+	// MaxReplicaLag is the replication lag above which a replica is not read from.
+	MaxReplicaLag time.Duration
+}
+
+// dsn returns the connection string of c, DSN when it is set.
+func (c Config) dsn() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+
+	sslMode := c.SSLMode
+	if sslMode == "" {
+		sslMode = defaultSSLMode
+	}
+
+	params := []string{
+		"host=" + dsnValue(c.Host),
+		"user=" + dsnValue(c.User),
+		"password=" + dsnValue(c.Password),
+		"dbname=" + dsnValue(c.Name),
+		"sslmode=" + dsnValue(sslMode),
+	}
+
+	if c.Port != 0 {
+		params = append(params, fmt.Sprintf("port=%d", c.Port))
+	}
+
+	certs := []struct{ key, path string }{
+		{"sslrootcert", c.SSLRootCert},
+		{"sslcert", c.SSLCert},
+		{"sslkey", c.SSLKey},
+	}
+
+	for _, cert := range certs {
+		if cert.path != "" {
+			params = append(params, cert.key+"="+dsnValue(cert.path))
+		}
+	}
+
+	if c.ApplicationName != "" {
+		params = append(params, "application_name="+dsnValue(c.ApplicationName))
+	}
+
+	// sent as a run-time parameter of the connections, in milliseconds.
+	if c.StatementTimeout > 0 {
+		params = append(params, fmt.Sprintf("statement_timeout=%d", c.StatementTimeout.Milliseconds()))
+	}
+
+	return strings.Join(params, " ")
+}
+
+// dsnValue quotes v for a key/value connection string.
+func dsnValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// configurePool sizes the connections pool of db.
+func (c Config) configurePool(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+}
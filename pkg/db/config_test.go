@@ -0,0 +1,73 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDSN(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c := Config{Host: "postgres", User: "u", Password: "p", Name: "db"}
+
+		assert.Equal(t, "host='postgres' user='u' password='p' dbname='db' sslmode='disable'", c.dsn())
+	})
+
+	t.Run("all-fields", func(t *testing.T) {
+		c := Config{
+			Host:             "pg.example.com",
+			Port:             25060,
+			User:             "u",
+			Password:         `it's \ secret`,
+			Name:             "db",
+			SSLMode:          "verify-full",
+			SSLRootCert:      "/certs/ca.pem",
+			SSLCert:          "/certs/client.pem",
+			SSLKey:           "/certs/client.key",
+			ApplicationName:  "sellerpayout",
+			StatementTimeout: 30 * time.Second,
+		}
+
+		assert.Equal(t, "host='pg.example.com' user='u' password='it\\'s \\\\ secret' dbname='db' sslmode='verify-full' "+
+			"port=25060 sslrootcert='/certs/ca.pem' sslcert='/certs/client.pem' sslkey='/certs/client.key' "+
+			"application_name='sellerpayout' statement_timeout=30000", c.dsn())
+	})
+
+	t.Run("parsed-by-driver", func(t *testing.T) {
+		c := Config{
+			Host:             "pg.example.com",
+			Port:             25060,
+			User:             "u",
+			Password:         `it's \ secret`,
+			Name:             "db",
+			SSLMode:          "require",
+			ApplicationName:  "sellerpayout",
+			StatementTimeout: time.Second,
+		}
+
+		pc, err := pgconn.ParseConfig(c.dsn())
+		require.NoError(t, err)
+
+		assert.Equal(t, "pg.example.com", pc.Host)
+		assert.Equal(t, uint16(25060), pc.Port)
+		assert.Equal(t, `it's \ secret`, pc.Password)
+		assert.Equal(t, "db", pc.Database)
+		assert.NotNil(t, pc.TLSConfig)
+		assert.Equal(t, "sellerpayout", pc.RuntimeParams["application_name"])
+		assert.Equal(t, "1000", pc.RuntimeParams["statement_timeout"])
+	})
+
+	t.Run("dsn-overrides-fields", func(t *testing.T) {
+		c := Config{DSN: "postgres://u:p@pg.example.com:25060/db?sslmode=require", Host: "postgres", Port: 5432}
+
+		assert.Equal(t, c.DSN, c.dsn())
+	})
+}
+
+func TestNewReplicasWithDSN(t *testing.T) {
+	_, err := New(Config{DSN: "postgres://u:p@pg.example.com/db", ReplicaHosts: []string{"replica"}})
+	assert.ErrorIs(t, err, ErrReplicasWithDSN)
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUniqueViolation is raised when a row duplicates the key of another one.
+	ErrUniqueViolation = errors.New("unique constraint violated")
+	// ErrForeignKeyViolation is raised when a row refers to a missing one, or is referred to on delete.
+	ErrForeignKeyViolation = errors.New("foreign key constraint violated")
+	// ErrCheckViolation is raised when a value is out of the range allowed by a check constraint.
+	ErrCheckViolation = errors.New("check constraint violated")
+	// ErrNotNullViolation is raised when a required value is missing.
+	ErrNotNullViolation = errors.New("not null constraint violated")
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+var constraintViolations = map[string]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"23514": ErrCheckViolation,
+	"23502": ErrNotNullViolation,
+}
+
+// ConstraintError is a violation of a database constraint, it matches its kind with errors.Is:
+//
+//	if errors.Is(err, db.ErrUniqueViolation) {
+//		var cerr *db.ConstraintError
+//		errors.As(err, &cerr)
+//		fmt.Println(cerr.Constraint) // payout_items_item_id_key
+//	}
+type ConstraintError struct {
+	// Kind is one of ErrUniqueViolation, ErrForeignKeyViolation, ErrCheckViolation and ErrNotNullViolation.
+	Kind       error
+	Table      string
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Constraint == "" {
+		return fmt.Sprintf("%s on %s: %s", e.Kind, e.Table, e.Err)
+	}
+
+	return fmt.Sprintf("%s on %s (%s): %s", e.Kind, e.Table, e.Constraint, e.Err)
+}
+
+// Is reports whether target is the kind of the violation.
+func (e *ConstraintError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// constraintError translates a constraint violation raised by postgres into a ConstraintError,
+// the other errors are returned as is.
+func constraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	kind, ok := constraintViolations[pgErr.Code]
+	if !ok {
+		return err
+	}
+
+	return &ConstraintError{Kind: kind, Table: pgErr.TableName, Constraint: pgErr.ConstraintName, Err: err}
+}
+
+// registerConstraintErrors makes the writes of the driver return constraint violations as ConstraintError.
+func registerConstraintErrors(driver *gorm.DB) error {
+	translate := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			tx.Error = constraintError(tx.Error)
+		}
+	}
+
+	cb := driver.Callback()
+
+	for name, register := range map[string]func(string, func(*gorm.DB)) error{
+		"create": cb.Create().After("*").Register,
+		"update": cb.Update().After("*").Register,
+		"delete": cb.Delete().After("*").Register,
+		"raw":    cb.Raw().After("*").Register,
+	} {
+		if err := register("db:constraint_errors", translate); err != nil {
+			return fmt.Errorf("failed to register the constraint errors on %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestConstraintError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		kind error
+	}{
+		"unique":      {err: &pgconn.PgError{Code: "23505"}, kind: ErrUniqueViolation},
+		"foreign-key": {err: &pgconn.PgError{Code: "23503"}, kind: ErrForeignKeyViolation},
+		"check":       {err: &pgconn.PgError{Code: "23514"}, kind: ErrCheckViolation},
+		"not-null":    {err: &pgconn.PgError{Code: "23502"}, kind: ErrNotNullViolation},
+	}
+
+	for tn, tc := range tests {
+		tn, tc := tn, tc
+		t.Run(tn, func(t *testing.T) {
+			err := constraintError(tc.err)
+
+			assert.ErrorIs(t, err, tc.kind)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	t.Run("other-errors-unchanged", func(t *testing.T) {
+		serialization := &pgconn.PgError{Code: "40001"}
+		merr := errors.New("mock")
+
+		assert.Same(t, serialization, constraintError(serialization))
+		assert.Equal(t, merr, constraintError(merr))
+	})
+
+	t.Run("constraint-name", func(t *testing.T) {
+		err := constraintError(&pgconn.PgError{Code: "23505", TableName: "payout_items", ConstraintName: "payout_items_item_id_key"})
+
+		var cerr *ConstraintError
+		if assert.ErrorAs(t, err, &cerr) {
+			assert.Equal(t, "payout_items", cerr.Table)
+			assert.Equal(t, "payout_items_item_id_key", cerr.Constraint)
+		}
+
+		assert.NotErrorIs(t, err, ErrCheckViolation)
+	})
+}
+
+func TestRegisterConstraintErrors(t *testing.T) {
+	driver, err := gorm.Open(postgres.Open(""), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, registerConstraintErrors(driver))
+
+	// the violation is raised by the statement, before the translation.
+	violation := func(tx *gorm.DB) { _ = tx.AddError(&pgconn.PgError{Code: "23505"}) }
+	assert.NoError(t, driver.Callback().Create().After("gorm:create").Register("test:violation", violation))
+
+	err = driver.Create(&domain.PayoutItem{}).Error
+
+	assert.ErrorIs(t, err, ErrUniqueViolation)
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm"
+)
+
+// UpdateCurrencyRates saves the USD exchange rates of currencies, found by code, and nothing else of them.
+// ErrRecordNotFound is returned when a code is unknown, none of the rates is saved then.
+func (d database) UpdateCurrencyRates(ctx context.Context, currencies []domain.Currency) error {
+	return d.driver.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, c := range currencies {
+			res := tx.Model(&domain.Currency{}).Where("code = ?", c.Code).Update("usd_exch_rate", c.USDExchRate)
+			if res.Error != nil {
+				return res.Error
+			}
+
+			if res.RowsAffected == 0 {
+				return ErrRecordNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindCurrencies finds all the currencies.
+func (d database) FindCurrencies(ctx context.Context) ([]domain.Currency, error) {
+	var currencies []domain.Currency
+
+	if err := d.driver.WithContext(ctx).Order("code ASC").Find(&currencies).Error; err != nil {
+		return nil, err
+	}
+
+	return currencies, nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("should_decode_an_encoded_cursor", func(t *testing.T) {
+		c := Cursor{
+			CreatedAt: time.Date(2022, 2, 14, 13, 24, 21, 356614000, time.UTC),
+			ID:        uuid.Must(uuid.NewV4()),
+		}
+
+		got, err := DecodeCursor(c.Encode())
+		require.NoError(t, err)
+
+		assert.True(t, c.CreatedAt.Equal(got.CreatedAt))
+		assert.Equal(t, c.ID, got.ID)
+	})
+
+	t.Run("should_return_an_error_on_malformed_cursor", func(t *testing.T) {
+		for _, s := range []string{"%%%", "Zm9v", "MjAyMi0wMi0xNCxmb28"} {
+			_, err := DecodeCursor(s)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		}
+	})
+}
@@ -1,10 +1,12 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/TestardR/seller-payout/internal/domain"
 	"github.com/golang-migrate/migrate/v4"
@@ -12,6 +14,7 @@ import (
 
 	// perform migrate init.
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/shopspring/decimal"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -30,34 +33,82 @@ var (
 
 //go:generate mockgen -source=db.go -destination=$MOCK_FOLDER/db.go -package=mock
 
-// Config holds our database configuration.
-type Config struct {
-	User     string
-	Name     string
-	Password string
-	Host     string
-}
-
 // DB represents the interface to interact with the database.
 type DB interface {
-	Health() error
-	Begin() (DB, error)
+	Health(ctx context.Context) error
+	Begin(ctx context.Context) (DB, error)
 	Rollback() error
 	Commit() error
 
-	Insert(dest interface{}) error
-	Update(dest interface{}) error
+	InsertSeller(ctx context.Context, s *domain.Seller) error
+	FindSellerByID(ctx context.Context, id string) (domain.Seller, error)
+	InsertItems(ctx context.Context, items []domain.Item) error
+	FindCurrencies(ctx context.Context) ([]domain.Currency, error)
+	InsertBankFile(ctx context.Context, f *domain.BankFile) error
+	FindBankFileByID(ctx context.Context, id string) (domain.BankFile, error)
+	InsertReconciliation(ctx context.Context, r *domain.Reconciliation) error
+	InsertItemImport(ctx context.Context, i *domain.ItemImport) error
+	InsertItemImportErrors(ctx context.Context, errs []domain.ItemImportError) error
+	FindItemImportByID(ctx context.Context, id string) (domain.ItemImport, error)
+	InsertWebhookSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	FindActiveWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	InsertWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	InsertWebhookDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error
+	FindWebhookDeadLetterByID(ctx context.Context, id string) (domain.WebhookDeadLetter, error)
+	FindWebhookDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error)
+	InsertOutboxMessage(ctx context.Context, m *domain.OutboxMessage) error
+	InsertJobRun(ctx context.Context, r *domain.JobRun) error
+
+	FindPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (PayoutsPage, error)
+	SumPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (decimal.Decimal, error)
+	FindPayoutByID(ctx context.Context, id string) (domain.Payout, error)
+	InsertPayout(ctx context.Context, p *domain.Payout) error
+	FindPayoutsByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error)
+	UpdatePayoutStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error
+	ExportPayouts(ctx context.Context, bankFileID string, payoutIDs []string) error
+	SettlePayouts(ctx context.Context, payoutIDs []string, settledAt time.Time) error
+	FindReconciliationByID(ctx context.Context, id string) (domain.Reconciliation, error)
+	FindItems(ctx context.Context, f ItemsFilter) (ItemsPage, error)
+	FindItemByID(ctx context.Context, id string) (domain.Item, error)
+	FindUnpaidOutItemsBySellerID(ctx context.Context, id string) ([]domain.Item, error)
+	FindUnpaidOutItems(ctx context.Context) ([]domain.Item, error)
+	FindUnpaidOutItemsPage(ctx context.Context, sellerID string, after *Cursor, limit int) (ItemsPage, error)
+	FindItemImportErrors(ctx context.Context, importID string, afterRow, limit int) ([]domain.ItemImportError, error)
+	FinishItemImport(ctx context.Context, i domain.ItemImport) error
+	UpdateCurrencyRates(ctx context.Context, currencies []domain.Currency) error
+	FindSellersWithUnpaidOutItems(ctx context.Context, f SellersFilter) (SellersPage, error)
+	LockUnpaidItems(ctx context.Context, ids []string) error
+	PayOutItems(ctx context.Context, ids []string) error
+	FindDueWebhookDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, w domain.WebhookDelivery) error
+	ReplayWebhookDeadLetter(ctx context.Context, id string, at time.Time) error
+	FindUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	UpdateOutboxMessage(ctx context.Context, m domain.OutboxMessage) error
+	FinishJobRun(ctx context.Context, r domain.JobRun) error
+	FindJobRuns(ctx context.Context, f JobRunsFilter) (JobRunsPage, error)
+	FindJobRunByID(ctx context.Context, id string) (domain.JobRun, error)
+
+	TryLock(ctx context.Context, name string) (Lock, error)
 
-	FindByID(dest interface{}, id string) error
-	FindAll(dest interface{}) error
-	FindAllWhere(dest interface{}, conds map[string]interface{}) error
+	RunMigrations(path string) error
+	Close() error
+}
 
-	FindPayoutsBySellerID(string) ([]domain.Payout, error)
-	FindUnpaidOutItemsBySellerID(string) ([]domain.Item, error)
-	FindUnpaidOutItems() ([]domain.Item, error)
-	FindSellersWhereItems(conds map[string]interface{}) ([]domain.Seller, error)
+// HealthChecker checks that the database answers.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
 
-	RunMigrations(path string) error
+// Locker acquires the locks shared by the application replicas, see DB.TryLock.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (Lock, error)
+}
+
+// Lock is a lock shared by the application replicas.
+type Lock interface {
+	// Lost is closed when the lock is no longer held.
+	Lost() <-chan struct{}
+	Release() error
 }
 
 type database struct {
@@ -65,31 +116,75 @@ type database struct {
 	config Config
 }
 
-// New creates a new postgres database.
+// New creates a new postgres database, the listings and the statements are read
+// from the replicas when some are configured.
 func New(c Config) (DB, error) {
+	if c.DSN != "" && len(c.ReplicaHosts) > 0 {
+		return &database{}, ErrReplicasWithDSN
+	}
+
+	primary, err := open(c)
+	if err != nil {
+		return &database{}, err
+	}
+
+	if len(c.ReplicaHosts) == 0 {
+		return primary, nil
+	}
+
+	replicas := make([]*replica, 0, len(c.ReplicaHosts))
+
+	for _, host := range c.ReplicaHosts {
+		rc := c
+		rc.Host, rc.ReplicaHosts = host, nil
+
+		d, err := open(rc)
+		if err != nil {
+			_ = newRouted(primary, replicas, 0).Close()
+
+			return &database{}, fmt.Errorf("replica %s: %w", host, err)
+		}
+
+		replicas = append(replicas, &replica{db: d, lag: d.replicationLag})
+	}
+
+	return newRouted(primary, replicas, c.MaxReplicaLag), nil
+}
+
+func open(c Config) (*database, error) {
 	var err error
 
 	var driver *gorm.DB
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s sslmode=disable", c.Host, c.User, c.Password, c.Name)
-	if driver, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}); err != nil {
-		return &database{}, err
+	if driver, err = gorm.Open(postgres.Open(c.dsn()), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}); err != nil {
+		return nil, err
+	}
+
+	pool, err := driver.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	c.configurePool(pool)
+
+	if err = registerConstraintErrors(driver); err != nil {
+		return nil, err
 	}
 
 	return &database{driver: driver, config: c}, nil
 }
 
-func (d database) Health() error {
-	if pinger, ok := d.driver.ConnPool.(interface{ Ping() error }); ok {
-		return pinger.Ping()
+func (d database) Health(ctx context.Context) error {
+	if pinger, ok := d.driver.ConnPool.(interface{ PingContext(context.Context) error }); ok {
+		return pinger.PingContext(ctx)
 	}
 
 	return ErrConnPool
 }
 
 // Begin begins a sql transaction.
-func (d database) Begin() (DB, error) {
-	tx := d.driver.Begin()
+func (d database) Begin(ctx context.Context) (DB, error) {
+	tx := d.driver.WithContext(ctx).Begin()
 
 	return &database{driver: tx}, tx.Error
 }
@@ -104,47 +199,14 @@ func (d database) Rollback() error {
 	return d.driver.Rollback().Error
 }
 
-// Insert take pointer to struct and add new entry in database and generate automatically a new ID
-//  var u User
-//  u.Name = "Bob"
-//  Insert(&u)
-func (d database) Insert(dest interface{}) error {
-	return d.driver.Create(dest).Error
-}
-
-// FindAll retrieving all object in database
-//   var users []User
-//   db.FindAll(&users)
-//   for _, u := range users {
-//   	fmt.Println(u.ID)
-//   }
-func (d database) FindAll(dest interface{}) error {
-	return d.driver.Find(dest).Error
-}
-
-// FindWhere take pointer to struct and conditions value
-//  var u []User
-//  FindWhere(&u, map[string]interface{}{"name": "Bob"})
-//  fmt.Println(u[0].Name) // Bob
-func (d database) FindAllWhere(dest interface{}, conds map[string]interface{}) error {
-	return d.driver.Where(conds).Find(dest).Error
-}
-
-// FindByID take pointer to struct and the ID, then it fill the struct
-//  var u User
-//  FindByID(&u, 42)
-//  fmt.Println(u.Name) // Bob
-func (d database) FindByID(dest interface{}, id string) error {
-	return d.driver.Take(dest, "id = ?", id).Error
-}
+// Close closes the connections pool, the transactions in progress must have ended.
+func (d database) Close() error {
+	db, err := d.driver.DB()
+	if err != nil {
+		return err
+	}
 
-// Update update the provided struct with it fields, the value Struct must have a ID
-//  var u User
-//  FindById(&u, 42)
-//  u.Name = "Marvin"
-//  Update(&u)
-func (d database) Update(value interface{}) error {
-	return d.driver.Save(value).Error
+	return db.Close()
 }
 
 // Migrate applies all migrations needed.
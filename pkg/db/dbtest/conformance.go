@@ -0,0 +1,815 @@
+// Package dbtest is the conformance suite of the db.DB implementations, so that the memory DB
+// used by the tests behaves as the postgres one.
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run runs the conformance suite, open returns an empty migrated DB for each test.
+func Run(t *testing.T, open func(t *testing.T) db.DB) {
+	tests := map[string]func(t *testing.T, d db.DB){
+		"currencies-seeded":          testCurrenciesSeeded,
+		"currency-rates":             testCurrencyRates,
+		"insert-sets-defaults":       testInsertSetsDefaults,
+		"insert-omits-associations":  testInsertOmitsAssociations,
+		"find-by-id":                 testFindByID,
+		"transactions":               testTransactions,
+		"items":                      testItems,
+		"sellers-with-unpaid-out":    testSellersWithUnpaidOutItems,
+		"payouts":                    testPayouts,
+		"payouts-statuses":           testPayoutsStatuses,
+		"lock-unpaid-items":          testLockUnpaidItems,
+		"pay-out-items":              testPayOutItems,
+		"constraints":                testConstraints,
+		"item-import-errors":         testItemImportErrors,
+		"finish-item-import":         testFinishItemImport,
+		"reconciliations":            testReconciliations,
+		"webhooks":                   testWebhooks,
+		"outbox":                     testOutbox,
+		"job-runs":                   testJobRuns,
+		"try-lock":                   testTryLock,
+		"cancelled-context-rejected": testCancelledContext,
+	}
+
+	for tn, tc := range tests {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			tc(t, open(t))
+		})
+	}
+}
+
+// epoch orders the rows created by the tests, postgres stores times to the microsecond.
+var epoch = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(minutes int) time.Time {
+	return epoch.Add(time.Duration(minutes) * time.Minute)
+}
+
+func newSeller(t *testing.T, d db.DB, createdAt time.Time) domain.Seller {
+	t.Helper()
+
+	s := domain.Seller{CreatedAt: createdAt, CurrencyCode: "USD", Name: "seller"}
+	require.NoError(t, d.InsertSeller(context.Background(), &s))
+
+	return s
+}
+
+func newItems(t *testing.T, d db.DB, s domain.Seller, n int) []domain.Item {
+	t.Helper()
+
+	items := make([]domain.Item, n)
+	for i := range items {
+		items[i] = domain.Item{
+			CreatedAt:     at(i),
+			ReferenceName: "item",
+			PriceAmount:   decimal.NewFromInt(int64(100 * (i + 1))),
+			CurrencyCode:  "USD",
+			SellerID:      s.ID,
+		}
+	}
+
+	require.NoError(t, d.InsertItems(context.Background(), items))
+
+	return items
+}
+
+func currency(t *testing.T, d db.DB, code string) domain.Currency {
+	t.Helper()
+
+	currencies, err := d.FindCurrencies(context.Background())
+	require.NoError(t, err)
+
+	for _, c := range currencies {
+		if c.Code == code {
+			return c
+		}
+	}
+
+	t.Fatalf("currency %s not found", code)
+
+	return domain.Currency{}
+}
+
+func newPayout(t *testing.T, d db.DB, s domain.Seller, items []domain.Item, createdAt time.Time) domain.Payout {
+	t.Helper()
+
+	p := domain.Payout{
+		CreatedAt:  createdAt,
+		SellerID:   s.ID,
+		CurrencyID: currency(t, d, "USD").ID,
+		PriceTotal: decimal.Zero,
+	}
+
+	for _, it := range items {
+		p.PriceTotal = p.PriceTotal.Add(it.PriceAmount)
+		p.Lines = append(p.Lines, domain.PayoutItem{
+			ItemID:          it.ID,
+			ConvertedAmount: decimal.NewNullDecimal(it.PriceAmount),
+			ExchangeRate:    decimal.NewNullDecimal(decimal.NewFromInt(1)),
+		})
+	}
+
+	require.NoError(t, d.InsertPayout(context.Background(), &p))
+
+	return p
+}
+
+func ids(items []domain.Item) []string {
+	s := make([]string, len(items))
+	for i, it := range items {
+		s[i] = it.ID.String()
+	}
+
+	return s
+}
+
+func testCurrenciesSeeded(t *testing.T, d db.DB) {
+	currencies, err := d.FindCurrencies(context.Background())
+	require.NoError(t, err)
+
+	rates := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		rates = append(rates, c.Code+" "+c.USDExchRate.String())
+	}
+
+	assert.Equal(t, []string{"EUR 0.88", "GBP 0.74", "USD 1"}, rates, "ordered by code")
+}
+
+func testCurrencyRates(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	eur := currency(t, d, "EUR")
+
+	eur.USDExchRate = decimal.RequireFromString("0.9")
+	require.NoError(t, d.UpdateCurrencyRates(ctx, []domain.Currency{eur}))
+	assert.Equal(t, "0.9", currency(t, d, "EUR").USDExchRate.String())
+
+	// none of the rates is saved when a code is unknown.
+	eur.USDExchRate = decimal.RequireFromString("0.5")
+	err := d.UpdateCurrencyRates(ctx, []domain.Currency{eur, {Code: "XXX", USDExchRate: decimal.NewFromInt(1)}})
+	assert.ErrorIs(t, err, db.ErrRecordNotFound)
+	assert.Equal(t, "0.9", currency(t, d, "EUR").USDExchRate.String())
+}
+
+func testInsertSetsDefaults(t *testing.T, d db.DB) {
+	ctx := context.Background()
+
+	s := domain.Seller{CurrencyCode: "USD"}
+	require.NoError(t, d.InsertSeller(ctx, &s))
+
+	assert.NotEqual(t, uuid.Nil, s.ID)
+	assert.False(t, s.CreatedAt.IsZero())
+	assert.False(t, s.UpdatedAt.IsZero())
+
+	p := domain.Payout{SellerID: s.ID, CurrencyID: currency(t, d, "USD").ID, PriceTotal: decimal.Zero}
+	require.NoError(t, d.InsertPayout(ctx, &p))
+	assert.Equal(t, domain.PayoutStatusCreated, p.Status)
+
+	sub := domain.WebhookSubscription{URL: "https://example.com", Events: string(domain.EventPayoutCreated)}
+	require.NoError(t, d.InsertWebhookSubscription(ctx, &sub))
+	assert.True(t, sub.Active)
+
+	m1, m2 := domain.OutboxMessage{Key: s.ID}, domain.OutboxMessage{Key: s.ID}
+	require.NoError(t, d.InsertOutboxMessage(ctx, &m1))
+	require.NoError(t, d.InsertOutboxMessage(ctx, &m2))
+	assert.Greater(t, m2.Seq, m1.Seq)
+}
+
+func testInsertOmitsAssociations(t *testing.T, d db.DB) {
+	ctx := context.Background()
+
+	s := domain.Seller{CurrencyCode: "USD", Items: []domain.Item{{PriceAmount: decimal.NewFromInt(10), CurrencyCode: "USD"}}}
+	require.NoError(t, d.InsertSeller(ctx, &s))
+
+	page, err := d.FindItems(ctx, db.ItemsFilter{SellerID: s.ID.String()})
+	require.NoError(t, err)
+	assert.Zero(t, page.Total, "the items of a seller are not inserted along")
+
+	items := []domain.Item{{
+		PriceAmount:  decimal.NewFromInt(10),
+		CurrencyCode: "USD",
+		SellerID:     s.ID,
+		Seller:       domain.Seller{CurrencyCode: "EUR", Name: "new"},
+	}}
+	require.NoError(t, d.InsertItems(ctx, items))
+	assert.NotEqual(t, uuid.Nil, items[0].ID)
+
+	got, err := d.FindSellerByID(ctx, s.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "USD", got.CurrencyCode, "the seller of an item is not saved along")
+}
+
+func testFindByID(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s := newSeller(t, d, at(0))
+
+	got, err := d.FindSellerByID(ctx, s.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, s.ID, got.ID)
+	assert.Equal(t, "USD", got.CurrencyCode)
+
+	_, err = d.FindSellerByID(ctx, uuid.Must(uuid.NewV4()).String())
+	assert.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	_, err = d.FindSellerByID(ctx, "not-a-uuid")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, db.ErrRecordNotFound)
+}
+
+func testTransactions(t *testing.T, d db.DB) {
+	ctx := context.Background()
+
+	tx, err := d.Begin(ctx)
+	require.NoError(t, err)
+
+	rolledBack := newSeller(t, tx, at(0))
+	require.NoError(t, tx.Rollback())
+
+	_, err = d.FindSellerByID(ctx, rolledBack.ID.String())
+	assert.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	tx, err = d.Begin(ctx)
+	require.NoError(t, err)
+
+	committed := newSeller(t, tx, at(0))
+	require.NoError(t, tx.Commit())
+
+	_, err = d.FindSellerByID(ctx, committed.ID.String())
+	assert.NoError(t, err)
+	assert.Error(t, tx.Commit(), "a transaction ends once")
+
+	tx, err = d.Begin(ctx)
+	require.NoError(t, err)
+
+	items := newItems(t, tx, committed, 1)
+	require.NoError(t, tx.PayOutItems(ctx, ids(items)))
+	require.NoError(t, tx.Rollback())
+
+	unpaid, err := d.FindUnpaidOutItemsBySellerID(ctx, committed.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, unpaid, "the items inserted in the transaction are rolled back as well")
+}
+
+func testItems(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s1, s2 := newSeller(t, d, at(0)), newSeller(t, d, at(1))
+	items := newItems(t, d, s1, 5)
+	newItems(t, d, s2, 1)
+	newPayout(t, d, s1, items[:1], at(0))
+	require.NoError(t, d.PayOutItems(ctx, ids(items[:1])))
+
+	first, err := d.FindItems(ctx, db.ItemsFilter{SellerID: s1.ID.String(), Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), first.Total)
+	assert.Equal(t, ids(items[:2]), ids(first.Items))
+	require.NotNil(t, first.Next)
+	require.NotNil(t, first.Items[0].PayoutItem, "the payout line of a paid out item is loaded")
+	assert.Nil(t, first.Items[1].PayoutItem)
+
+	last, err := d.FindItems(ctx, db.ItemsFilter{SellerID: s1.ID.String(), After: first.Next, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, ids(items[2:]), ids(last.Items))
+	assert.Nil(t, last.Next)
+
+	desc, err := d.FindItems(ctx, db.ItemsFilter{SellerID: s1.ID.String(), Desc: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, ids(items[4:]), ids(desc.Items))
+
+	paidOut := false
+	unpaid, err := d.FindItems(ctx, db.ItemsFilter{PaidOut: &paidOut, From: at(1), To: at(4)})
+	require.NoError(t, err)
+	assert.Equal(t, ids(items[1:4]), ids(unpaid.Items))
+
+	it, err := d.FindItemByID(ctx, items[0].ID.String())
+	require.NoError(t, err)
+	assert.True(t, it.PaidOut)
+	assert.True(t, it.PriceAmount.Equal(decimal.NewFromInt(100)))
+
+	_, err = d.FindItemByID(ctx, uuid.Must(uuid.NewV4()).String())
+	assert.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	bySeller, err := d.FindUnpaidOutItemsBySellerID(ctx, s1.ID.String())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ids(items[1:]), ids(bySeller))
+	assert.Equal(t, s1.ID, bySeller[0].Seller.ID, "the seller is loaded")
+
+	all, err := d.FindUnpaidOutItems(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	page, err := d.FindUnpaidOutItemsPage(ctx, s1.ID.String(), nil, 3)
+	require.NoError(t, err)
+	assert.Equal(t, ids(items[1:4]), ids(page.Items))
+	require.NotNil(t, page.Next)
+
+	page, err = d.FindUnpaidOutItemsPage(ctx, s1.ID.String(), page.Next, 3)
+	require.NoError(t, err)
+	assert.Equal(t, ids(items[4:]), ids(page.Items))
+	assert.Nil(t, page.Next)
+}
+
+func testSellersWithUnpaidOutItems(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s1, s2, s3, paid := newSeller(t, d, at(0)), newSeller(t, d, at(1)), newSeller(t, d, at(2)), newSeller(t, d, at(3))
+	newSeller(t, d, at(4))
+
+	for _, s := range []domain.Seller{s1, s2, s3} {
+		newItems(t, d, s, 1)
+	}
+
+	require.NoError(t, d.PayOutItems(ctx, ids(newItems(t, d, paid, 1))))
+
+	first, err := d.FindSellersWithUnpaidOutItems(ctx, db.SellersFilter{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{s1.ID, s2.ID}, []uuid.UUID{first.Sellers[0].ID, first.Sellers[1].ID})
+	require.NotNil(t, first.Next)
+
+	last, err := d.FindSellersWithUnpaidOutItems(ctx, db.SellersFilter{After: first.Next, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, last.Sellers, 1)
+	assert.Equal(t, s3.ID, last.Sellers[0].ID)
+	assert.Nil(t, last.Next)
+
+	some, err := d.FindSellersWithUnpaidOutItems(ctx, db.SellersFilter{IDs: []string{s2.ID.String(), paid.ID.String()}})
+	require.NoError(t, err)
+	require.Len(t, some.Sellers, 1)
+	assert.Equal(t, s2.ID, some.Sellers[0].ID)
+}
+
+func testPayouts(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s := newSeller(t, d, at(0))
+	items := newItems(t, d, s, 3)
+	p1 := newPayout(t, d, s, items[:2], at(0))
+	p2 := newPayout(t, d, s, items[2:], at(1))
+
+	assert.Equal(t, p1.ID, p1.Lines[0].PayoutID)
+
+	got, err := d.FindPayoutByID(ctx, p1.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "USD", got.Currency.Code)
+	require.Len(t, got.Lines, 2)
+	assert.ElementsMatch(t, ids(items[:2]), []string{got.Lines[0].Item.ID.String(), got.Lines[1].Item.ID.String()})
+	assert.True(t, got.PriceTotal.Equal(decimal.NewFromInt(300)))
+
+	_, err = d.FindPayoutByID(ctx, uuid.Must(uuid.NewV4()).String())
+	assert.ErrorIs(t, err, db.ErrRecordNotFound)
+
+	page, err := d.FindPayoutsBySellerID(ctx, s.ID.String(), db.PayoutsFilter{Desc: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	require.Len(t, page.Payouts, 1)
+	assert.Equal(t, p2.ID, page.Payouts[0].ID)
+	require.NotNil(t, page.Next)
+
+	page, err = d.FindPayoutsBySellerID(ctx, s.ID.String(), db.PayoutsFilter{Desc: true, After: page.Next, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Payouts, 1)
+	assert.Equal(t, p1.ID, page.Payouts[0].ID)
+	assert.Nil(t, page.Next)
+
+	page, err = d.FindPayoutsBySellerID(ctx, s.ID.String(), db.PayoutsFilter{From: at(1)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	sum, err := d.SumPayoutsBySellerID(ctx, s.ID.String(), db.PayoutsFilter{})
+	require.NoError(t, err)
+	assert.True(t, sum.Equal(decimal.NewFromInt(600)), sum.String())
+
+	sum, err = d.SumPayoutsBySellerID(ctx, s.ID.String(), db.PayoutsFilter{Status: domain.PayoutStatusSettled})
+	require.NoError(t, err)
+	assert.True(t, sum.IsZero())
+}
+
+func testPayoutsStatuses(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s := newSeller(t, d, at(0))
+	p1, p2 := newPayout(t, d, s, nil, at(0)), newPayout(t, d, s, nil, at(1))
+
+	assert.ErrorIs(t, d.UpdatePayoutStatus(ctx, p1.ID.String(), domain.PayoutStatusApproved, domain.PayoutStatusExported),
+		db.ErrRecordNotFound)
+	require.NoError(t, d.UpdatePayoutStatus(ctx, p1.ID.String(), domain.PayoutStatusCreated, domain.PayoutStatusApproved))
+
+	approved, err := d.FindPayoutsByStatus(ctx, domain.PayoutStatusApproved)
+	require.NoError(t, err)
+	require.Len(t, approved, 1)
+	assert.Equal(t, p1.ID, approved[0].ID)
+	assert.Equal(t, s.ID, approved[0].Seller.ID)
+	assert.Equal(t, "USD", approved[0].Currency.Code)
+
+	file := domain.BankFile{CurrencyCode: "USD", MessageID: "msg", ControlSum: decimal.Zero, Content: []byte("file")}
+	require.NoError(t, d.InsertBankFile(ctx, &file))
+
+	gotFile, err := d.FindBankFileByID(ctx, file.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("file"), gotFile.Content)
+
+	payoutIDs := []string{p1.ID.String(), p2.ID.String()}
+	require.NoError(t, d.ExportPayouts(ctx, file.ID.String(), payoutIDs[:1]))
+	assert.ErrorIs(t, d.ExportPayouts(ctx, file.ID.String(), payoutIDs), db.ErrPayoutsChanged)
+
+	exported, err := d.FindPayoutByID(ctx, p1.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.PayoutStatusExported, exported.Status)
+	require.NotNil(t, exported.BankFileID)
+	assert.Equal(t, file.ID, *exported.BankFileID)
+	assert.NotNil(t, exported.ExportedAt)
+
+	assert.NoError(t, d.SettlePayouts(ctx, nil, at(2)))
+	require.NoError(t, d.SettlePayouts(ctx, payoutIDs[:1], at(2)))
+	assert.ErrorIs(t, d.SettlePayouts(ctx, payoutIDs[:1], at(2)), db.ErrPayoutsChanged)
+
+	settled, err := d.FindPayoutByID(ctx, p1.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.PayoutStatusSettled, settled.Status)
+	require.NotNil(t, settled.SettledAt)
+	assert.True(t, settled.SettledAt.Equal(at(2)))
+}
+
+func testLockUnpaidItems(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s := newSeller(t, d, at(0))
+	items := newItems(t, d, s, 3)
+
+	assert.NoError(t, d.LockUnpaidItems(ctx, nil))
+
+	tx1, err := d.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx1.LockUnpaidItems(ctx, ids(items[:2])))
+
+	tx2, err := d.Begin(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, tx2.LockUnpaidItems(ctx, ids(items[1:])), db.ErrItemsUnavailable)
+	require.NoError(t, tx2.Rollback())
+
+	require.NoError(t, tx1.PayOutItems(ctx, ids(items[:1])))
+	require.NoError(t, tx1.Commit())
+
+	tx3, err := d.Begin(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, tx3.LockUnpaidItems(ctx, ids(items[1:])), "the locks are released at the end of the transaction")
+	assert.ErrorIs(t, tx3.LockUnpaidItems(ctx, ids(items[:1])), db.ErrItemsUnavailable, "paid out items are unavailable")
+	require.NoError(t, tx3.Rollback())
+}
+
+func testPayOutItems(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s := newSeller(t, d, at(0))
+	items := newItems(t, d, s, 2)
+
+	assert.NoError(t, d.PayOutItems(ctx, nil))
+	require.NoError(t, d.PayOutItems(ctx, ids(items[:1])))
+
+	tx, err := d.Begin(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, tx.PayOutItems(ctx, ids(items)), db.ErrItemsPaidOut)
+	require.NoError(t, tx.Rollback())
+
+	unpaid, err := d.FindUnpaidOutItemsBySellerID(ctx, s.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, ids(items[1:]), ids(unpaid), "the rolled back transaction pays nothing out")
+
+	assert.ErrorIs(t, d.PayOutItems(ctx, ids(items)), db.ErrItemsPaidOut)
+}
+
+func testConstraints(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	s := newSeller(t, d, at(0))
+	items := newItems(t, d, s, 1)
+	newPayout(t, d, s, items, at(0))
+
+	item := func(currencyCode string, price int64, sellerID uuid.UUID) func() error {
+		return func() error {
+			return d.InsertItems(ctx, []domain.Item{
+				{PriceAmount: decimal.NewFromInt(price), CurrencyCode: currencyCode, SellerID: sellerID},
+			})
+		}
+	}
+
+	tests := map[string]struct {
+		write      func() error
+		kind       error
+		constraint string
+	}{
+		"unknown-currency": {
+			write:      item("XXX", 1, s.ID),
+			kind:       db.ErrForeignKeyViolation,
+			constraint: "items_currency_code_fkey",
+		},
+		"unknown-seller": {
+			write:      item("USD", 1, uuid.Must(uuid.NewV4())),
+			kind:       db.ErrForeignKeyViolation,
+			constraint: "items_seller_id_fkey",
+		},
+		"zero-price": {
+			write:      item("USD", 0, s.ID),
+			kind:       db.ErrCheckViolation,
+			constraint: "items_price_amount_check",
+		},
+		"zero-rate": {
+			write: func() error {
+				return d.UpdateCurrencyRates(ctx, []domain.Currency{{Code: "USD", USDExchRate: decimal.Zero}})
+			},
+			kind:       db.ErrCheckViolation,
+			constraint: "currencies_usd_exch_rate_check",
+		},
+	}
+
+	for tn, tc := range tests {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			err := tc.write()
+
+			assert.ErrorIs(t, err, tc.kind)
+
+			var cerr *db.ConstraintError
+			if assert.True(t, errors.As(err, &cerr)) {
+				assert.Equal(t, tc.constraint, cerr.Constraint)
+			}
+		})
+	}
+
+	t.Run("item-in-another-payout", func(t *testing.T) {
+		p := domain.Payout{SellerID: s.ID, CurrencyID: currency(t, d, "USD").ID, PriceTotal: decimal.Zero,
+			Lines: []domain.PayoutItem{{ItemID: items[0].ID}}}
+
+		tx, err := d.Begin(ctx)
+		require.NoError(t, err)
+
+		err = tx.InsertPayout(ctx, &p)
+		require.NoError(t, tx.Rollback())
+
+		assert.ErrorIs(t, err, db.ErrUniqueViolation)
+
+		_, err = d.FindPayoutByID(ctx, p.ID.String())
+		assert.ErrorIs(t, err, db.ErrRecordNotFound)
+	})
+}
+
+func testItemImportErrors(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	imp := domain.ItemImport{Format: "csv", Status: domain.ItemImportStatusCompleted}
+	require.NoError(t, d.InsertItemImport(ctx, &imp))
+
+	errs := []domain.ItemImportError{
+		{RowNumber: 3, Message: "c", ItemImportID: imp.ID},
+		{RowNumber: 1, Message: "a", ItemImportID: imp.ID},
+		{RowNumber: 2, Message: "b", ItemImportID: imp.ID},
+	}
+	require.NoError(t, d.InsertItemImportErrors(ctx, errs))
+
+	got, err := d.FindItemImportErrors(ctx, imp.ID.String(), 1, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "b", got[0].Message)
+
+	got, err = d.FindItemImportErrors(ctx, imp.ID.String(), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, []int{got[0].RowNumber, got[1].RowNumber, got[2].RowNumber})
+}
+
+func testFinishItemImport(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	imp := domain.ItemImport{Format: "csv", Status: domain.ItemImportStatusProcessing}
+	require.NoError(t, d.InsertItemImport(ctx, &imp))
+
+	now := at(0)
+	imp.Status, imp.TotalRows, imp.ImportedRows, imp.FailedRows, imp.FinishedAt = domain.ItemImportStatusCompleted, 3, 2, 1, &now
+	require.NoError(t, d.FinishItemImport(ctx, imp))
+
+	got, err := d.FindItemImportByID(ctx, imp.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.ItemImportStatusCompleted, got.Status)
+	assert.Equal(t, []int{3, 2, 1}, []int{got.TotalRows, got.ImportedRows, got.FailedRows})
+	require.NotNil(t, got.FinishedAt)
+	assert.True(t, now.Equal(*got.FinishedAt))
+
+	assert.ErrorIs(t, d.FinishItemImport(ctx, domain.ItemImport{ID: uuid.Must(uuid.NewV4())}), db.ErrRecordNotFound)
+}
+
+func testReconciliations(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	r := domain.Reconciliation{Format: "camt053", Exceptions: []domain.ReconciliationException{
+		{CreatedAt: at(1), Kind: domain.ExceptionUnmatchedEntry, Message: "second"},
+		{CreatedAt: at(0), Kind: domain.ExceptionUnmatchedEntry, Message: "first"},
+	}}
+	require.NoError(t, d.InsertReconciliation(ctx, &r))
+
+	got, err := d.FindReconciliationByID(ctx, r.ID.String())
+	require.NoError(t, err)
+	require.Len(t, got.Exceptions, 2)
+	assert.Equal(t, "first", got.Exceptions[0].Message)
+	assert.Equal(t, r.ID, got.Exceptions[1].ReconciliationID)
+
+	_, err = d.FindReconciliationByID(ctx, uuid.Must(uuid.NewV4()).String())
+	assert.ErrorIs(t, err, db.ErrRecordNotFound)
+}
+
+func testWebhooks(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	sub := domain.WebhookSubscription{URL: "https://example.com", Events: string(domain.EventPayoutCreated)}
+	require.NoError(t, d.InsertWebhookSubscription(ctx, &sub))
+
+	subs, err := d.FindActiveWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, sub.ID, subs[0].ID)
+
+	deliveries := []domain.WebhookDelivery{
+		{EventID: sub.ID, NextAttemptAt: at(2), SubscriptionID: sub.ID},
+		{EventID: sub.ID, NextAttemptAt: at(1), SubscriptionID: sub.ID},
+		{EventID: sub.ID, NextAttemptAt: at(5), SubscriptionID: sub.ID},
+	}
+	require.NoError(t, d.InsertWebhookDeliveries(ctx, deliveries))
+
+	due, err := d.FindDueWebhookDeliveries(ctx, at(3), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, deliveries[1].ID, due[0].ID)
+	assert.Equal(t, "https://example.com", due[0].Subscription.URL)
+
+	delivered := due[0]
+	now := at(3)
+	delivered.Status, delivered.Attempts, delivered.DeliveredAt = domain.WebhookDeliveryDelivered, 1, &now
+	require.NoError(t, d.UpdateWebhookDelivery(ctx, delivered))
+
+	due, err = d.FindDueWebhookDeliveries(ctx, at(3), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, deliveries[0].ID, due[0].ID)
+
+	assert.ErrorIs(t, d.UpdateWebhookDelivery(ctx, domain.WebhookDelivery{ID: uuid.Must(uuid.NewV4())}), db.ErrRecordNotFound)
+
+	dead := deliveries[2]
+	dead.Status, dead.Attempts = domain.WebhookDeliveryDead, 8
+	require.NoError(t, d.UpdateWebhookDelivery(ctx, dead))
+
+	letter := domain.WebhookDeadLetter{Attempts: 8, LastError: "timeout", DeliveryID: dead.ID}
+	require.NoError(t, d.InsertWebhookDeadLetter(ctx, &letter))
+
+	gotLetter, err := d.FindWebhookDeadLetterByID(ctx, letter.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "timeout", gotLetter.LastError)
+
+	letters, err := d.FindWebhookDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+
+	require.NoError(t, d.ReplayWebhookDeadLetter(ctx, letter.ID.String(), at(4)))
+	assert.ErrorIs(t, d.ReplayWebhookDeadLetter(ctx, letter.ID.String(), at(4)), db.ErrRecordNotFound)
+
+	letters, err = d.FindWebhookDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, letters, "replayed dead letters are not listed")
+
+	due, err = d.FindDueWebhookDeliveries(ctx, at(4), 1)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, deliveries[0].ID, due[0].ID, "limited to the oldest due delivery")
+
+	due, err = d.FindDueWebhookDeliveries(ctx, at(4), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, dead.ID, due[1].ID)
+	assert.Zero(t, due[1].Attempts)
+}
+
+func testOutbox(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	key, other := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+
+	messages := []domain.OutboxMessage{
+		{Key: key, EventType: domain.EventPayoutCreated, Payload: []byte(`{}`)},
+		{Key: key, EventType: domain.EventPayoutCreated, Payload: []byte(`{}`)},
+		{Key: other, EventType: domain.EventPayoutCreated, Payload: []byte(`{}`)},
+		{Key: key, EventType: domain.EventPayoutCreated, Payload: []byte(`{}`)},
+		{Key: other, EventType: domain.EventPayoutCreated, Payload: []byte(`{}`)},
+	}
+	for i := range messages {
+		require.NoError(t, d.InsertOutboxMessage(ctx, &messages[i]))
+	}
+
+	now := at(0)
+	published := messages[0]
+	published.PublishedAt, published.Attempts = &now, 1
+	require.NoError(t, d.UpdateOutboxMessage(ctx, published))
+
+	// only the oldest pending message of each key.
+	got, err := d.FindUnpublishedOutboxMessages(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, []uuid.UUID{messages[1].ID, messages[2].ID}, []uuid.UUID{got[0].ID, got[1].ID})
+	assert.Equal(t, []byte(`{}`), got[0].Payload)
+
+	got, err = d.FindUnpublishedOutboxMessages(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, messages[1].ID, got[0].ID)
+
+	// a dead message lets the next one of its key through.
+	dead := messages[1]
+	dead.DeadAt, dead.Attempts, dead.LastError = &now, 8, "mock"
+	require.NoError(t, d.UpdateOutboxMessage(ctx, dead))
+
+	got, err = d.FindUnpublishedOutboxMessages(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, []uuid.UUID{messages[2].ID, messages[3].ID}, []uuid.UUID{got[0].ID, got[1].ID})
+
+	assert.ErrorIs(t, d.UpdateOutboxMessage(ctx, domain.OutboxMessage{ID: uuid.Must(uuid.NewV4())}), db.ErrRecordNotFound)
+}
+
+func testJobRuns(t *testing.T, d db.DB) {
+	ctx := context.Background()
+	runs := []domain.JobRun{
+		{CreatedAt: at(0), Job: "payouts", Status: domain.JobRunRunning, StartedAt: at(0)},
+		{CreatedAt: at(1), Job: "payouts", Status: domain.JobRunRunning, StartedAt: at(1)},
+		{CreatedAt: at(2), Job: "currencies", Status: domain.JobRunRunning, StartedAt: at(2)},
+	}
+	for i := range runs {
+		require.NoError(t, d.InsertJobRun(ctx, &runs[i]))
+	}
+
+	finished := runs[0]
+	finishedAt := at(1)
+	finished.Status, finished.FinishedAt, finished.PayoutsCreated = domain.JobRunSucceeded, &finishedAt, 2
+	finished.Totals = []domain.JobRunTotal{
+		{CurrencyCode: "USD", PayoutsCount: 1, Amount: decimal.NewFromInt(10)},
+		{CurrencyCode: "EUR", PayoutsCount: 1, Amount: decimal.NewFromInt(20)},
+	}
+	finished.Failures = []domain.JobRunFailure{{SellerID: uuid.Must(uuid.NewV4()), Error: "failed"}}
+	require.NoError(t, d.FinishJobRun(ctx, finished))
+
+	assert.ErrorIs(t, d.FinishJobRun(ctx, domain.JobRun{ID: uuid.Must(uuid.NewV4())}), db.ErrRecordNotFound)
+
+	got, err := d.FindJobRunByID(ctx, finished.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.PayoutsCreated)
+	require.Len(t, got.Totals, 2)
+	assert.Equal(t, []string{"EUR", "USD"}, []string{got.Totals[0].CurrencyCode, got.Totals[1].CurrencyCode})
+	require.Len(t, got.Failures, 1)
+	assert.Equal(t, "failed", got.Failures[0].Error)
+
+	page, err := d.FindJobRuns(ctx, db.JobRunsFilter{Job: "payouts", Desc: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	require.Len(t, page.Runs, 1)
+	assert.Equal(t, runs[1].ID, page.Runs[0].ID)
+	require.NotNil(t, page.Next)
+
+	page, err = d.FindJobRuns(ctx, db.JobRunsFilter{Job: "payouts", Desc: true, After: page.Next, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Runs, 1)
+	assert.Equal(t, finished.ID, page.Runs[0].ID)
+	assert.Len(t, page.Runs[0].Totals, 2)
+	assert.Nil(t, page.Next)
+}
+
+func testTryLock(t *testing.T, d db.DB) {
+	ctx := context.Background()
+
+	l, err := d.TryLock(ctx, "conformance")
+	require.NoError(t, err)
+
+	_, err = d.TryLock(ctx, "conformance")
+	assert.ErrorIs(t, err, db.ErrLockHeld)
+
+	other, err := d.TryLock(ctx, "conformance-other")
+	require.NoError(t, err)
+	assert.NoError(t, other.Release())
+
+	select {
+	case <-l.Lost():
+		t.Fatal("the lock should be held")
+	default:
+	}
+
+	require.NoError(t, l.Release())
+
+	l, err = d.TryLock(ctx, "conformance")
+	require.NoError(t, err)
+	assert.NoError(t, l.Release())
+}
+
+func testCancelledContext(t *testing.T, d db.DB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := d.FindCurrencies(ctx)
+	assert.Error(t, err)
+	assert.Error(t, d.InsertSeller(ctx, &domain.Seller{CurrencyCode: "USD"}))
+
+	_, err = d.Begin(ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package db
+
+// Truncate empties the tables of a postgres DB, and seeds the currencies again as the migrations do.
+func Truncate(d DB) error {
+	tables := "sellers, currencies, items, payouts, payout_items, item_imports, item_import_errors, bank_files, " +
+		"reconciliations, reconciliation_exceptions, webhook_subscriptions, webhook_deliveries, webhook_dead_letters, " +
+		"outbox, job_runs, job_run_totals, job_run_failures"
+
+	driver := d.(*database).driver
+	if err := driver.Exec("TRUNCATE " + tables + " CASCADE").Error; err != nil {
+		return err
+	}
+
+	return driver.Exec("INSERT INTO currencies (code, USD_exch_rate) VALUES ('GBP', 0.74), ('EUR', 0.88), ('USD', 1)").Error
+}
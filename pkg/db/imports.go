@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+)
+
+// InsertItemImport inserts an items import.
+func (d database) InsertItemImport(ctx context.Context, i *domain.ItemImport) error {
+	return d.driver.WithContext(ctx).Create(i).Error
+}
+
+// InsertItemImportErrors inserts rejected rows of an items import.
+func (d database) InsertItemImportErrors(ctx context.Context, errs []domain.ItemImportError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return d.driver.WithContext(ctx).Create(&errs).Error
+}
+
+// FindItemImportByID finds an items import by id, without its rejected rows.
+func (d database) FindItemImportByID(ctx context.Context, id string) (domain.ItemImport, error) {
+	var i domain.ItemImport
+
+	if err := d.driver.WithContext(ctx).Take(&i, "id = ?", id).Error; err != nil {
+		return domain.ItemImport{}, err
+	}
+
+	return i, nil
+}
+
+// FindItemImportErrors finds the rejected rows of an items import,
+// ordered by row and starting after the afterRow row.
+func (d database) FindItemImportErrors(ctx context.Context, importID string, afterRow, limit int) ([]domain.ItemImportError, error) {
+	var errs []domain.ItemImportError
+
+	err := d.driver.WithContext(ctx).
+		Where("item_import_id = ? AND row_number > ?", importID, afterRow).
+		Order("row_number ASC").
+		Limit(limit).
+		Find(&errs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+// FinishItemImport saves the outcome and the rows counters of an items import.
+func (d database) FinishItemImport(ctx context.Context, i domain.ItemImport) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.ItemImport{}).
+		Where("id = ?", i.ID).
+		Updates(map[string]interface{}{
+			"status":        i.Status,
+			"total_rows":    i.TotalRows,
+			"imported_rows": i.ImportedRows,
+			"failed_rows":   i.FailedRows,
+			"error":         i.Error,
+			"finished_at":   i.FinishedAt,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
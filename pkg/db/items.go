@@ -1,35 +1,152 @@
 package db
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
-	"fmt"
+	"strings"
+	"time"
 
 	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrItemsPaidOut is raised when items to pay out are already paid out.
+var ErrItemsPaidOut = errors.New("items already paid out")
+
+// ItemsFilter narrows down and paginates an items listing, zero values are ignored.
+type ItemsFilter struct {
+	SellerID     string
+	CurrencyCode string
+	PaidOut      *bool
+	// From and To bound the items creation date, From is inclusive and To exclusive.
+	From time.Time
+	To   time.Time
+	// Desc sorts items from the most recent.
+	Desc bool
+	// After is the keyset position from which the page starts, nil for the first page.
+	After *Cursor
+	Limit int
+}
+
+// ItemsPage is a page of items.
+type ItemsPage struct {
+	Items []domain.Item
+	// Next is the cursor of the following page, nil on the last page.
+	Next *Cursor
+	// Total is the number of items matching the filter, regardless of pagination.
+	Total int64
+}
+
+// InsertItems inserts items, without their seller nor their payout.
+func (d database) InsertItems(ctx context.Context, items []domain.Item) error {
+	return d.driver.WithContext(ctx).Omit(clause.Associations).Create(&items).Error
+}
+
 // FindUnpaidOutItemsBySellerID finds unpaid out itmes by seller_id.
-func (d database) FindUnpaidOutItemsBySellerID(id string) ([]domain.Item, error) {
+func (d database) FindUnpaidOutItemsBySellerID(ctx context.Context, id string) ([]domain.Item, error) {
 	where := Conditions{"seller_id": id, "paid_out": false}
 
-	return d.items(where)
+	return d.items(ctx, where)
 }
 
 // FindUnpaidOutItems finds unpaid out itmes.
-func (d database) FindUnpaidOutItems() ([]domain.Item, error) {
+func (d database) FindUnpaidOutItems(ctx context.Context) ([]domain.Item, error) {
 	where := Conditions{"paid_out": false}
 
-	return d.items(where)
+	return d.items(ctx, where)
 }
 
-func (d database) items(where Conditions) ([]domain.Item, error) {
-	var items []domain.Item
+// FindUnpaidOutItemsPage finds a page of the unpaid out items of a seller, ordered by (created_at, id).
+// Unlike FindItems, the items are not counted nor their payout loaded.
+func (d database) FindUnpaidOutItemsPage(ctx context.Context, sellerID string, after *Cursor, limit int) (ItemsPage, error) {
+	tx := d.driver.WithContext(ctx).Model(&domain.Item{}).Where("seller_id = ? AND paid_out = false", sellerID)
+
+	var page ItemsPage
+
+	if err := keyset(tx, after, false, limit).Find(&page.Items).Error; err != nil {
+		return ItemsPage{}, err
+	}
+
+	if limit > 0 && len(page.Items) > limit {
+		page.Items = page.Items[:limit]
+		last := page.Items[limit-1]
+		page.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// PayOutItems marks the items as paid out, ErrItemsPaidOut is returned when some of them
+// are already paid out so that the transaction does not pay them out twice.
+func (d database) PayOutItems(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx := d.driver.WithContext(ctx).Exec("UPDATE items SET paid_out = true, updated_at = ? WHERE id = ANY(?) AND paid_out = false",
+		time.Now(), uuidArray(ids))
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected != int64(len(ids)) {
+		return ErrItemsPaidOut
+	}
+
+	return nil
+}
+
+// uuidArray binds ids as a single uuid[] parameter, gorm would expand a slice into a list of parameters.
+type uuidArray []string
+
+// Value implements driver.Valuer.
+func (a uuidArray) Value() (driver.Value, error) {
+	return "{" + strings.Join(a, ",") + "}", nil
+}
 
-	db, err := d.preloadItemsRelations()
-	if err != nil {
-		return nil, fmt.Errorf("failed to preload Currencies: %w", err)
+// FindItems finds a page of items.
+func (d database) FindItems(ctx context.Context, f ItemsFilter) (ItemsPage, error) {
+	where := func() *gorm.DB {
+		return f.where(d.driver.WithContext(ctx).Model(&domain.Item{}))
 	}
 
-	err = db.FindAllWhere(&items, where)
+	var page ItemsPage
+
+	if err := where().Count(&page.Total).Error; err != nil {
+		return ItemsPage{}, err
+	}
+
+	tx := keyset(where(), f.After, f.Desc, f.Limit).Preload("PayoutItem")
+	if err := tx.Find(&page.Items).Error; err != nil {
+		return ItemsPage{}, err
+	}
+
+	if f.Limit > 0 && len(page.Items) > f.Limit {
+		page.Items = page.Items[:f.Limit]
+		last := page.Items[f.Limit-1]
+		page.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// FindItemByID finds an item by id.
+func (d database) FindItemByID(ctx context.Context, id string) (domain.Item, error) {
+	var item domain.Item
+
+	if err := d.driver.WithContext(ctx).Preload("PayoutItem").Take(&item, "id = ?", id).Error; err != nil {
+		return domain.Item{}, err
+	}
+
+	return item, nil
+}
+
+func (d database) items(ctx context.Context, where Conditions) ([]domain.Item, error) {
+	var items []domain.Item
+
+	err := d.driver.WithContext(ctx).Preload("Seller").Where(map[string]interface{}(where)).Find(&items).Error
 	if err == nil {
 		return items, nil
 	}
@@ -41,8 +158,27 @@ func (d database) items(where Conditions) ([]domain.Item, error) {
 	return nil, err
 }
 
-func (d database) preloadItemsRelations() (DB, error) {
-	tx := d.driver.Preload("Seller")
+// where applies the filter conditions, pagination excluded.
+func (f ItemsFilter) where(tx *gorm.DB) *gorm.DB {
+	if f.SellerID != "" {
+		tx = tx.Where("seller_id = ?", f.SellerID)
+	}
+
+	if f.CurrencyCode != "" {
+		tx = tx.Where("currency_code = ?", f.CurrencyCode)
+	}
+
+	if f.PaidOut != nil {
+		tx = tx.Where("paid_out = ?", *f.PaidOut)
+	}
+
+	if !f.From.IsZero() {
+		tx = tx.Where("created_at >= ?", f.From)
+	}
+
+	if !f.To.IsZero() {
+		tx = tx.Where("created_at < ?", f.To)
+	}
 
-	return &database{driver: tx}, tx.Error
+	return tx
 }
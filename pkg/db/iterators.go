@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+)
+
+// SellerIterator streams the sellers having unpaid out items, fetched in keyset paginated chunks
+// so that only a chunk is held in memory.
+//
+//	sellers := db.NewSellerIterator(db.NewUnitOfWork(d).Sellers(), nil, 100)
+//	for sellers.Next(ctx) {
+//		fmt.Println(sellers.Seller().ID)
+//	}
+//	if err := sellers.Err(); err != nil {
+//		...
+//	}
+type SellerIterator struct {
+	repo   SellerRepository
+	filter SellersFilter
+	chunk  []domain.Seller
+	seller domain.Seller
+	last   bool
+	err    error
+}
+
+// NewSellerIterator returns an iterator over the sellers having unpaid out items,
+// restricted to ids when not empty, fetched by chunks of size sellers.
+func NewSellerIterator(repo SellerRepository, ids []string, size int) *SellerIterator {
+	return &SellerIterator{repo: repo, filter: SellersFilter{IDs: ids, Limit: size}}
+}
+
+// Next advances to the next seller, false is returned once the sellers are exhausted or on error.
+func (it *SellerIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.chunk) == 0 {
+		if it.last {
+			return false
+		}
+
+		page, err := it.repo.FindWithUnpaidOutItems(ctx, it.filter)
+		if err != nil {
+			it.err = err
+
+			return false
+		}
+
+		it.chunk = page.Sellers
+		it.filter.After = page.Next
+		it.last = page.Next == nil
+
+		if len(it.chunk) == 0 {
+			return false
+		}
+	}
+
+	it.seller, it.chunk = it.chunk[0], it.chunk[1:]
+
+	return true
+}
+
+// Seller returns the current seller.
+func (it *SellerIterator) Seller() domain.Seller {
+	return it.seller
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *SellerIterator) Err() error {
+	return it.err
+}
+
+// ItemIterator streams the unpaid out items of a seller, fetched in keyset paginated chunks.
+// The items paid out meanwhile behind the iterator do not shift the following chunks.
+type ItemIterator struct {
+	repo     ItemRepository
+	sellerID string
+	size     int
+	after    *Cursor
+	chunk    []domain.Item
+	item     domain.Item
+	last     bool
+	err      error
+}
+
+// NewItemIterator returns an iterator over the unpaid out items of a seller, fetched by chunks of size items.
+func NewItemIterator(repo ItemRepository, sellerID string, size int) *ItemIterator {
+	return &ItemIterator{repo: repo, sellerID: sellerID, size: size}
+}
+
+// Next advances to the next item, false is returned once the items are exhausted or on error.
+func (it *ItemIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.chunk) == 0 {
+		if it.last {
+			return false
+		}
+
+		page, err := it.repo.FindUnpaidOutPage(ctx, it.sellerID, it.after, it.size)
+		if err != nil {
+			it.err = err
+
+			return false
+		}
+
+		it.chunk = page.Items
+		it.after = page.Next
+		it.last = page.Next == nil
+
+		if len(it.chunk) == 0 {
+			return false
+		}
+	}
+
+	it.item, it.chunk = it.chunk[0], it.chunk[1:]
+
+	return true
+}
+
+// Item returns the current item.
+func (it *ItemIterator) Item() domain.Item {
+	return it.item
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *ItemIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// pagesDB serves the pages of sellers and items in order, then fails with err if any.
+type pagesDB struct {
+	DB
+	sellers []SellersPage
+	items   []ItemsPage
+	err     error
+	filters []SellersFilter
+	afters  []*Cursor
+}
+
+func (p *pagesDB) FindSellersWithUnpaidOutItems(_ context.Context, f SellersFilter) (SellersPage, error) {
+	p.filters = append(p.filters, f)
+	if len(p.sellers) == 0 {
+		return SellersPage{}, p.err
+	}
+
+	page := p.sellers[0]
+	p.sellers = p.sellers[1:]
+
+	return page, nil
+}
+
+func (p *pagesDB) FindUnpaidOutItemsPage(_ context.Context, _ string, after *Cursor, _ int) (ItemsPage, error) {
+	p.afters = append(p.afters, after)
+	if len(p.items) == 0 {
+		return ItemsPage{}, p.err
+	}
+
+	page := p.items[0]
+	p.items = p.items[1:]
+
+	return page, nil
+}
+
+func TestSellerIterator(t *testing.T) {
+	s1, s2, s3 := domain.Seller{ID: uuid.Must(uuid.NewV4())}, domain.Seller{ID: uuid.Must(uuid.NewV4())},
+		domain.Seller{ID: uuid.Must(uuid.NewV4())}
+	next := &Cursor{ID: s2.ID}
+
+	t.Run("all-chunks", func(t *testing.T) {
+		d := &pagesDB{sellers: []SellersPage{{Sellers: []domain.Seller{s1, s2}, Next: next}, {Sellers: []domain.Seller{s3}}}}
+		it := NewSellerIterator(NewUnitOfWork(d).Sellers(), []string{"a"}, 2)
+
+		var ids []uuid.UUID
+		for it.Next(context.Background()) {
+			ids = append(ids, it.Seller().ID)
+		}
+
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []uuid.UUID{s1.ID, s2.ID, s3.ID}, ids)
+		assert.Equal(t, []SellersFilter{
+			{IDs: []string{"a"}, Limit: 2},
+			{IDs: []string{"a"}, After: next, Limit: 2},
+		}, d.filters)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		it := NewSellerIterator(NewUnitOfWork(&pagesDB{}).Sellers(), nil, 2)
+
+		assert.False(t, it.Next(context.Background()))
+		assert.NoError(t, it.Err())
+	})
+
+	t.Run("fail-db", func(t *testing.T) {
+		merr := errors.New("mock")
+		d := &pagesDB{sellers: []SellersPage{{Sellers: []domain.Seller{s1}, Next: next}}, err: merr}
+		it := NewSellerIterator(NewUnitOfWork(d).Sellers(), nil, 1)
+
+		assert.True(t, it.Next(context.Background()))
+		assert.False(t, it.Next(context.Background()))
+		assert.False(t, it.Next(context.Background()))
+		assert.ErrorIs(t, it.Err(), merr)
+		assert.Len(t, d.filters, 2)
+	})
+}
+
+func TestItemIterator(t *testing.T) {
+	i1, i2 := domain.Item{ID: uuid.Must(uuid.NewV4())}, domain.Item{ID: uuid.Must(uuid.NewV4())}
+	next := &Cursor{ID: i1.ID}
+
+	t.Run("all-chunks", func(t *testing.T) {
+		d := &pagesDB{items: []ItemsPage{{Items: []domain.Item{i1}, Next: next}, {Items: []domain.Item{i2}}}}
+		it := NewItemIterator(NewUnitOfWork(d).Items(), "seller", 1)
+
+		var ids []uuid.UUID
+		for it.Next(context.Background()) {
+			ids = append(ids, it.Item().ID)
+		}
+
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []uuid.UUID{i1.ID, i2.ID}, ids)
+		assert.Equal(t, []*Cursor{nil, next}, d.afters)
+	})
+
+	t.Run("fail-db", func(t *testing.T) {
+		merr := errors.New("mock")
+		it := NewItemIterator(NewUnitOfWork(&pagesDB{err: merr}).Items(), "seller", 1)
+
+		assert.False(t, it.Next(context.Background()))
+		assert.ErrorIs(t, it.Err(), merr)
+	})
+}
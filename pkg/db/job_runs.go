@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobRunsFilter narrows down and paginates a job runs listing, zero values are ignored.
+type JobRunsFilter struct {
+	Job    string
+	Status domain.JobRunStatus
+	// Desc sorts runs from the most recent.
+	Desc bool
+	// After is the keyset position from which the page starts, nil for the first page.
+	After *Cursor
+	Limit int
+}
+
+// JobRunsPage is a page of job runs.
+type JobRunsPage struct {
+	Runs []domain.JobRun
+	// Next is the cursor of the following page, nil on the last page.
+	Next *Cursor
+	// Total is the number of runs matching the filter, regardless of pagination.
+	Total int64
+}
+
+// InsertJobRun inserts a run, its totals and failures are saved by FinishJobRun.
+func (d database) InsertJobRun(ctx context.Context, r *domain.JobRun) error {
+	return d.driver.WithContext(ctx).Omit(clause.Associations).Create(r).Error
+}
+
+// FinishJobRun saves the outcome of a run along with its totals and failures.
+func (d database) FinishJobRun(ctx context.Context, r domain.JobRun) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.JobRun{}).
+		Where("id = ?", r.ID).
+		Updates(map[string]interface{}{
+			"status":            r.Status,
+			"finished_at":       r.FinishedAt,
+			"duration_ms":       r.DurationMS,
+			"error":             r.Error,
+			"sellers_processed": r.SellersProcessed,
+			"sellers_failed":    r.SellersFailed,
+			"payouts_created":   r.PayoutsCreated,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	if len(r.Totals) > 0 {
+		totals := make([]domain.JobRunTotal, len(r.Totals))
+		for i, t := range r.Totals {
+			t.JobRunID = r.ID
+			totals[i] = t
+		}
+
+		if err := d.driver.WithContext(ctx).Create(&totals).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(r.Failures) == 0 {
+		return nil
+	}
+
+	failures := make([]domain.JobRunFailure, len(r.Failures))
+	for i, f := range r.Failures {
+		f.JobRunID = r.ID
+		failures[i] = f
+	}
+
+	return d.driver.WithContext(ctx).Create(&failures).Error
+}
+
+// FindJobRuns finds a page of job runs along with their totals and failures.
+func (d database) FindJobRuns(ctx context.Context, f JobRunsFilter) (JobRunsPage, error) {
+	where := func() *gorm.DB {
+		return f.where(d.driver.WithContext(ctx).Model(&domain.JobRun{}))
+	}
+
+	var page JobRunsPage
+
+	if err := where().Count(&page.Total).Error; err != nil {
+		return JobRunsPage{}, err
+	}
+
+	tx := keyset(where(), f.After, f.Desc, f.Limit).
+		Preload("Totals", orderTotals).
+		Preload("Failures", orderFailures)
+	if err := tx.Find(&page.Runs).Error; err != nil {
+		return JobRunsPage{}, err
+	}
+
+	if f.Limit > 0 && len(page.Runs) > f.Limit {
+		page.Runs = page.Runs[:f.Limit]
+		last := page.Runs[f.Limit-1]
+		page.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// FindJobRunByID finds a job run by id along with its totals and failures.
+func (d database) FindJobRunByID(ctx context.Context, id string) (domain.JobRun, error) {
+	var r domain.JobRun
+
+	err := d.driver.WithContext(ctx).
+		Preload("Totals", orderTotals).
+		Preload("Failures", orderFailures).
+		Take(&r, "id = ?", id).Error
+	if err != nil {
+		return domain.JobRun{}, err
+	}
+
+	return r, nil
+}
+
+func orderTotals(tx *gorm.DB) *gorm.DB {
+	return tx.Order("currency_code ASC")
+}
+
+func orderFailures(tx *gorm.DB) *gorm.DB {
+	return tx.Order("created_at ASC, id ASC")
+}
+
+// where applies the filter conditions, pagination excluded.
+func (f JobRunsFilter) where(tx *gorm.DB) *gorm.DB {
+	if f.Job != "" {
+		tx = tx.Where("job = ?", f.Job)
+	}
+
+	if f.Status != "" {
+		tx = tx.Where("status = ?", f.Status)
+	}
+
+	return tx
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// leaseRenewal is the interval the lock session is checked at.
+	leaseRenewal = 10 * time.Second
+	lockTimeout  = 5 * time.Second
+	// lockCheckAttempts is the number of failed checks in a row after which the lock is deemed lost,
+	// a single failing query does not tell that the session dropped.
+	lockCheckAttempts = 3
+	lockCheckRetry    = time.Second
+)
+
+var (
+	// ErrLockHeld is raised when a lock is held by another session.
+	ErrLockHeld = errors.New("lock held by another session")
+	// ErrItemsUnavailable is raised when items are paid out or being paid out by another transaction.
+	ErrItemsUnavailable = errors.New("items no longer available")
+)
+
+// lockKey maps a lock name to an advisory lock key, kept positive so that
+// it can be found in pg_locks as (classid << 32) | objid.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	return int64(h.Sum64() >> 1)
+}
+
+// TryLock acquires a session advisory lock on a connection of its own, ErrLockHeld is returned
+// when another session holds it. The session is checked periodically, Lost is closed if it drops.
+// It is not meant to be called on a transaction.
+func (d database) TryLock(ctx context.Context, name string) (Lock, error) {
+	sqlDB, err := d.driver.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := lockKey(name)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	if !acquired {
+		_ = conn.Close()
+
+		return nil, ErrLockHeld
+	}
+
+	l := &advisoryLock{
+		conn: conn,
+		key:  key,
+		lost: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go l.renew()
+
+	return l, nil
+}
+
+type advisoryLock struct {
+	conn *sql.Conn
+	key  int64
+	lost chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func (l *advisoryLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// renew checks on every lease renewal that the session still holds the lock.
+func (l *advisoryLock) renew() {
+	ticker := time.NewTicker(leaseRenewal)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !l.check() {
+				close(l.lost)
+
+				return
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// check tells whether the session still holds the lock, the check is retried when it fails.
+func (l *advisoryLock) check() bool {
+	for i := 0; i < lockCheckAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(lockCheckRetry):
+			case <-l.done:
+				return true
+			}
+		}
+
+		held, err := l.held()
+		if err == nil {
+			return held
+		}
+	}
+
+	return false
+}
+
+func (l *advisoryLock) held() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	var held bool
+
+	err := l.conn.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND granted AND pid = pg_backend_pid()
+		AND objsubid = 1 AND ((classid::bigint << 32) | objid::bigint) = $1
+	)`, l.key).Scan(&held)
+
+	return held, err
+}
+
+// Release releases the lock and the connection holding it.
+func (l *advisoryLock) Release() error {
+	var err error
+
+	l.once.Do(func() {
+		close(l.done)
+
+		ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+		defer cancel()
+
+		_, err = l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+
+		// closing the session releases the lock anyway.
+		if cerr := l.conn.Close(); err == nil {
+			err = cerr
+		}
+	})
+
+	return err
+}
+
+// LockUnpaidItems locks the items to pay out for the rest of the transaction, skipping the ones
+// locked by another transaction. ErrItemsUnavailable is returned when some of them are paid out
+// or being paid out meanwhile.
+func (d database) LockUnpaidItems(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var locked []string
+
+	err := d.driver.WithContext(ctx).Model(&domain.Item{}).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id IN ? AND paid_out = ?", ids, false).
+		Pluck("id", &locked).Error
+	if err != nil {
+		return err
+	}
+
+	if len(locked) != len(ids) {
+		return ErrItemsUnavailable
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockKey(t *testing.T) {
+	assert.Equal(t, lockKey("create-payouts"), lockKey("create-payouts"))
+	assert.NotEqual(t, lockKey("create-payouts"), lockKey("update-currencies"))
+
+	for _, name := range []string{"", "create-payouts", "update-currencies", "flush-outbox"} {
+		assert.Positive(t, lockKey(name))
+	}
+}
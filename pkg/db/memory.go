@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// memory is an in-memory DB for tests and local development, it behaves as the postgres DB
+// once the migrations are run: same defaults, constraints and typed errors, same orders and preloads.
+//
+// Its transactions are not isolated: their writes are visible to the others before the commit,
+// and undone on rollback. The rows locked by LockUnpaidItems are only skipped by LockUnpaidItems,
+// they do not block the other writes.
+type memory struct {
+	s *store
+	// tx is nil outside of a transaction.
+	tx *memTx
+}
+
+// memTx is a transaction of the memory DB, its writes are undone in reverse order on rollback.
+type memTx struct {
+	undo []func()
+	done bool
+}
+
+// NewMemory returns an empty in-memory DB, with the currencies seeded by the migrations.
+func NewMemory() DB {
+	m := memory{s: newStore()}
+
+	seed := []domain.Currency{
+		{Code: "GBP", USDExchRate: decimal.RequireFromString("0.74")},
+		{Code: "EUR", USDExchRate: decimal.RequireFromString("0.88")},
+		{Code: "USD", USDExchRate: decimal.NewFromInt(1)},
+	}
+
+	if err := m.insert(context.Background(), &seed); err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// read runs fn under the store lock, once ctx and the transaction are checked.
+func (m memory) read(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if m.tx != nil && m.tx.done {
+		return sql.ErrTxDone
+	}
+
+	return fn()
+}
+
+// write runs fn under the store lock as a single statement: its writes are undone if it fails,
+// and kept in the transaction otherwise so that a rollback undoes them.
+func (m memory) write(ctx context.Context, fn func(w *writer) error) error {
+	return m.read(ctx, func() error {
+		w := &writer{s: m.s}
+
+		if err := fn(w); err != nil {
+			w.rollback()
+
+			return err
+		}
+
+		if m.tx != nil {
+			m.tx.undo = append(m.tx.undo, w.undo...)
+		}
+
+		return nil
+	})
+}
+
+func (m memory) Health(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Begin begins a transaction, a transaction begun on a transaction is the same transaction.
+func (m memory) Begin(ctx context.Context) (DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if m.tx != nil {
+		return m, nil
+	}
+
+	return memory{s: m.s, tx: &memTx{}}, nil
+}
+
+func (m memory) Commit() error {
+	return m.end(false)
+}
+
+func (m memory) Rollback() error {
+	return m.end(true)
+}
+
+func (m memory) end(rollback bool) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if m.tx == nil || m.tx.done {
+		return sql.ErrTxDone
+	}
+
+	if rollback {
+		for i := len(m.tx.undo) - 1; i >= 0; i-- {
+			m.tx.undo[i]()
+		}
+	}
+
+	m.tx.done = true
+	m.tx.undo = nil
+	m.s.unlockRows(m.tx)
+
+	return nil
+}
+
+// insert inserts a struct or a slice of structs without their associations,
+// as gorm Create does with the associations omitted.
+func (m memory) insert(ctx context.Context, dest interface{}) error {
+	return m.write(ctx, func(w *writer) error {
+		return w.each(dest, w.create)
+	})
+}
+
+// findByID loads the row of dest, a pointer to a struct, by id.
+func (m memory) findByID(ctx context.Context, dest interface{}, id string) error {
+	return m.read(ctx, func() error {
+		rv := reflect.ValueOf(dest)
+		if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("%w: %T", errUnsupportedDest, dest)
+		}
+
+		uid, err := parseUUID(id)
+		if err != nil {
+			return err
+		}
+
+		t, err := m.s.table(dest)
+		if err != nil {
+			return err
+		}
+
+		row, ok := t.rows[uid]
+		if !ok {
+			return ErrRecordNotFound
+		}
+
+		rv.Elem().Set(clone(t, row))
+
+		return nil
+	})
+}
+
+// findAllWhere finds the rows equal to the conditions, a nil condition matches NULL and a slice any of its values.
+func (m memory) findAllWhere(ctx context.Context, dest interface{}, conds map[string]interface{}) error {
+	return m.read(ctx, func() error {
+		return m.s.load(dest, func(t *table, row reflect.Value) bool {
+			for column, cond := range conds {
+				if !t.matches(row, column, cond) {
+					return false
+				}
+			}
+
+			return true
+		})
+	})
+}
+
+// TryLock acquires a lock held until released, ErrLockHeld is returned when it is held already.
+func (m memory) TryLock(ctx context.Context, name string) (Lock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+
+	if m.s.locks[name] {
+		return nil, ErrLockHeld
+	}
+
+	m.s.locks[name] = true
+
+	return &memoryLock{s: m.s, name: name, lost: make(chan struct{})}, nil
+}
+
+type memoryLock struct {
+	s    *store
+	name string
+	lost chan struct{}
+	once sync.Once
+}
+
+// Lost is never closed, the lock cannot be lost.
+func (l *memoryLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *memoryLock) Release() error {
+	l.once.Do(func() {
+		l.s.mu.Lock()
+		defer l.s.mu.Unlock()
+
+		delete(l.s.locks, l.name)
+	})
+
+	return nil
+}
+
+// RunMigrations does nothing, the memory DB is created migrated.
+func (m memory) RunMigrations(string) error {
+	return nil
+}
+
+func (m memory) Close() error {
+	return nil
+}
+
+// parseUUID parses an id as postgres does for a uuid column.
+func parseUUID(id string) (uuid.UUID, error) {
+	uid, err := uuid.FromString(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %q", errInvalidUUID, id)
+	}
+
+	return uid, nil
+}
+
+func parseUUIDs(ids []string) ([]uuid.UUID, error) {
+	uids := make([]uuid.UUID, 0, len(ids))
+
+	for _, id := range ids {
+		uid, err := parseUUID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		uids = append(uids, uid)
+	}
+
+	return uids, nil
+}
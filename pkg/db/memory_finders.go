@@ -0,0 +1,1125 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// pageOf returns the indexes of the rows of a keyset page and the cursor of the next one,
+// keys are the positions of the rows in ascending order.
+func pageOf(keys []Cursor, after *Cursor, desc bool, limit int) ([]int, *Cursor) {
+	var indexes []int
+
+	for n := 0; n < len(keys); n++ {
+		i := n
+		if desc {
+			i = len(keys) - 1 - n
+		}
+
+		if after != nil && (!desc && !after.before(keys[i]) || desc && !keys[i].before(*after)) {
+			continue
+		}
+
+		if limit > 0 && len(indexes) == limit {
+			return indexes, &keys[indexes[limit-1]]
+		}
+
+		indexes = append(indexes, i)
+	}
+
+	return indexes, nil
+}
+
+// inRange tells whether a time is within [from, to), zero bounds are ignored.
+func inRange(at, from, to time.Time) bool {
+	return (from.IsZero() || !at.Before(from)) && (to.IsZero() || at.Before(to))
+}
+
+// updateWhere updates the rows of a model matching filter, and returns the number of rows updated.
+func (m memory) updateWhere(ctx context.Context, model interface{}, filter func(row reflect.Value) bool,
+	columns map[string]interface{}) (int, error) {
+	var n int
+
+	err := m.write(ctx, func(w *writer) error {
+		t, err := m.s.table(model)
+		if err != nil {
+			return err
+		}
+
+		n, err = w.update(t, filter, columns)
+
+		return err
+	})
+
+	return n, err
+}
+
+func (m memory) FindPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (PayoutsPage, error) {
+	var page PayoutsPage
+
+	err := m.read(ctx, func() error {
+		sellerID, err := parseUUID(id)
+		if err != nil {
+			return err
+		}
+
+		var payouts []domain.Payout
+		if err := m.s.load(&payouts, nil); err != nil {
+			return err
+		}
+
+		var keys []Cursor
+
+		matching := payouts[:0]
+
+		for _, p := range payouts {
+			if p.SellerID == sellerID && f.matches(p) {
+				matching = append(matching, p)
+				keys = append(keys, Cursor{CreatedAt: p.CreatedAt, ID: p.ID})
+			}
+		}
+
+		page.Total = int64(len(matching))
+
+		indexes, next := pageOf(keys, f.After, f.Desc, f.Limit)
+		page.Next = next
+
+		for _, i := range indexes {
+			p := matching[i]
+			if err := m.preloadPayout(&p); err != nil {
+				return err
+			}
+
+			page.Payouts = append(page.Payouts, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return PayoutsPage{}, err
+	}
+
+	return page, nil
+}
+
+func (m memory) SumPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (decimal.Decimal, error) {
+	sum := decimal.Zero
+
+	err := m.read(ctx, func() error {
+		sellerID, err := parseUUID(id)
+		if err != nil {
+			return err
+		}
+
+		var payouts []domain.Payout
+		if err := m.s.load(&payouts, nil); err != nil {
+			return err
+		}
+
+		for _, p := range payouts {
+			if p.SellerID == sellerID && f.matches(p) {
+				sum = sum.Add(p.PriceTotal)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return sum, nil
+}
+
+// matches applies the filter conditions, pagination excluded.
+func (f PayoutsFilter) matches(p domain.Payout) bool {
+	return inRange(p.CreatedAt, f.From, f.To) && (f.Status == "" || p.Status == f.Status)
+}
+
+func (m memory) FindPayoutByID(ctx context.Context, id string) (domain.Payout, error) {
+	var p domain.Payout
+
+	if err := m.findByID(ctx, &p, id); err != nil {
+		return domain.Payout{}, err
+	}
+
+	err := m.read(ctx, func() error {
+		return m.preloadPayout(&p)
+	})
+	if err != nil {
+		return domain.Payout{}, err
+	}
+
+	return p, nil
+}
+
+// preloadPayout loads the currency of a payout and its lines along with their item.
+func (m memory) preloadPayout(p *domain.Payout) error {
+	if err := m.take(&p.Currency, p.CurrencyID); err != nil {
+		return err
+	}
+
+	var lines []domain.PayoutItem
+	if err := m.s.load(&lines, nil); err != nil {
+		return err
+	}
+
+	p.Lines = nil
+
+	for _, l := range lines {
+		if l.PayoutID != p.ID {
+			continue
+		}
+
+		if err := m.take(&l.Item, l.ItemID); err != nil {
+			return err
+		}
+
+		p.Lines = append(p.Lines, l)
+	}
+
+	return nil
+}
+
+// take loads the row of dest, a pointer to a struct, by id. Missing rows leave dest zero as preloads do.
+func (m memory) take(dest interface{}, id uuid.UUID) error {
+	t, err := m.s.table(dest)
+	if err != nil {
+		return err
+	}
+
+	if row, ok := t.rows[id]; ok {
+		reflect.ValueOf(dest).Elem().Set(clone(t, row))
+	}
+
+	return nil
+}
+
+func (m memory) InsertPayout(ctx context.Context, p *domain.Payout) error {
+	return m.write(ctx, func(w *writer) error {
+		if err := w.create(reflect.ValueOf(p).Elem()); err != nil {
+			return err
+		}
+
+		for i := range p.Lines {
+			p.Lines[i].PayoutID = p.ID
+
+			if err := w.create(reflect.ValueOf(&p.Lines[i]).Elem()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m memory) FindPayoutsByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error) {
+	var payouts []domain.Payout
+
+	err := m.read(ctx, func() error {
+		err := m.s.load(&payouts, func(t *table, row reflect.Value) bool {
+			return t.matches(row, "status", status)
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range payouts {
+			if err := m.take(&payouts[i].Seller, payouts[i].SellerID); err != nil {
+				return err
+			}
+
+			if err := m.take(&payouts[i].Currency, payouts[i].CurrencyID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
+
+func (m memory) UpdatePayoutStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error {
+	uid, err := parseUUID(id)
+	if err != nil {
+		return err
+	}
+
+	n, err := m.updateWhere(ctx, &domain.Payout{}, func(row reflect.Value) bool {
+		p, _ := row.Interface().(domain.Payout)
+
+		return p.ID == uid && p.Status == from
+	}, map[string]interface{}{"status": to})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m memory) ExportPayouts(ctx context.Context, bankFileID string, payoutIDs []string) error {
+	fileID, err := parseUUID(bankFileID)
+	if err != nil {
+		return err
+	}
+
+	ids, err := parseUUIDs(payoutIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	n, err := m.updateWhere(ctx, &domain.Payout{}, func(row reflect.Value) bool {
+		p, _ := row.Interface().(domain.Payout)
+
+		return containsUUID(ids, p.ID) && p.Status == domain.PayoutStatusApproved
+	}, map[string]interface{}{
+		"status":       domain.PayoutStatusExported,
+		"bank_file_id": &fileID,
+		"exported_at":  &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if n != len(payoutIDs) {
+		return ErrPayoutsChanged
+	}
+
+	return nil
+}
+
+func (m memory) SettlePayouts(ctx context.Context, payoutIDs []string, settledAt time.Time) error {
+	if len(payoutIDs) == 0 {
+		return nil
+	}
+
+	ids, err := parseUUIDs(payoutIDs)
+	if err != nil {
+		return err
+	}
+
+	n, err := m.updateWhere(ctx, &domain.Payout{}, func(row reflect.Value) bool {
+		p, _ := row.Interface().(domain.Payout)
+
+		return containsUUID(ids, p.ID) && p.Status == domain.PayoutStatusExported
+	}, map[string]interface{}{"status": domain.PayoutStatusSettled, "settled_at": &settledAt})
+	if err != nil {
+		return err
+	}
+
+	if n != len(payoutIDs) {
+		return ErrPayoutsChanged
+	}
+
+	return nil
+}
+
+func (m memory) InsertBankFile(ctx context.Context, f *domain.BankFile) error {
+	return m.insert(ctx, f)
+}
+
+func (m memory) FindBankFileByID(ctx context.Context, id string) (domain.BankFile, error) {
+	var f domain.BankFile
+
+	if err := m.findByID(ctx, &f, id); err != nil {
+		return domain.BankFile{}, err
+	}
+
+	return f, nil
+}
+
+func (m memory) InsertReconciliation(ctx context.Context, r *domain.Reconciliation) error {
+	return m.write(ctx, func(w *writer) error {
+		if err := w.create(reflect.ValueOf(r).Elem()); err != nil {
+			return err
+		}
+
+		for i := range r.Exceptions {
+			r.Exceptions[i].ReconciliationID = r.ID
+
+			if err := w.create(reflect.ValueOf(&r.Exceptions[i]).Elem()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m memory) FindReconciliationByID(ctx context.Context, id string) (domain.Reconciliation, error) {
+	var r domain.Reconciliation
+
+	if err := m.findByID(ctx, &r, id); err != nil {
+		return domain.Reconciliation{}, err
+	}
+
+	err := m.read(ctx, func() error {
+		return m.s.load(&r.Exceptions, func(t *table, row reflect.Value) bool {
+			return t.matches(row, "reconciliation_id", r.ID)
+		})
+	})
+	if err != nil {
+		return domain.Reconciliation{}, err
+	}
+
+	return r, nil
+}
+
+func (m memory) InsertSeller(ctx context.Context, s *domain.Seller) error {
+	return m.insert(ctx, s)
+}
+
+func (m memory) FindSellerByID(ctx context.Context, id string) (domain.Seller, error) {
+	var s domain.Seller
+
+	if err := m.findByID(ctx, &s, id); err != nil {
+		return domain.Seller{}, err
+	}
+
+	return s, nil
+}
+
+func (m memory) InsertItems(ctx context.Context, items []domain.Item) error {
+	return m.insert(ctx, items)
+}
+
+func (m memory) FindCurrencies(ctx context.Context) ([]domain.Currency, error) {
+	var currencies []domain.Currency
+
+	if err := m.findAllWhere(ctx, &currencies, nil); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+
+	return currencies, nil
+}
+
+func (m memory) FindItems(ctx context.Context, f ItemsFilter) (ItemsPage, error) {
+	var page ItemsPage
+
+	err := m.read(ctx, func() error {
+		var items []domain.Item
+		if err := m.s.load(&items, nil); err != nil {
+			return err
+		}
+
+		var keys []Cursor
+
+		matching := items[:0]
+
+		for _, it := range items {
+			if f.matches(it) {
+				matching = append(matching, it)
+				keys = append(keys, Cursor{CreatedAt: it.CreatedAt, ID: it.ID})
+			}
+		}
+
+		page.Total = int64(len(matching))
+
+		indexes, next := pageOf(keys, f.After, f.Desc, f.Limit)
+		page.Next = next
+
+		for _, i := range indexes {
+			it := matching[i]
+			if err := m.preloadPayoutItem(&it); err != nil {
+				return err
+			}
+
+			page.Items = append(page.Items, it)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ItemsPage{}, err
+	}
+
+	return page, nil
+}
+
+// matches applies the filter conditions, pagination excluded.
+func (f ItemsFilter) matches(it domain.Item) bool {
+	return (f.SellerID == "" || it.SellerID.String() == f.SellerID) &&
+		(f.CurrencyCode == "" || it.CurrencyCode == f.CurrencyCode) &&
+		(f.PaidOut == nil || it.PaidOut == *f.PaidOut) &&
+		inRange(it.CreatedAt, f.From, f.To)
+}
+
+func (m memory) FindItemByID(ctx context.Context, id string) (domain.Item, error) {
+	var it domain.Item
+
+	if err := m.findByID(ctx, &it, id); err != nil {
+		return domain.Item{}, err
+	}
+
+	err := m.read(ctx, func() error {
+		return m.preloadPayoutItem(&it)
+	})
+	if err != nil {
+		return domain.Item{}, err
+	}
+
+	return it, nil
+}
+
+// preloadPayoutItem loads the payout line of an item, if any.
+func (m memory) preloadPayoutItem(it *domain.Item) error {
+	var lines []domain.PayoutItem
+
+	err := m.s.load(&lines, func(t *table, row reflect.Value) bool {
+		return t.matches(row, "item_id", it.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	it.PayoutItem = nil
+	if len(lines) > 0 {
+		it.PayoutItem = &lines[0]
+	}
+
+	return nil
+}
+
+func (m memory) FindUnpaidOutItemsBySellerID(ctx context.Context, id string) ([]domain.Item, error) {
+	if _, err := parseUUID(id); err != nil {
+		return nil, err
+	}
+
+	return m.unpaidOutItems(ctx, Conditions{"seller_id": id, "paid_out": false})
+}
+
+func (m memory) FindUnpaidOutItems(ctx context.Context) ([]domain.Item, error) {
+	return m.unpaidOutItems(ctx, Conditions{"paid_out": false})
+}
+
+// unpaidOutItems finds the items matching the conditions along with their seller.
+func (m memory) unpaidOutItems(ctx context.Context, where Conditions) ([]domain.Item, error) {
+	var items []domain.Item
+
+	if err := m.findAllWhere(ctx, &items, where); err != nil {
+		return nil, err
+	}
+
+	err := m.read(ctx, func() error {
+		for i := range items {
+			if err := m.take(&items[i].Seller, items[i].SellerID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (m memory) FindUnpaidOutItemsPage(ctx context.Context, sellerID string, after *Cursor, limit int) (ItemsPage, error) {
+	var page ItemsPage
+
+	err := m.read(ctx, func() error {
+		uid, err := parseUUID(sellerID)
+		if err != nil {
+			return err
+		}
+
+		var items []domain.Item
+
+		err = m.s.load(&items, func(t *table, row reflect.Value) bool {
+			it, _ := row.Interface().(domain.Item)
+
+			return it.SellerID == uid && !it.PaidOut
+		})
+		if err != nil {
+			return err
+		}
+
+		keys := make([]Cursor, len(items))
+		for i, it := range items {
+			keys[i] = Cursor{CreatedAt: it.CreatedAt, ID: it.ID}
+		}
+
+		indexes, next := pageOf(keys, after, false, limit)
+		page.Next = next
+
+		for _, i := range indexes {
+			page.Items = append(page.Items, items[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ItemsPage{}, err
+	}
+
+	return page, nil
+}
+
+func (m memory) InsertItemImport(ctx context.Context, i *domain.ItemImport) error {
+	return m.insert(ctx, i)
+}
+
+func (m memory) InsertItemImportErrors(ctx context.Context, errs []domain.ItemImportError) error {
+	return m.insert(ctx, errs)
+}
+
+func (m memory) FindItemImportByID(ctx context.Context, id string) (domain.ItemImport, error) {
+	var i domain.ItemImport
+
+	if err := m.findByID(ctx, &i, id); err != nil {
+		return domain.ItemImport{}, err
+	}
+
+	return i, nil
+}
+
+func (m memory) FinishItemImport(ctx context.Context, i domain.ItemImport) error {
+	n, err := m.updateWhere(ctx, &domain.ItemImport{}, byID(i.ID), map[string]interface{}{
+		"status":        i.Status,
+		"total_rows":    i.TotalRows,
+		"imported_rows": i.ImportedRows,
+		"failed_rows":   i.FailedRows,
+		"error":         i.Error,
+		"finished_at":   i.FinishedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UpdateCurrencyRates updates the rates as a single statement, none is saved when a code is unknown.
+func (m memory) UpdateCurrencyRates(ctx context.Context, currencies []domain.Currency) error {
+	return m.write(ctx, func(w *writer) error {
+		t, err := m.s.table(&domain.Currency{})
+		if err != nil {
+			return err
+		}
+
+		for _, c := range currencies {
+			code := c.Code
+
+			n, err := w.update(t, func(row reflect.Value) bool {
+				return row.FieldByName("Code").Interface() == code
+			}, map[string]interface{}{"usd_exch_rate": c.USDExchRate})
+			if err != nil {
+				return err
+			}
+
+			if n == 0 {
+				return ErrRecordNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m memory) FindItemImportErrors(ctx context.Context, importID string, afterRow, limit int) ([]domain.ItemImportError, error) {
+	var errs []domain.ItemImportError
+
+	err := m.read(ctx, func() error {
+		uid, err := parseUUID(importID)
+		if err != nil {
+			return err
+		}
+
+		return m.s.load(&errs, func(t *table, row reflect.Value) bool {
+			e, _ := row.Interface().(domain.ItemImportError)
+
+			return e.ItemImportID == uid && e.RowNumber > afterRow
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].RowNumber < errs[j].RowNumber })
+
+	if limit > 0 && len(errs) > limit {
+		errs = errs[:limit]
+	}
+
+	return errs, nil
+}
+
+func (m memory) FindSellersWithUnpaidOutItems(ctx context.Context, f SellersFilter) (SellersPage, error) {
+	var page SellersPage
+
+	err := m.read(ctx, func() error {
+		ids, err := parseUUIDs(f.IDs)
+		if err != nil {
+			return err
+		}
+
+		var items []domain.Item
+
+		err = m.s.load(&items, func(t *table, row reflect.Value) bool {
+			return t.matches(row, "paid_out", false)
+		})
+		if err != nil {
+			return err
+		}
+
+		unpaid := make(map[uuid.UUID]bool, len(items))
+		for _, it := range items {
+			unpaid[it.SellerID] = true
+		}
+
+		var sellers []domain.Seller
+
+		err = m.s.load(&sellers, func(t *table, row reflect.Value) bool {
+			id := t.id(row)
+
+			return unpaid[id] && (len(ids) == 0 || containsUUID(ids, id))
+		})
+		if err != nil {
+			return err
+		}
+
+		keys := make([]Cursor, len(sellers))
+		for i, s := range sellers {
+			keys[i] = Cursor{CreatedAt: s.CreatedAt, ID: s.ID}
+		}
+
+		indexes, next := pageOf(keys, f.After, false, f.Limit)
+		page.Next = next
+
+		for _, i := range indexes {
+			page.Sellers = append(page.Sellers, sellers[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return SellersPage{}, err
+	}
+
+	return page, nil
+}
+
+// LockUnpaidItems locks the items for the rest of the transaction, the items locked by another transaction
+// are skipped. Outside of a transaction the locks are released right away.
+func (m memory) LockUnpaidItems(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	uids, err := parseUUIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	var locked []domain.Item
+
+	err = m.read(ctx, func() error {
+		err := m.s.load(&locked, func(t *table, row reflect.Value) bool {
+			it, _ := row.Interface().(domain.Item)
+			owner, ok := m.s.rowLocks[it.ID]
+
+			return containsUUID(uids, it.ID) && !it.PaidOut && (!ok || owner == m.tx)
+		})
+		if err != nil {
+			return err
+		}
+
+		if m.tx == nil {
+			return nil
+		}
+
+		for _, it := range locked {
+			m.s.rowLocks[it.ID] = m.tx
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(locked) != len(ids) {
+		return ErrItemsUnavailable
+	}
+
+	return nil
+}
+
+func (m memory) PayOutItems(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	uids, err := parseUUIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	n, err := m.updateWhere(ctx, &domain.Item{}, func(row reflect.Value) bool {
+		it, _ := row.Interface().(domain.Item)
+
+		return containsUUID(uids, it.ID) && !it.PaidOut
+	}, map[string]interface{}{"paid_out": true})
+	if err != nil {
+		return err
+	}
+
+	if n != len(ids) {
+		return ErrItemsPaidOut
+	}
+
+	return nil
+}
+
+func (m memory) InsertWebhookSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	return m.insert(ctx, s)
+}
+
+func (m memory) FindActiveWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	var subs []domain.WebhookSubscription
+
+	if err := m.findAllWhere(ctx, &subs, map[string]interface{}{"active": true}); err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+func (m memory) InsertWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	return m.insert(ctx, deliveries)
+}
+
+func (m memory) InsertWebhookDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error {
+	return m.insert(ctx, l)
+}
+
+func (m memory) FindWebhookDeadLetterByID(ctx context.Context, id string) (domain.WebhookDeadLetter, error) {
+	var l domain.WebhookDeadLetter
+
+	if err := m.findByID(ctx, &l, id); err != nil {
+		return domain.WebhookDeadLetter{}, err
+	}
+
+	return l, nil
+}
+
+func (m memory) FindWebhookDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error) {
+	letters := []domain.WebhookDeadLetter{}
+
+	if err := m.findAllWhere(ctx, &letters, map[string]interface{}{"replayed_at": nil}); err != nil {
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+func (m memory) FindDueWebhookDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+
+	err := m.read(ctx, func() error {
+		err := m.s.load(&deliveries, func(t *table, row reflect.Value) bool {
+			d, _ := row.Interface().(domain.WebhookDelivery)
+
+			return d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(at)
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range deliveries {
+			if err := m.take(&deliveries[i].Subscription, deliveries[i].SubscriptionID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		a, b := deliveries[i], deliveries[j]
+
+		return (Cursor{CreatedAt: a.NextAttemptAt, ID: a.ID}).before(Cursor{CreatedAt: b.NextAttemptAt, ID: b.ID})
+	})
+
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (m memory) UpdateWebhookDelivery(ctx context.Context, w domain.WebhookDelivery) error {
+	n, err := m.updateWhere(ctx, &domain.WebhookDelivery{}, byID(w.ID), map[string]interface{}{
+		"status":          w.Status,
+		"attempts":        w.Attempts,
+		"next_attempt_at": w.NextAttemptAt,
+		"last_error":      w.LastError,
+		"delivered_at":    w.DeliveredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m memory) ReplayWebhookDeadLetter(ctx context.Context, id string, at time.Time) error {
+	uid, err := parseUUID(id)
+	if err != nil {
+		return err
+	}
+
+	var deliveryID uuid.UUID
+
+	n, err := m.updateWhere(ctx, &domain.WebhookDeadLetter{}, func(row reflect.Value) bool {
+		l, _ := row.Interface().(domain.WebhookDeadLetter)
+		if l.ID != uid || l.ReplayedAt != nil {
+			return false
+		}
+
+		deliveryID = l.DeliveryID
+
+		return true
+	}, map[string]interface{}{"replayed_at": &at})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	_, err = m.updateWhere(ctx, &domain.WebhookDelivery{}, byID(deliveryID), map[string]interface{}{
+		"status":          domain.WebhookDeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": at,
+	})
+
+	return err
+}
+
+func (m memory) InsertOutboxMessage(ctx context.Context, msg *domain.OutboxMessage) error {
+	return m.insert(ctx, msg)
+}
+
+func (m memory) FindUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	err := m.read(ctx, func() error {
+		return m.s.load(&messages, func(t *table, row reflect.Value) bool {
+			return t.matches(row, "published_at", nil) && t.matches(row, "dead_at", nil)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Seq < messages[j].Seq })
+
+	// only the oldest message of each key, as DISTINCT ON (key) does.
+	heads := messages[:0]
+	seen := make(map[uuid.UUID]struct{})
+
+	for _, msg := range messages {
+		if _, ok := seen[msg.Key]; ok {
+			continue
+		}
+
+		seen[msg.Key] = struct{}{}
+		heads = append(heads, msg)
+	}
+
+	if limit > 0 && len(heads) > limit {
+		heads = heads[:limit]
+	}
+
+	return heads, nil
+}
+
+func (m memory) UpdateOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error {
+	n, err := m.updateWhere(ctx, &domain.OutboxMessage{}, byID(msg.ID), map[string]interface{}{
+		"published_at": msg.PublishedAt,
+		"attempts":     msg.Attempts,
+		"last_error":   msg.LastError,
+		"dead_at":      msg.DeadAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m memory) InsertJobRun(ctx context.Context, r *domain.JobRun) error {
+	return m.insert(ctx, r)
+}
+
+func (m memory) FinishJobRun(ctx context.Context, r domain.JobRun) error {
+	n, err := m.updateWhere(ctx, &domain.JobRun{}, byID(r.ID), map[string]interface{}{
+		"status":            r.Status,
+		"finished_at":       r.FinishedAt,
+		"duration_ms":       r.DurationMS,
+		"error":             r.Error,
+		"sellers_processed": r.SellersProcessed,
+		"sellers_failed":    r.SellersFailed,
+		"payouts_created":   r.PayoutsCreated,
+	})
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	if len(r.Totals) > 0 {
+		totals := make([]domain.JobRunTotal, len(r.Totals))
+		for i, t := range r.Totals {
+			t.JobRunID = r.ID
+			totals[i] = t
+		}
+
+		if err := m.insert(ctx, &totals); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Failures) == 0 {
+		return nil
+	}
+
+	failures := make([]domain.JobRunFailure, len(r.Failures))
+	for i, f := range r.Failures {
+		f.JobRunID = r.ID
+		failures[i] = f
+	}
+
+	return m.insert(ctx, &failures)
+}
+
+func (m memory) FindJobRuns(ctx context.Context, f JobRunsFilter) (JobRunsPage, error) {
+	var page JobRunsPage
+
+	err := m.read(ctx, func() error {
+		var runs []domain.JobRun
+
+		err := m.s.load(&runs, func(t *table, row reflect.Value) bool {
+			r, _ := row.Interface().(domain.JobRun)
+
+			return (f.Job == "" || r.Job == f.Job) && (f.Status == "" || r.Status == f.Status)
+		})
+		if err != nil {
+			return err
+		}
+
+		page.Total = int64(len(runs))
+
+		keys := make([]Cursor, len(runs))
+		for i, r := range runs {
+			keys[i] = Cursor{CreatedAt: r.CreatedAt, ID: r.ID}
+		}
+
+		indexes, next := pageOf(keys, f.After, f.Desc, f.Limit)
+		page.Next = next
+
+		for _, i := range indexes {
+			r := runs[i]
+			if err := m.preloadJobRun(&r); err != nil {
+				return err
+			}
+
+			page.Runs = append(page.Runs, r)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return JobRunsPage{}, err
+	}
+
+	return page, nil
+}
+
+func (m memory) FindJobRunByID(ctx context.Context, id string) (domain.JobRun, error) {
+	var r domain.JobRun
+
+	if err := m.findByID(ctx, &r, id); err != nil {
+		return domain.JobRun{}, err
+	}
+
+	err := m.read(ctx, func() error {
+		return m.preloadJobRun(&r)
+	})
+	if err != nil {
+		return domain.JobRun{}, err
+	}
+
+	return r, nil
+}
+
+// preloadJobRun loads the totals of a run ordered by currency, and its failures.
+func (m memory) preloadJobRun(r *domain.JobRun) error {
+	if err := m.s.load(&r.Totals, func(t *table, row reflect.Value) bool {
+		return t.matches(row, "job_run_id", r.ID)
+	}); err != nil {
+		return err
+	}
+
+	sort.SliceStable(r.Totals, func(i, j int) bool { return r.Totals[i].CurrencyCode < r.Totals[j].CurrencyCode })
+
+	return m.s.load(&r.Failures, func(t *table, row reflect.Value) bool {
+		return t.matches(row, "job_run_id", r.ID)
+	})
+}
+
+// byID matches the row of an id.
+func byID(id uuid.UUID) func(row reflect.Value) bool {
+	return func(row reflect.Value) bool {
+		return row.FieldByName("ID").Interface() == id
+	}
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
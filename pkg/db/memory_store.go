@@ -0,0 +1,591 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	errUnsupportedDest = errors.New("unsupported destination")
+	errInvalidUUID     = errors.New("invalid input syntax for type uuid")
+	errViolation       = errors.New("row violates constraint")
+)
+
+// store holds the tables of the memory DB, the rows are mapped by the gorm schemas of the domain models.
+type store struct {
+	mu      sync.Mutex
+	schemas *sync.Map
+	tables  map[string]*table
+	// seq is the last value of the outbox sequence.
+	seq int64
+	// locks are the locks held by TryLock, rowLocks the rows locked by the transactions.
+	locks    map[string]bool
+	rowLocks map[uuid.UUID]*memTx
+}
+
+func newStore() *store {
+	return &store{
+		schemas:  &sync.Map{},
+		tables:   make(map[string]*table),
+		locks:    make(map[string]bool),
+		rowLocks: make(map[uuid.UUID]*memTx),
+	}
+}
+
+// table is a table of the memory DB, its rows are stored without their associations.
+type table struct {
+	schema *schema.Schema
+	rows   map[uuid.UUID]reflect.Value
+}
+
+// table returns the table of a model, a pointer to a struct or to a slice of structs.
+func (s *store) table(model interface{}) (*table, error) {
+	sch, err := schema.Parse(model, s.schemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := s.tables[sch.Table]
+	if !ok {
+		t = &table{schema: sch, rows: make(map[uuid.UUID]reflect.Value)}
+		s.tables[sch.Table] = t
+	}
+
+	return t, nil
+}
+
+func (s *store) tableOf(typ reflect.Type) (*table, error) {
+	return s.table(reflect.New(typ).Interface())
+}
+
+// load fills dest, a pointer to a slice of structs, with copies of the rows matching filter,
+// ordered by (created_at, id).
+func (s *store) load(dest interface{}, filter func(t *table, row reflect.Value) bool) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: %T", errUnsupportedDest, dest)
+	}
+
+	sliceType := rv.Elem().Type()
+	elemType := sliceType.Elem()
+
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	t, err := s.tableOf(elemType)
+	if err != nil {
+		return err
+	}
+
+	out := reflect.MakeSlice(sliceType, 0, len(t.rows))
+
+	for _, row := range t.sorted() {
+		if filter != nil && !filter(t, row) {
+			continue
+		}
+
+		c := clone(t, row)
+		if isPtr {
+			c = c.Addr()
+		}
+
+		out = reflect.Append(out, c)
+	}
+
+	rv.Elem().Set(out)
+
+	return nil
+}
+
+func (s *store) unlockRows(tx *memTx) {
+	for id, owner := range s.rowLocks {
+		if owner == tx {
+			delete(s.rowLocks, id)
+		}
+	}
+}
+
+// sorted returns the rows ordered by (created_at, id).
+func (t *table) sorted() []reflect.Value {
+	rows := make([]reflect.Value, 0, len(t.rows))
+	for _, row := range t.rows {
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return t.key(rows[i]).before(t.key(rows[j]))
+	})
+
+	return rows
+}
+
+// key returns the keyset position of a row.
+func (t *table) key(row reflect.Value) Cursor {
+	var c Cursor
+
+	if f := t.schema.LookUpField("created_at"); f != nil {
+		c.CreatedAt, _ = f.ReflectValueOf(row).Interface().(time.Time)
+	}
+
+	c.ID, _ = t.schema.PrioritizedPrimaryField.ReflectValueOf(row).Interface().(uuid.UUID)
+
+	return c
+}
+
+func (t *table) id(row reflect.Value) uuid.UUID {
+	id, _ := t.schema.PrioritizedPrimaryField.ReflectValueOf(row).Interface().(uuid.UUID)
+
+	return id
+}
+
+// value returns the value of a column of a row, nil for NULL.
+func (t *table) value(row reflect.Value, column string) interface{} {
+	f := t.schema.LookUpField(column)
+	if f == nil {
+		return nil
+	}
+
+	v := f.ReflectValueOf(row)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if d, ok := v.Interface().(decimal.NullDecimal); ok {
+		if !d.Valid {
+			return nil
+		}
+
+		return d.Decimal
+	}
+
+	return v.Interface()
+}
+
+// matches tells whether a column equals a condition, a nil condition matches NULL and a slice any of its values.
+func (t *table) matches(row reflect.Value, column string, cond interface{}) bool {
+	v := t.value(row, column)
+	if cond == nil {
+		return v == nil
+	}
+
+	if cv := reflect.ValueOf(cond); cv.Kind() == reflect.Slice && cv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < cv.Len(); i++ {
+			if equal(v, cv.Index(i).Interface()) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return equal(v, cond)
+}
+
+// equal compares two column values by their text representation, as postgres casts the parameters.
+func equal(v, cond interface{}) bool {
+	if v == nil || cond == nil {
+		return false
+	}
+
+	if d, ok := v.(decimal.Decimal); ok {
+		if c, err := decimal.NewFromString(fmt.Sprint(cond)); err == nil {
+			return d.Equal(c)
+		}
+	}
+
+	return fmt.Sprint(v) == fmt.Sprint(cond)
+}
+
+func (c Cursor) before(o Cursor) bool {
+	if !c.CreatedAt.Equal(o.CreatedAt) {
+		return c.CreatedAt.Before(o.CreatedAt)
+	}
+
+	return bytes.Compare(c.ID.Bytes(), o.ID.Bytes()) < 0
+}
+
+// owns tells whether a relationship is a field of the table model, gorm also lists the relationships
+// of the models referring to it.
+func (t *table) owns(rel *schema.Relationship) bool {
+	return rel.Field.Schema == t.schema
+}
+
+// clone copies a row, without its associations and with its times rounded to the microsecond
+// as stored by postgres.
+func clone(t *table, rv reflect.Value) reflect.Value {
+	row := reflect.New(rv.Type()).Elem()
+	row.Set(rv)
+
+	for _, rel := range t.schema.Relationships.Relations {
+		if !t.owns(rel) {
+			continue
+		}
+
+		f := rel.Field.ReflectValueOf(row)
+		f.Set(reflect.Zero(f.Type()))
+	}
+
+	for _, f := range t.schema.Fields {
+		if f.DBName == "" {
+			continue
+		}
+
+		fv := f.ReflectValueOf(row)
+
+		switch v := fv.Interface().(type) {
+		case time.Time:
+			fv.Set(reflect.ValueOf(v.Round(time.Microsecond)))
+		case *time.Time:
+			if v != nil {
+				r := v.Round(time.Microsecond)
+				fv.Set(reflect.ValueOf(&r))
+			}
+		case *uuid.UUID:
+			if v != nil {
+				id := *v
+				fv.Set(reflect.ValueOf(&id))
+			}
+		case []byte:
+			if v != nil {
+				fv.Set(reflect.ValueOf(append([]byte{}, v...)))
+			}
+		}
+	}
+
+	return row
+}
+
+// writer applies the writes of a statement, recording how to undo them.
+type writer struct {
+	s    *store
+	undo []func()
+}
+
+func (w *writer) rollback() {
+	for i := len(w.undo) - 1; i >= 0; i-- {
+		w.undo[i]()
+	}
+
+	w.undo = nil
+}
+
+// put stores a row once the constraints are checked.
+func (w *writer) put(t *table, row reflect.Value) error {
+	if err := w.s.check(t, row); err != nil {
+		return err
+	}
+
+	id := t.id(row)
+	prev, existed := t.rows[id]
+	t.rows[id] = row
+
+	w.undo = append(w.undo, func() {
+		if existed {
+			t.rows[id] = prev
+		} else {
+			delete(t.rows, id)
+		}
+	})
+
+	return nil
+}
+
+// each calls fn on the struct dest points to, or on each struct of the slice dest is or points to.
+func (w *writer) each(dest interface{}, fn func(rv reflect.Value) error) error {
+	rv := reflect.Indirect(reflect.ValueOf(dest))
+
+	switch {
+	case rv.Kind() == reflect.Struct && rv.CanAddr():
+		return fn(rv)
+	case rv.Kind() == reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			if err := fn(reflect.Indirect(rv.Index(i))); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedDest, dest)
+	}
+}
+
+// create inserts a row as gorm Create does with the associations omitted:
+// the primary key, the timestamps and the defaults are set on rv.
+func (w *writer) create(rv reflect.Value) error {
+	t, err := w.s.tableOf(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, f := range t.schema.Fields {
+		if f.DBName == "" {
+			continue
+		}
+
+		fv := f.ReflectValueOf(rv)
+
+		switch {
+		case f.PrimaryKey && fv.IsZero():
+			err = f.Set(rv, uuid.Must(uuid.NewV4()))
+		case f.AutoIncrement:
+			w.s.seq++
+			err = f.Set(rv, w.s.seq)
+		case (f.AutoCreateTime > 0 || f.AutoUpdateTime > 0) && fv.IsZero():
+			err = f.Set(rv, now)
+		case f.DefaultValueInterface != nil && fv.IsZero():
+			err = f.Set(rv, f.DefaultValueInterface)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := t.rows[t.id(rv)]; ok {
+		return &ConstraintError{
+			Kind:       ErrUniqueViolation,
+			Table:      t.schema.Table,
+			Constraint: t.schema.Table + "_pkey",
+			Err:        fmt.Errorf("%w %s", errViolation, t.schema.Table+"_pkey"),
+		}
+	}
+
+	return w.put(t, clone(t, rv))
+}
+
+// update sets columns of the rows of a table matching filter and returns the number of rows updated,
+// as an UPDATE statement does. The updated_at column is set as well.
+func (w *writer) update(t *table, filter func(row reflect.Value) bool, columns map[string]interface{}) (int, error) {
+	n := 0
+	now := time.Now()
+
+	for _, stored := range t.sorted() {
+		if !filter(stored) {
+			continue
+		}
+
+		row := clone(t, stored)
+
+		if f := t.schema.LookUpField("updated_at"); f != nil {
+			if err := f.Set(row, now); err != nil {
+				return n, err
+			}
+		}
+
+		for column, v := range columns {
+			if err := setColumn(t.schema.LookUpField(column), row, v); err != nil {
+				return n, err
+			}
+		}
+
+		if err := w.put(t, clone(t, row)); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// setColumn sets a column of a row, nil sets NULL.
+func setColumn(f *schema.Field, row reflect.Value, v interface{}) error {
+	fv := f.ReflectValueOf(row)
+	if v == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch {
+	case rv.Type().AssignableTo(fv.Type()):
+		fv.Set(rv)
+	case rv.Type().ConvertibleTo(fv.Type()):
+		fv.Set(rv.Convert(fv.Type()))
+	default:
+		return f.Set(row, v)
+	}
+
+	return nil
+}
+
+// foreignKey is a foreign key constraint of the migrations.
+type foreignKey struct {
+	name      string
+	column    string
+	refTable  string
+	refColumn string
+}
+
+// check is a check constraint of the migrations, ok tells whether a row satisfies it.
+type check struct {
+	name string
+	ok   func(t *table, row reflect.Value) bool
+}
+
+// The unique, foreign key and check constraints of the migrations, by table.
+var (
+	memoryUniques = map[string][]string{
+		"payout_items": {"item_id"},
+		"currencies":   {"code"},
+		"bank_files":   {"message_id"},
+		"outbox":       {"seq"},
+	}
+	memoryForeignKeys = map[string][]foreignKey{
+		"sellers": {
+			{name: "sellers_currency_code_fkey", column: "currency_code", refTable: "currencies", refColumn: "code"},
+		},
+		"items": {
+			{name: "items_seller_id_fkey", column: "seller_id", refTable: "sellers", refColumn: "id"},
+			{name: "items_currency_code_fkey", column: "currency_code", refTable: "currencies", refColumn: "code"},
+		},
+		"payouts": {
+			{name: "payouts_currency_id_fkey", column: "currency_id", refTable: "currencies", refColumn: "id"},
+			{name: "payouts_seller_id_fkey", column: "seller_id", refTable: "sellers", refColumn: "id"},
+			{name: "payouts_bank_file_id_fkey", column: "bank_file_id", refTable: "bank_files", refColumn: "id"},
+		},
+		"payout_items": {
+			{name: "payout_items_payout_id_fkey", column: "payout_id", refTable: "payouts", refColumn: "id"},
+			{name: "payout_items_item_id_fkey", column: "item_id", refTable: "items", refColumn: "id"},
+		},
+		"item_import_errors": {
+			{name: "item_import_errors_item_import_id_fkey", column: "item_import_id", refTable: "item_imports", refColumn: "id"},
+		},
+		"reconciliation_exceptions": {
+			{name: "reconciliation_exceptions_payout_id_fkey", column: "payout_id", refTable: "payouts", refColumn: "id"},
+			{name: "reconciliation_exceptions_reconciliation_id_fkey", column: "reconciliation_id",
+				refTable: "reconciliations", refColumn: "id"},
+		},
+		"webhook_deliveries": {
+			{name: "webhook_deliveries_subscription_id_fkey", column: "subscription_id",
+				refTable: "webhook_subscriptions", refColumn: "id"},
+		},
+		"webhook_dead_letters": {
+			{name: "webhook_dead_letters_delivery_id_fkey", column: "delivery_id", refTable: "webhook_deliveries", refColumn: "id"},
+		},
+		"job_run_totals": {
+			{name: "job_run_totals_job_run_id_fkey", column: "job_run_id", refTable: "job_runs", refColumn: "id"},
+		},
+		"job_run_failures": {
+			{name: "job_run_failures_job_run_id_fkey", column: "job_run_id", refTable: "job_runs", refColumn: "id"},
+		},
+	}
+	memoryChecks = map[string][]check{
+		"currencies": {
+			{name: "currencies_code_check", ok: func(t *table, row reflect.Value) bool {
+				code, _ := t.value(row, "code").(string)
+
+				return len(code) == 3 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z' &&
+					code[2] >= 'A' && code[2] <= 'Z'
+			}},
+			{name: "currencies_usd_exch_rate_check", ok: positive("usd_exch_rate")},
+		},
+		"items": {
+			{name: "items_price_amount_check", ok: positive("price_amount")},
+		},
+		"payouts": {
+			{name: "payouts_price_total_check", ok: notNegative("price_total")},
+		},
+		"payout_items": {
+			{name: "payout_items_converted_amount_check", ok: notNegative("converted_amount")},
+			{name: "payout_items_exchange_rate_check", ok: positive("exchange_rate")},
+		},
+	}
+)
+
+// positive checks that a numeric column is NULL or above zero.
+func positive(column string) func(t *table, row reflect.Value) bool {
+	return func(t *table, row reflect.Value) bool {
+		d, ok := t.value(row, column).(decimal.Decimal)
+
+		return !ok || d.IsPositive()
+	}
+}
+
+// notNegative checks that a numeric column is NULL or not below zero.
+func notNegative(column string) func(t *table, row reflect.Value) bool {
+	return func(t *table, row reflect.Value) bool {
+		d, ok := t.value(row, column).(decimal.Decimal)
+
+		return !ok || !d.IsNegative()
+	}
+}
+
+// check checks the constraints of the migrations on a row about to be stored.
+func (s *store) check(t *table, row reflect.Value) error {
+	name := t.schema.Table
+	id := t.id(row)
+
+	violation := func(kind error, constraint string) error {
+		return &ConstraintError{
+			Kind:       kind,
+			Table:      name,
+			Constraint: constraint,
+			Err:        fmt.Errorf("%w %s", errViolation, constraint),
+		}
+	}
+
+	for _, c := range memoryChecks[name] {
+		if !c.ok(t, row) {
+			return violation(ErrCheckViolation, c.name)
+		}
+	}
+
+	for _, column := range memoryUniques[name] {
+		v := t.value(row, column)
+		if v == nil {
+			continue
+		}
+
+		for otherID, other := range t.rows {
+			if otherID != id && equal(t.value(other, column), v) {
+				return violation(ErrUniqueViolation, fmt.Sprintf("%s_%s_key", name, column))
+			}
+		}
+	}
+
+	for _, fk := range memoryForeignKeys[name] {
+		v := t.value(row, fk.column)
+		if v == nil {
+			continue
+		}
+
+		found := false
+
+		if ref, ok := s.tables[fk.refTable]; ok {
+			for _, r := range ref.rows {
+				if equal(ref.value(r, fk.refColumn), v) {
+					found = true
+
+					break
+				}
+			}
+		}
+
+		if !found {
+			return violation(ErrForeignKeyViolation, fk.name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+)
+
+// InsertOutboxMessage records a message to publish, its sequence is set on success.
+func (d database) InsertOutboxMessage(ctx context.Context, m *domain.OutboxMessage) error {
+	return d.driver.WithContext(ctx).Create(m).Error
+}
+
+// FindUnpublishedOutboxMessages finds the oldest pending message of each key, neither published nor dead,
+// in the order they were recorded. A key whose oldest message keeps failing thus never holds back the others.
+func (d database) FindUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	heads := d.driver.Model(&domain.OutboxMessage{}).
+		Select("DISTINCT ON (key) *").
+		Where("published_at IS NULL AND dead_at IS NULL").
+		Order("key, seq")
+
+	err := d.driver.WithContext(ctx).
+		Table("(?) AS heads", heads).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// UpdateOutboxMessage saves the outcome of a publishing attempt.
+func (d database) UpdateOutboxMessage(ctx context.Context, m domain.OutboxMessage) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.OutboxMessage{}).
+		Where("id = ?", m.ID).
+		Updates(map[string]interface{}{
+			"published_at": m.PublishedAt,
+			"attempts":     m.Attempts,
+			"last_error":   m.LastError,
+			"dead_at":      m.DeadAt,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -1,44 +1,150 @@
 package db
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Conditions helper used for Storager queries (see gorm.Where).
 type Conditions map[string]interface{}
 
-// FindPayoutsBySellerID finds payouts by seller_id.
-func (d database) FindPayoutsBySellerID(id string) ([]domain.Payout, error) {
-	where := Conditions{"seller_id": id}
+// PayoutsFilter narrows down and paginates a payouts listing.
+type PayoutsFilter struct {
+	// From and To bound the payouts creation date, From is inclusive and To exclusive.
+	// Zero values are ignored.
+	From   time.Time
+	To     time.Time
+	Status domain.PayoutStatus
+	// Desc sorts payouts from the most recent.
+	Desc bool
+	// After is the keyset position from which the page starts, nil for the first page.
+	After *Cursor
+	Limit int
+}
+
+// PayoutsPage is a page of payouts.
+type PayoutsPage struct {
+	Payouts []domain.Payout
+	// Next is the cursor of the following page, nil on the last page.
+	Next *Cursor
+	// Total is the number of payouts matching the filter, regardless of pagination.
+	Total int64
+}
+
+// FindPayoutsBySellerID finds a page of payouts by seller_id.
+func (d database) FindPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (PayoutsPage, error) {
+	where := func() *gorm.DB {
+		return f.where(d.driver.WithContext(ctx).Model(&domain.Payout{}).Where("seller_id = ?", id))
+	}
+
+	var page PayoutsPage
+
+	if err := where().Count(&page.Total).Error; err != nil {
+		return PayoutsPage{}, err
+	}
+
+	if err := preloadPayoutsRelations(keyset(where(), f.After, f.Desc, f.Limit)).Find(&page.Payouts).Error; err != nil {
+		return PayoutsPage{}, err
+	}
+
+	if f.Limit > 0 && len(page.Payouts) > f.Limit {
+		page.Payouts = page.Payouts[:f.Limit]
+		last := page.Payouts[f.Limit-1]
+		page.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// SumPayoutsBySellerID sums the price of the payouts of a seller matching the filter,
+// pagination is ignored.
+func (d database) SumPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (decimal.Decimal, error) {
+	var sum decimal.Decimal
+
+	row := f.where(d.driver.WithContext(ctx).Model(&domain.Payout{}).Where("seller_id = ?", id)).
+		Select("COALESCE(SUM(price_total), 0)").Row()
+	if err := row.Scan(&sum); err != nil {
+		return decimal.Zero, err
+	}
+
+	return sum, nil
+}
 
-	return d.payouts(where)
+// FindPayoutByID finds a payout by id.
+func (d database) FindPayoutByID(ctx context.Context, id string) (domain.Payout, error) {
+	var p domain.Payout
+
+	if err := preloadPayoutsRelations(d.driver.WithContext(ctx)).Take(&p, "id = ?", id).Error; err != nil {
+		return domain.Payout{}, err
+	}
+
+	return p, nil
 }
 
-func (d database) payouts(where Conditions) ([]domain.Payout, error) {
-	var p []domain.Payout
+// InsertPayout inserts a payout and its lines, without upserting the seller, the currency
+// nor the items they refer to. It is meant to run in a transaction.
+func (d database) InsertPayout(ctx context.Context, p *domain.Payout) error {
+	tx := d.driver.WithContext(ctx)
 
-	db, err := d.preloadPayoutsRelations()
-	if err != nil {
-		return nil, fmt.Errorf("failed to preload Items: %w", err)
+	if err := tx.Omit(clause.Associations).Create(p).Error; err != nil {
+		return err
 	}
 
-	err = db.FindAllWhere(&p, where)
-	if err == nil {
-		return p, nil
+	if len(p.Lines) == 0 {
+		return nil
 	}
 
-	if errors.Is(err, ErrRecordNotFound) {
-		return nil, ErrRecordNotFound
+	for i := range p.Lines {
+		p.Lines[i].PayoutID = p.ID
 	}
 
-	return nil, err
+	return tx.Omit(clause.Associations).Create(&p.Lines).Error
+}
+
+func preloadPayoutsRelations(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Currency").Preload("Lines.Item")
 }
 
-func (d database) preloadPayoutsRelations() (DB, error) {
-	tx := d.driver.Preload("Currency").Preload("Items")
+// where applies the filter conditions, pagination excluded.
+func (f PayoutsFilter) where(tx *gorm.DB) *gorm.DB {
+	if !f.From.IsZero() {
+		tx = tx.Where("created_at >= ?", f.From)
+	}
+
+	if !f.To.IsZero() {
+		tx = tx.Where("created_at < ?", f.To)
+	}
+
+	if f.Status != "" {
+		tx = tx.Where("status = ?", f.Status)
+	}
+
+	return tx
+}
+
+// keyset paginates a query ordered by (created_at, id),
+// one extra row is fetched to detect whether a next page exists.
+func keyset(tx *gorm.DB, after *Cursor, desc bool, limit int) *gorm.DB {
+	cmp, order := ">", "created_at ASC, id ASC"
+	if desc {
+		cmp, order = "<", "created_at DESC, id DESC"
+	}
+
+	if after != nil {
+		tx = tx.Where(fmt.Sprintf("(created_at, id) %s (?, ?)", cmp), after.CreatedAt, after.ID)
+	}
+
+	tx = tx.Order(order)
+
+	if limit > 0 {
+		tx = tx.Limit(limit + 1)
+	}
 
-	return &database{driver: tx}, tx.Error
+	return tx
 }
@@ -0,0 +1,42 @@
+package db_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/TestardR/seller-payout/pkg/db/dbtest"
+)
+
+// TestPostgres runs the conformance suite against the postgres DB set by the TEST_PG_* variables,
+// its tables are emptied: make test-pg runs it against the docker-compose one.
+func TestPostgres(t *testing.T) {
+	host := os.Getenv("TEST_PG_HOST")
+	if host == "" {
+		t.Skip("TEST_PG_HOST is not set")
+	}
+
+	d, err := db.New(db.Config{
+		User:     os.Getenv("TEST_PG_USER"),
+		Name:     os.Getenv("TEST_PG_NAME"),
+		Password: os.Getenv("TEST_PG_PASSWORD"),
+		Host:     host,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = d.Close() })
+
+	if err := d.RunMigrations("../../migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	dbtest.Run(t, func(t *testing.T) db.DB {
+		if err := db.Truncate(d); err != nil {
+			t.Fatal(err)
+		}
+
+		return d
+	})
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettlePayouts marks exported payouts as settled,
+// ErrPayoutsChanged is returned when some of them are no longer exported.
+func (d database) SettlePayouts(ctx context.Context, payoutIDs []string, settledAt time.Time) error {
+	if len(payoutIDs) == 0 {
+		return nil
+	}
+
+	tx := d.driver.WithContext(ctx).Model(&domain.Payout{}).
+		Where("id IN ? AND status = ?", payoutIDs, domain.PayoutStatusExported).
+		Updates(map[string]interface{}{"status": domain.PayoutStatusSettled, "settled_at": settledAt})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected != int64(len(payoutIDs)) {
+		return ErrPayoutsChanged
+	}
+
+	return nil
+}
+
+// FindReconciliationByID finds a reconciliation by id along with its exceptions.
+func (d database) FindReconciliationByID(ctx context.Context, id string) (domain.Reconciliation, error) {
+	var r domain.Reconciliation
+
+	err := d.driver.WithContext(ctx).
+		Preload("Exceptions", func(tx *gorm.DB) *gorm.DB { return tx.Order("created_at ASC, id ASC") }).
+		Take(&r, "id = ?", id).Error
+	if err != nil {
+		return domain.Reconciliation{}, err
+	}
+
+	return r, nil
+}
+
+// InsertReconciliation inserts a reconciliation and its exceptions. It is meant to run in a transaction.
+func (d database) InsertReconciliation(ctx context.Context, r *domain.Reconciliation) error {
+	tx := d.driver.WithContext(ctx)
+
+	if err := tx.Omit(clause.Associations).Create(r).Error; err != nil {
+		return err
+	}
+
+	if len(r.Exceptions) == 0 {
+		return nil
+	}
+
+	for i := range r.Exceptions {
+		r.Exceptions[i].ReconciliationID = r.ID
+	}
+
+	return tx.Omit(clause.Associations).Create(&r.Exceptions).Error
+}
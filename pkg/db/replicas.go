@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// replicaCheckInterval is how long the replication lag measured on a replica is trusted,
+	// a failing replica is not read from either for as long.
+	replicaCheckInterval = 5 * time.Second
+	// replicaCheckTimeout bounds the measure of the replication lag.
+	replicaCheckTimeout = 2 * time.Second
+)
+
+var (
+	errReplicaFailed       = errors.New("replica failed to answer a query")
+	errReplicaDisconnected = errors.New("replica not streaming from its primary")
+)
+
+// replicationLagQuery returns in seconds how far behind its primary the database replays,
+// zero when it is not a replica or when it replayed all it received:
+// the last replay timestamp grows old when the primary has nothing to write.
+// It returns -1 when no WAL receiver is streaming, the replica then receives nothing and
+// would otherwise always look up to date. The status of the receiver is only visible to
+// the members of pg_read_all_stats, a running receiver is deemed streaming otherwise.
+const replicationLagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN NOT EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE COALESCE(status, 'streaming') = 'streaming') THEN -1
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// replica is a read replica whose replication lag is measured by lag.
+type replica struct {
+	db  DB
+	lag func(ctx context.Context) (time.Duration, error)
+
+	// probes are the measures of the lag in progress.
+	probes sync.WaitGroup
+
+	mu        sync.Mutex
+	probing   bool
+	err       error
+	measured  time.Duration
+	checkedAt time.Time
+}
+
+// usable tells whether the replica answered and lagged at most maxLag behind the primary when last measured.
+// The lag is measured again in the background once the measure is older than replicaCheckInterval,
+// so that the readers never wait on a replica. A replica is not usable until it is measured.
+func (r *replica) usable(now time.Time, maxLag time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.probing && (r.checkedAt.IsZero() || now.Sub(r.checkedAt) >= replicaCheckInterval) {
+		r.probing = true
+		r.probes.Add(1)
+
+		go r.probe(now)
+	}
+
+	return !r.checkedAt.IsZero() && r.err == nil && r.measured <= maxLag
+}
+
+// probe measures the lag of the replica.
+func (r *replica) probe(now time.Time) {
+	defer r.probes.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), replicaCheckTimeout)
+	defer cancel()
+
+	lag, err := r.lag(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.measured, r.err, r.checkedAt, r.probing = lag, err, now, false
+}
+
+// fail keeps the replica aside until its next check.
+func (r *replica) fail(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err, r.checkedAt = errReplicaFailed, now
+}
+
+// routed sends the read-only finders of the listings and the statements to the replicas,
+// the writes, the transactions and the other finders go to the primary it embeds.
+// A replica lagging more than maxLag or failing is skipped, the primary is read when none is left.
+type routed struct {
+	DB
+	replicas []*replica
+	maxLag   time.Duration
+	next     *uint32
+	now      func() time.Time
+}
+
+func newRouted(primary DB, replicas []*replica, maxLag time.Duration) routed {
+	return routed{DB: primary, replicas: replicas, maxLag: maxLag, next: new(uint32), now: time.Now}
+}
+
+// replica returns the next usable replica, in turn, nil when there is none.
+func (r routed) replica() *replica {
+	start := atomic.AddUint32(r.next, 1)
+
+	for i := range r.replicas {
+		rep := r.replicas[(int(start)+i)%len(r.replicas)]
+		if rep.usable(r.now(), r.maxLag) {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+// read runs fn on a replica, then on the primary when no replica is usable or the replica failed.
+func (r routed) read(ctx context.Context, fn func(d DB) error) error {
+	rep := r.replica()
+	if rep == nil {
+		return fn(r.DB)
+	}
+
+	err := fn(rep.db)
+	if err == nil || errors.Is(err, ErrRecordNotFound) || ctx.Err() != nil {
+		return err
+	}
+
+	rep.fail(r.now())
+
+	return fn(r.DB)
+}
+
+func (r routed) FindPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (PayoutsPage, error) {
+	var page PayoutsPage
+
+	err := r.read(ctx, func(d DB) (err error) {
+		page, err = d.FindPayoutsBySellerID(ctx, id, f)
+
+		return err
+	})
+
+	return page, err
+}
+
+func (r routed) SumPayoutsBySellerID(ctx context.Context, id string, f PayoutsFilter) (decimal.Decimal, error) {
+	var sum decimal.Decimal
+
+	err := r.read(ctx, func(d DB) (err error) {
+		sum, err = d.SumPayoutsBySellerID(ctx, id, f)
+
+		return err
+	})
+
+	return sum, err
+}
+
+func (r routed) FindItems(ctx context.Context, f ItemsFilter) (ItemsPage, error) {
+	var page ItemsPage
+
+	err := r.read(ctx, func(d DB) (err error) {
+		page, err = d.FindItems(ctx, f)
+
+		return err
+	})
+
+	return page, err
+}
+
+func (r routed) FindJobRuns(ctx context.Context, f JobRunsFilter) (JobRunsPage, error) {
+	var page JobRunsPage
+
+	err := r.read(ctx, func(d DB) (err error) {
+		page, err = d.FindJobRuns(ctx, f)
+
+		return err
+	})
+
+	return page, err
+}
+
+// Close closes the connections pools of the primary and of the replicas, once their lag is measured.
+func (r routed) Close() error {
+	err := r.DB.Close()
+
+	for _, rep := range r.replicas {
+		rep.probes.Wait()
+
+		if rerr := rep.db.Close(); err == nil {
+			err = rerr
+		}
+	}
+
+	return err
+}
+
+// replicationLag measures how far behind its primary d replays, see replicationLagQuery.
+func (d database) replicationLag(ctx context.Context) (time.Duration, error) {
+	var seconds float64
+
+	if err := d.driver.WithContext(ctx).Raw(replicationLagQuery).Scan(&seconds).Error; err != nil {
+		return 0, err
+	}
+
+	return lagFromSeconds(seconds)
+}
+
+// lagFromSeconds converts the result of replicationLagQuery, errReplicaDisconnected is returned when it is negative.
+func lagFromSeconds(seconds float64) (time.Duration, error) {
+	if seconds < 0 {
+		return 0, errReplicaDisconnected
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingDB fails the listing of the items.
+type failingDB struct {
+	DB
+}
+
+func (failingDB) FindItems(context.Context, ItemsFilter) (ItemsPage, error) {
+	return ItemsPage{}, errors.New("mock")
+}
+
+// replicaOf returns a replica whose name tells it apart from the others, lagging by lag.
+func replicaOf(t *testing.T, d DB, name string, lag *time.Duration, lagErr error) *replica {
+	t.Helper()
+
+	job := domain.JobRun{Job: name, Trigger: domain.JobRunTriggerSchedule, Status: domain.JobRunRunning}
+	require.NoError(t, d.InsertJobRun(context.Background(), &job))
+
+	return &replica{db: d, lag: func(context.Context) (time.Duration, error) { return *lag, lagErr }}
+}
+
+// measure measures the lag of the replicas of r and waits for it.
+func measure(r routed) {
+	for _, rep := range r.replicas {
+		rep.usable(r.now(), r.maxLag)
+		rep.probes.Wait()
+	}
+}
+
+// readFrom returns the name of the database the job runs are listed from.
+func readFrom(t *testing.T, r routed) string {
+	t.Helper()
+
+	page, err := r.FindJobRuns(context.Background(), JobRunsFilter{})
+	require.NoError(t, err)
+	require.Len(t, page.Runs, 1)
+
+	return page.Runs[0].Job
+}
+
+func TestRouted(t *testing.T) {
+	var noLag, lagging time.Duration = 0, time.Minute
+
+	newPrimary := func(t *testing.T) DB {
+		t.Helper()
+
+		d := NewMemory()
+		replicaOf(t, d, "primary", &noLag, nil)
+
+		return d
+	}
+
+	t.Run("primary-read-until-replicas-measured", func(t *testing.T) {
+		r := newRouted(newPrimary(t), []*replica{replicaOf(t, NewMemory(), "replica", &noLag, nil)}, time.Second)
+
+		assert.Equal(t, "primary", readFrom(t, r))
+
+		r.replicas[0].probes.Wait()
+		assert.Equal(t, "replica", readFrom(t, r))
+	})
+
+	t.Run("reads-from-replicas-in-turn", func(t *testing.T) {
+		r := newRouted(newPrimary(t), []*replica{
+			replicaOf(t, NewMemory(), "replica-1", &noLag, nil),
+			replicaOf(t, NewMemory(), "replica-2", &noLag, nil),
+		}, time.Second)
+		measure(r)
+
+		first, second := readFrom(t, r), readFrom(t, r)
+		assert.ElementsMatch(t, []string{"replica-1", "replica-2"}, []string{first, second})
+		assert.Equal(t, first, readFrom(t, r))
+	})
+
+	t.Run("skips-lagging-replica", func(t *testing.T) {
+		r := newRouted(newPrimary(t), []*replica{
+			replicaOf(t, NewMemory(), "replica-1", &lagging, nil),
+			replicaOf(t, NewMemory(), "replica-2", &noLag, nil),
+		}, time.Second)
+		measure(r)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, "replica-2", readFrom(t, r))
+		}
+	})
+
+	t.Run("falls-back-to-primary", func(t *testing.T) {
+		r := newRouted(newPrimary(t), []*replica{
+			replicaOf(t, NewMemory(), "replica-1", &lagging, nil),
+			replicaOf(t, NewMemory(), "replica-2", &noLag, errors.New("mock")),
+		}, time.Second)
+		measure(r)
+
+		assert.Equal(t, "primary", readFrom(t, r))
+	})
+
+	t.Run("lag-measured-again-after-interval", func(t *testing.T) {
+		lag := lagging
+		r := newRouted(newPrimary(t), []*replica{replicaOf(t, NewMemory(), "replica", &lag, nil)}, time.Second)
+
+		now := time.Now()
+		r.now = func() time.Time { return now }
+		measure(r)
+
+		assert.Equal(t, "primary", readFrom(t, r))
+
+		lag = noLag
+		assert.Equal(t, "primary", readFrom(t, r))
+
+		// the previous measure is used until the new one is over.
+		now = now.Add(replicaCheckInterval)
+		assert.Equal(t, "primary", readFrom(t, r))
+
+		r.replicas[0].probes.Wait()
+		assert.Equal(t, "replica", readFrom(t, r))
+	})
+
+	t.Run("readers-not-blocked-by-measure", func(t *testing.T) {
+		release := make(chan struct{})
+		r := newRouted(newPrimary(t), []*replica{{
+			db: NewMemory(),
+			lag: func(ctx context.Context) (time.Duration, error) {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+
+				return 0, nil
+			},
+		}}, time.Second)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, "primary", readFrom(t, r))
+		}
+
+		close(release)
+		r.replicas[0].probes.Wait()
+	})
+
+	t.Run("failed-replica-query-retried-on-primary", func(t *testing.T) {
+		primary := NewMemory()
+		r := newRouted(primary, []*replica{{
+			db:  failingDB{NewMemory()},
+			lag: func(context.Context) (time.Duration, error) { return 0, nil },
+		}}, time.Second)
+		measure(r)
+
+		_, err := r.FindItems(context.Background(), ItemsFilter{})
+		require.NoError(t, err)
+		assert.False(t, r.replicas[0].usable(r.now(), time.Second))
+	})
+
+	t.Run("writes-and-transactions-on-primary", func(t *testing.T) {
+		primary := NewMemory()
+		rep := NewMemory()
+		r := newRouted(primary, []*replica{{db: rep, lag: func(context.Context) (time.Duration, error) { return 0, nil }}}, time.Second)
+
+		ctx := context.Background()
+
+		tx, err := r.Begin(ctx)
+		require.NoError(t, err)
+
+		s := domain.Seller{CurrencyCode: "USD"}
+		require.NoError(t, tx.InsertSeller(ctx, &s))
+		require.NoError(t, tx.Commit())
+
+		_, err = primary.FindSellerByID(ctx, s.ID.String())
+		require.NoError(t, err)
+
+		_, err = rep.FindSellerByID(ctx, s.ID.String())
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	})
+}
+
+func TestLagFromSeconds(t *testing.T) {
+	lag, err := lagFromSeconds(1.5)
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, lag)
+
+	_, err = lagFromSeconds(-1)
+	assert.ErrorIs(t, err, errReplicaDisconnected)
+}
@@ -0,0 +1,424 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -source=repositories.go -destination=$MOCK_FOLDER/repositories.go -package=mock
+
+// SellerRepository persists the sellers, their items are never written along.
+type SellerRepository interface {
+	// Create inserts s, its ID and timestamps are set on success.
+	Create(ctx context.Context, s *domain.Seller) error
+	Get(ctx context.Context, id string) (domain.Seller, error)
+	FindWithUnpaidOutItems(ctx context.Context, f SellersFilter) (SellersPage, error)
+}
+
+// ItemRepository persists the items, their seller and payout are never written along.
+type ItemRepository interface {
+	// Create inserts items, their IDs and timestamps are set on success.
+	Create(ctx context.Context, items []domain.Item) error
+	Get(ctx context.Context, id string) (domain.Item, error)
+	Find(ctx context.Context, f ItemsFilter) (ItemsPage, error)
+	FindUnpaidOutPage(ctx context.Context, sellerID string, after *Cursor, limit int) (ItemsPage, error)
+	// Lock locks the unpaid out items until the end of the transaction, see LockUnpaidItems.
+	Lock(ctx context.Context, ids []string) error
+	// PayOut marks the items paid out, see PayOutItems.
+	PayOut(ctx context.Context, ids []string) error
+}
+
+// PayoutRepository persists the payouts along with their lines, their seller, currency
+// and items are never written along.
+type PayoutRepository interface {
+	// Create inserts p and its lines, their IDs and timestamps are set on success.
+	Create(ctx context.Context, p *domain.Payout) error
+	Get(ctx context.Context, id string) (domain.Payout, error)
+	FindBySeller(ctx context.Context, sellerID string, f PayoutsFilter) (PayoutsPage, error)
+	SumBySeller(ctx context.Context, sellerID string, f PayoutsFilter) (decimal.Decimal, error)
+	FindByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error)
+	UpdateStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error
+	Export(ctx context.Context, bankFileID string, ids []string) error
+	Settle(ctx context.Context, ids []string, settledAt time.Time) error
+}
+
+// CurrencyRepository persists the currencies.
+type CurrencyRepository interface {
+	All(ctx context.Context) ([]domain.Currency, error)
+	// UpdateRates saves the USD exchange rates of currencies, see UpdateCurrencyRates.
+	UpdateRates(ctx context.Context, currencies []domain.Currency) error
+}
+
+// OutboxRepository records the events published afterwards by the outbox relay.
+type OutboxRepository interface {
+	// Add inserts m, its ID and sequence are set on success.
+	Add(ctx context.Context, m *domain.OutboxMessage) error
+}
+
+// JobRunRepository records the runs of the background jobs.
+type JobRunRepository interface {
+	// Start inserts r, its ID is set on success.
+	Start(ctx context.Context, r *domain.JobRun) error
+	// Finish saves the outcome of r along with its totals, see FinishJobRun.
+	Finish(ctx context.Context, r domain.JobRun) error
+	Get(ctx context.Context, id string) (domain.JobRun, error)
+	Find(ctx context.Context, f JobRunsFilter) (JobRunsPage, error)
+}
+
+// BankFileRepository persists the bank files, their payouts are exported with PayoutRepository.
+type BankFileRepository interface {
+	// Create inserts f, its ID and timestamps are set on success.
+	Create(ctx context.Context, f *domain.BankFile) error
+	Get(ctx context.Context, id string) (domain.BankFile, error)
+}
+
+// ReconciliationRepository persists the reconciliations along with their exceptions.
+type ReconciliationRepository interface {
+	// Create inserts r and its exceptions, their IDs and timestamps are set on success.
+	Create(ctx context.Context, r *domain.Reconciliation) error
+	Get(ctx context.Context, id string) (domain.Reconciliation, error)
+}
+
+// ItemImportRepository persists the items imports and their rejected rows, the items are created with ItemRepository.
+type ItemImportRepository interface {
+	// Create inserts i, its ID and timestamps are set on success.
+	Create(ctx context.Context, i *domain.ItemImport) error
+	// Finish saves the outcome and the rows counters of i, see FinishItemImport.
+	Finish(ctx context.Context, i domain.ItemImport) error
+	// Reject inserts rejected rows of an import.
+	Reject(ctx context.Context, rows []domain.ItemImportError) error
+	Get(ctx context.Context, id string) (domain.ItemImport, error)
+	FindRejected(ctx context.Context, importID string, afterRow, limit int) ([]domain.ItemImportError, error)
+}
+
+// WebhookRepository persists the webhook subscriptions, their deliveries and dead letters.
+type WebhookRepository interface {
+	// Subscribe inserts s, its ID and timestamps are set on success.
+	Subscribe(ctx context.Context, s *domain.WebhookSubscription) error
+	FindActiveSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// Enqueue inserts deliveries, their subscription is never written along.
+	Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	// FindDueDeliveries finds the pending deliveries due at a time, see FindDueWebhookDeliveries.
+	FindDueDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// UpdateDelivery saves the outcome of a delivery attempt.
+	UpdateDelivery(ctx context.Context, w domain.WebhookDelivery) error
+	// AddDeadLetter inserts l, its delivery is never written along.
+	AddDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error
+	GetDeadLetter(ctx context.Context, id string) (domain.WebhookDeadLetter, error)
+	// FindDeadLetters finds the dead letters not replayed yet.
+	FindDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error)
+	// ReplayDeadLetter schedules the delivery of a dead letter again, see ReplayWebhookDeadLetter.
+	ReplayDeadLetter(ctx context.Context, id string, at time.Time) error
+}
+
+// UnitOfWork gives the repositories, all bound to the same transaction within Transaction.
+type UnitOfWork interface {
+	Sellers() SellerRepository
+	Items() ItemRepository
+	Payouts() PayoutRepository
+	Currencies() CurrencyRepository
+	Outbox() OutboxRepository
+	JobRuns() JobRunRepository
+	BankFiles() BankFileRepository
+	Reconciliations() ReconciliationRepository
+	ItemImports() ItemImportRepository
+	Webhooks() WebhookRepository
+	// Transaction runs fn in a transaction, committed when fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(tx UnitOfWork) error) error
+}
+
+// NewUnitOfWork returns the repositories running on d.
+func NewUnitOfWork(d DB) UnitOfWork {
+	return unitOfWork{db: d}
+}
+
+type unitOfWork struct {
+	db DB
+}
+
+func (u unitOfWork) Sellers() SellerRepository {
+	return sellerRepository{u.db}
+}
+
+func (u unitOfWork) Items() ItemRepository {
+	return itemRepository{u.db}
+}
+
+func (u unitOfWork) Payouts() PayoutRepository {
+	return payoutRepository{u.db}
+}
+
+func (u unitOfWork) Currencies() CurrencyRepository {
+	return currencyRepository{u.db}
+}
+
+func (u unitOfWork) Outbox() OutboxRepository {
+	return outboxRepository{u.db}
+}
+
+func (u unitOfWork) JobRuns() JobRunRepository {
+	return jobRunRepository{u.db}
+}
+
+func (u unitOfWork) BankFiles() BankFileRepository {
+	return bankFileRepository{u.db}
+}
+
+func (u unitOfWork) Reconciliations() ReconciliationRepository {
+	return reconciliationRepository{u.db}
+}
+
+func (u unitOfWork) ItemImports() ItemImportRepository {
+	return itemImportRepository{u.db}
+}
+
+func (u unitOfWork) Webhooks() WebhookRepository {
+	return webhookRepository{u.db}
+}
+
+func (u unitOfWork) Transaction(ctx context.Context, fn func(tx UnitOfWork) error) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create DB transaction: %w", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+
+			panic(r)
+		}
+	}()
+
+	if err := fn(unitOfWork{db: tx}); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction in DB: %w", err)
+	}
+
+	return nil
+}
+
+type sellerRepository struct {
+	db DB
+}
+
+func (r sellerRepository) Create(ctx context.Context, s *domain.Seller) error {
+	return r.db.InsertSeller(ctx, s)
+}
+
+func (r sellerRepository) Get(ctx context.Context, id string) (domain.Seller, error) {
+	return r.db.FindSellerByID(ctx, id)
+}
+
+func (r sellerRepository) FindWithUnpaidOutItems(ctx context.Context, f SellersFilter) (SellersPage, error) {
+	return r.db.FindSellersWithUnpaidOutItems(ctx, f)
+}
+
+type itemRepository struct {
+	db DB
+}
+
+func (r itemRepository) Create(ctx context.Context, items []domain.Item) error {
+	return r.db.InsertItems(ctx, items)
+}
+
+func (r itemRepository) Get(ctx context.Context, id string) (domain.Item, error) {
+	return r.db.FindItemByID(ctx, id)
+}
+
+func (r itemRepository) Find(ctx context.Context, f ItemsFilter) (ItemsPage, error) {
+	return r.db.FindItems(ctx, f)
+}
+
+func (r itemRepository) FindUnpaidOutPage(
+	ctx context.Context,
+	sellerID string,
+	after *Cursor,
+	limit int) (ItemsPage, error) {
+	return r.db.FindUnpaidOutItemsPage(ctx, sellerID, after, limit)
+}
+
+func (r itemRepository) Lock(ctx context.Context, ids []string) error {
+	return r.db.LockUnpaidItems(ctx, ids)
+}
+
+func (r itemRepository) PayOut(ctx context.Context, ids []string) error {
+	return r.db.PayOutItems(ctx, ids)
+}
+
+type payoutRepository struct {
+	db DB
+}
+
+// Create relies on InsertPayout which omits the associations, only the lines are inserted.
+func (r payoutRepository) Create(ctx context.Context, p *domain.Payout) error {
+	return r.db.InsertPayout(ctx, p)
+}
+
+func (r payoutRepository) Get(ctx context.Context, id string) (domain.Payout, error) {
+	return r.db.FindPayoutByID(ctx, id)
+}
+
+func (r payoutRepository) FindBySeller(ctx context.Context, sellerID string, f PayoutsFilter) (PayoutsPage, error) {
+	return r.db.FindPayoutsBySellerID(ctx, sellerID, f)
+}
+
+func (r payoutRepository) SumBySeller(ctx context.Context, sellerID string, f PayoutsFilter) (decimal.Decimal, error) {
+	return r.db.SumPayoutsBySellerID(ctx, sellerID, f)
+}
+
+func (r payoutRepository) FindByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error) {
+	return r.db.FindPayoutsByStatus(ctx, status)
+}
+
+func (r payoutRepository) UpdateStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error {
+	return r.db.UpdatePayoutStatus(ctx, id, from, to)
+}
+
+func (r payoutRepository) Export(ctx context.Context, bankFileID string, ids []string) error {
+	return r.db.ExportPayouts(ctx, bankFileID, ids)
+}
+
+func (r payoutRepository) Settle(ctx context.Context, ids []string, settledAt time.Time) error {
+	return r.db.SettlePayouts(ctx, ids, settledAt)
+}
+
+type currencyRepository struct {
+	db DB
+}
+
+func (r currencyRepository) All(ctx context.Context) ([]domain.Currency, error) {
+	return r.db.FindCurrencies(ctx)
+}
+
+func (r currencyRepository) UpdateRates(ctx context.Context, currencies []domain.Currency) error {
+	return r.db.UpdateCurrencyRates(ctx, currencies)
+}
+
+type outboxRepository struct {
+	db DB
+}
+
+func (r outboxRepository) Add(ctx context.Context, m *domain.OutboxMessage) error {
+	return r.db.InsertOutboxMessage(ctx, m)
+}
+
+type jobRunRepository struct {
+	db DB
+}
+
+func (r jobRunRepository) Start(ctx context.Context, run *domain.JobRun) error {
+	return r.db.InsertJobRun(ctx, run)
+}
+
+func (r jobRunRepository) Finish(ctx context.Context, run domain.JobRun) error {
+	return r.db.FinishJobRun(ctx, run)
+}
+
+func (r jobRunRepository) Get(ctx context.Context, id string) (domain.JobRun, error) {
+	return r.db.FindJobRunByID(ctx, id)
+}
+
+func (r jobRunRepository) Find(ctx context.Context, f JobRunsFilter) (JobRunsPage, error) {
+	return r.db.FindJobRuns(ctx, f)
+}
+
+type bankFileRepository struct {
+	db DB
+}
+
+func (r bankFileRepository) Create(ctx context.Context, f *domain.BankFile) error {
+	return r.db.InsertBankFile(ctx, f)
+}
+
+func (r bankFileRepository) Get(ctx context.Context, id string) (domain.BankFile, error) {
+	return r.db.FindBankFileByID(ctx, id)
+}
+
+type reconciliationRepository struct {
+	db DB
+}
+
+func (r reconciliationRepository) Create(ctx context.Context, rec *domain.Reconciliation) error {
+	return r.db.InsertReconciliation(ctx, rec)
+}
+
+func (r reconciliationRepository) Get(ctx context.Context, id string) (domain.Reconciliation, error) {
+	return r.db.FindReconciliationByID(ctx, id)
+}
+
+type itemImportRepository struct {
+	db DB
+}
+
+func (r itemImportRepository) Create(ctx context.Context, i *domain.ItemImport) error {
+	return r.db.InsertItemImport(ctx, i)
+}
+
+func (r itemImportRepository) Finish(ctx context.Context, i domain.ItemImport) error {
+	return r.db.FinishItemImport(ctx, i)
+}
+
+func (r itemImportRepository) Reject(ctx context.Context, rows []domain.ItemImportError) error {
+	return r.db.InsertItemImportErrors(ctx, rows)
+}
+
+func (r itemImportRepository) Get(ctx context.Context, id string) (domain.ItemImport, error) {
+	return r.db.FindItemImportByID(ctx, id)
+}
+
+func (r itemImportRepository) FindRejected(
+	ctx context.Context,
+	importID string,
+	afterRow, limit int) ([]domain.ItemImportError, error) {
+	return r.db.FindItemImportErrors(ctx, importID, afterRow, limit)
+}
+
+type webhookRepository struct {
+	db DB
+}
+
+func (r webhookRepository) Subscribe(ctx context.Context, s *domain.WebhookSubscription) error {
+	return r.db.InsertWebhookSubscription(ctx, s)
+}
+
+func (r webhookRepository) FindActiveSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.db.FindActiveWebhookSubscriptions(ctx)
+}
+
+func (r webhookRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	return r.db.InsertWebhookDeliveries(ctx, deliveries)
+}
+
+func (r webhookRepository) FindDueDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	return r.db.FindDueWebhookDeliveries(ctx, at, limit)
+}
+
+func (r webhookRepository) UpdateDelivery(ctx context.Context, w domain.WebhookDelivery) error {
+	return r.db.UpdateWebhookDelivery(ctx, w)
+}
+
+func (r webhookRepository) AddDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error {
+	return r.db.InsertWebhookDeadLetter(ctx, l)
+}
+
+func (r webhookRepository) GetDeadLetter(ctx context.Context, id string) (domain.WebhookDeadLetter, error) {
+	return r.db.FindWebhookDeadLetterByID(ctx, id)
+}
+
+func (r webhookRepository) FindDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error) {
+	return r.db.FindWebhookDeadLetters(ctx)
+}
+
+func (r webhookRepository) ReplayDeadLetter(ctx context.Context, id string, at time.Time) error {
+	return r.db.ReplayWebhookDeadLetter(ctx, id, at)
+}
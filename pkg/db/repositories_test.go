@@ -0,0 +1,152 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"github.com/TestardR/seller-payout/pkg/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("item-create-leaves-seller-untouched", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+
+		s := domain.Seller{CurrencyCode: "USD", Name: "before"}
+		require.NoError(t, repos.Sellers().Create(ctx, &s))
+
+		preloaded := s
+		preloaded.Name = "after"
+
+		items := []domain.Item{{SellerID: s.ID, Seller: preloaded, PriceAmount: decimal.NewFromInt(1), CurrencyCode: "USD"}}
+		require.NoError(t, repos.Items().Create(ctx, items))
+
+		assert.NotEqual(t, "", items[0].ID.String())
+		assert.Equal(t, "after", items[0].Seller.Name)
+
+		got, err := repos.Sellers().Get(ctx, s.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "before", got.Name)
+	})
+
+	t.Run("seller-create-leaves-items-untouched", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+
+		s := domain.Seller{CurrencyCode: "USD", Items: []domain.Item{{CurrencyCode: "USD"}}}
+		require.NoError(t, repos.Sellers().Create(ctx, &s))
+		assert.Len(t, s.Items, 1)
+
+		page, err := repos.Items().Find(ctx, db.ItemsFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+
+	t.Run("transaction-rolled-back-on-error", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+		merr := errors.New("mock")
+		s := domain.Seller{CurrencyCode: "USD"}
+
+		err := repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+			require.NoError(t, tx.Sellers().Create(ctx, &s))
+
+			return merr
+		})
+		require.ErrorIs(t, err, merr)
+
+		page, err := repos.Sellers().FindWithUnpaidOutItems(ctx, db.SellersFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Sellers)
+
+		_, err = repos.Sellers().Get(ctx, s.ID.String())
+		assert.ErrorIs(t, err, db.ErrRecordNotFound)
+	})
+
+	t.Run("transaction-committed", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+
+		var s domain.Seller
+
+		err := repos.Transaction(ctx, func(tx db.UnitOfWork) error {
+			s = domain.Seller{CurrencyCode: "USD"}
+			if err := tx.Sellers().Create(ctx, &s); err != nil {
+				return err
+			}
+
+			return tx.Items().Create(ctx, []domain.Item{{SellerID: s.ID, PriceAmount: decimal.NewFromInt(1), CurrencyCode: "USD"}})
+		})
+		require.NoError(t, err)
+
+		page, err := repos.Items().FindUnpaidOutPage(ctx, s.ID.String(), nil, 10)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+	})
+
+	t.Run("currencies-rates-updated", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+
+		currencies, err := repos.Currencies().All(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, currencies)
+
+		for i := range currencies {
+			currencies[i].USDExchRate = decimal.NewFromInt(2)
+		}
+
+		require.NoError(t, repos.Currencies().UpdateRates(ctx, currencies))
+
+		currencies, err = repos.Currencies().All(ctx)
+		require.NoError(t, err)
+
+		for _, c := range currencies {
+			assert.True(t, c.USDExchRate.Equal(decimal.NewFromInt(2)), c.Code)
+		}
+	})
+
+	t.Run("currencies-rates-not-saved-with-unknown-code", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+
+		currencies, err := repos.Currencies().All(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, currencies)
+
+		currencies[0].USDExchRate = decimal.NewFromInt(2)
+		unknown := domain.Currency{Code: "XXX", USDExchRate: decimal.NewFromInt(2)}
+
+		err = repos.Currencies().UpdateRates(ctx, []domain.Currency{currencies[0], unknown})
+		assert.ErrorIs(t, err, db.ErrRecordNotFound)
+
+		saved, err := repos.Currencies().All(ctx)
+		require.NoError(t, err)
+
+		for _, c := range saved {
+			assert.False(t, c.USDExchRate.Equal(decimal.NewFromInt(2)), c.Code)
+		}
+	})
+
+	t.Run("item-import-finished", func(t *testing.T) {
+		repos := db.NewUnitOfWork(db.NewMemory())
+
+		i := domain.ItemImport{Format: "csv", Status: domain.ItemImportStatusProcessing}
+		require.NoError(t, repos.ItemImports().Create(ctx, &i))
+		require.NoError(t, repos.ItemImports().Reject(ctx, []domain.ItemImportError{{ItemImportID: i.ID, RowNumber: 2}}))
+		require.NoError(t, repos.ItemImports().Reject(ctx, nil))
+
+		i.Status, i.TotalRows, i.FailedRows = domain.ItemImportStatusCompleted, 2, 1
+		require.NoError(t, repos.ItemImports().Finish(ctx, i))
+
+		got, err := repos.ItemImports().Get(ctx, i.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, domain.ItemImportStatusCompleted, got.Status)
+		assert.Equal(t, 2, got.TotalRows)
+
+		rejected, err := repos.ItemImports().FindRejected(ctx, i.ID.String(), 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, rejected, 1)
+	})
+}
@@ -1,35 +1,64 @@
 package db
 
 import (
-	"errors"
-	"fmt"
+	"context"
 
 	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm/clause"
 )
 
-// FindAllSellerWithUnpaidoutItems finds all sellers with unpaid out items.
-func (d database) FindSellersWhereItems(where map[string]interface{}) ([]domain.Seller, error) {
-	var s []domain.Seller
+// SellersFilter narrows down and paginates the sellers having unpaid out items.
+type SellersFilter struct {
+	// IDs restricts the sellers to some of them, all sellers are listed when empty.
+	IDs []string
+	// After is the keyset position from which the page starts, nil for the first page.
+	After *Cursor
+	Limit int
+}
+
+// SellersPage is a page of sellers, their items are not loaded.
+type SellersPage struct {
+	Sellers []domain.Seller
+	// Next is the cursor of the following page, nil on the last page.
+	Next *Cursor
+}
 
-	db, err := d.preloadSellersRelations(where)
-	if err != nil {
-		return nil, fmt.Errorf("failed to preload Items: %w", err)
+// FindSellersWithUnpaidOutItems finds a page of the sellers having unpaid out items, ordered by (created_at, id).
+func (d database) FindSellersWithUnpaidOutItems(ctx context.Context, f SellersFilter) (SellersPage, error) {
+	tx := d.driver.WithContext(ctx).Model(&domain.Seller{}).
+		Where("EXISTS (SELECT 1 FROM items WHERE items.seller_id = sellers.id AND items.paid_out = false)")
+
+	if len(f.IDs) > 0 {
+		tx = tx.Where("id IN ?", f.IDs)
 	}
 
-	err = db.FindAll(&s)
-	if err == nil {
-		return s, nil
+	var page SellersPage
+
+	if err := keyset(tx, f.After, false, f.Limit).Find(&page.Sellers).Error; err != nil {
+		return SellersPage{}, err
 	}
 
-	if errors.Is(err, ErrRecordNotFound) {
-		return nil, ErrRecordNotFound
+	if f.Limit > 0 && len(page.Sellers) > f.Limit {
+		page.Sellers = page.Sellers[:f.Limit]
+		last := page.Sellers[f.Limit-1]
+		page.Next = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
 	}
 
-	return nil, err
+	return page, nil
 }
 
-func (d database) preloadSellersRelations(where map[string]interface{}) (DB, error) {
-	tx := d.driver.Preload("Items", where)
+// InsertSeller inserts a seller without its items.
+func (d database) InsertSeller(ctx context.Context, s *domain.Seller) error {
+	return d.driver.WithContext(ctx).Omit(clause.Associations).Create(s).Error
+}
+
+// FindSellerByID finds a seller by id, without its items.
+func (d database) FindSellerByID(ctx context.Context, id string) (domain.Seller, error) {
+	var s domain.Seller
+
+	if err := d.driver.WithContext(ctx).Take(&s, "id = ?", id).Error; err != nil {
+		return domain.Seller{}, err
+	}
 
-	return &database{driver: tx}, tx.Error
+	return s, nil
 }
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/TestardR/seller-payout/internal/domain"
+	"gorm.io/gorm/clause"
+)
+
+// InsertWebhookSubscription inserts a webhook subscription.
+func (d database) InsertWebhookSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	return d.driver.WithContext(ctx).Create(s).Error
+}
+
+// FindActiveWebhookSubscriptions finds the active webhook subscriptions, oldest first.
+func (d database) FindActiveWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	var subs []domain.WebhookSubscription
+
+	if err := d.driver.WithContext(ctx).Where("active = ?", true).Order("created_at ASC, id ASC").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+// InsertWebhookDeliveries inserts deliveries, without their subscription.
+func (d database) InsertWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return d.driver.WithContext(ctx).Omit(clause.Associations).Create(&deliveries).Error
+}
+
+// InsertWebhookDeadLetter inserts the dead letter of a delivery, without the delivery.
+func (d database) InsertWebhookDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error {
+	return d.driver.WithContext(ctx).Omit(clause.Associations).Create(l).Error
+}
+
+// FindWebhookDeadLetterByID finds a dead letter by id.
+func (d database) FindWebhookDeadLetterByID(ctx context.Context, id string) (domain.WebhookDeadLetter, error) {
+	var l domain.WebhookDeadLetter
+
+	if err := d.driver.WithContext(ctx).Take(&l, "id = ?", id).Error; err != nil {
+		return domain.WebhookDeadLetter{}, err
+	}
+
+	return l, nil
+}
+
+// FindWebhookDeadLetters finds the dead letters not replayed yet, oldest first.
+func (d database) FindWebhookDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error) {
+	letters := []domain.WebhookDeadLetter{}
+
+	err := d.driver.WithContext(ctx).
+		Where("replayed_at IS NULL").
+		Order("created_at ASC, id ASC").
+		Find(&letters).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+// FindDueWebhookDeliveries finds the pending deliveries due at a time along with their subscription, oldest first.
+func (d database) FindDueWebhookDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+
+	err := d.driver.WithContext(ctx).
+		Joins("Subscription").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", domain.WebhookDeliveryPending, at).
+		Order("webhook_deliveries.next_attempt_at ASC, webhook_deliveries.id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery saves the outcome of a delivery attempt.
+func (d database) UpdateWebhookDelivery(ctx context.Context, w domain.WebhookDelivery) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Where("id = ?", w.ID).
+		Updates(map[string]interface{}{
+			"status":          w.Status,
+			"attempts":        w.Attempts,
+			"next_attempt_at": w.NextAttemptAt,
+			"last_error":      w.LastError,
+			"delivered_at":    w.DeliveredAt,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ReplayWebhookDeadLetter marks a dead letter as replayed and schedules its delivery for a new round of attempts,
+// ErrRecordNotFound is returned when the dead letter does not exist or was already replayed.
+func (d database) ReplayWebhookDeadLetter(ctx context.Context, id string, at time.Time) error {
+	tx := d.driver.WithContext(ctx).Model(&domain.WebhookDeadLetter{}).
+		Where("id = ? AND replayed_at IS NULL", id).
+		Update("replayed_at", at)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if tx.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	deliveryID := d.driver.WithContext(ctx).Model(&domain.WebhookDeadLetter{}).Select("delivery_id").Where("id = ?", id)
+
+	return d.driver.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Where("id = (?)", deliveryID).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": at,
+		}).Error
+}
@@ -5,6 +5,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,16 +36,16 @@ func (m *MockExchanger) EXPECT() *MockExchangerMockRecorder {
 }
 
 // GetConversionRate mocks base method.
-func (m *MockExchanger) GetConversionRate(currency string) (decimal.Decimal, error) {
+func (m *MockExchanger) GetConversionRate(ctx context.Context, currency string) (decimal.Decimal, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetConversionRate", currency)
+	ret := m.ctrl.Call(m, "GetConversionRate", ctx, currency)
 	ret0, _ := ret[0].(decimal.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetConversionRate indicates an expected call of GetConversionRate.
-func (mr *MockExchangerMockRecorder) GetConversionRate(currency interface{}) *gomock.Call {
+func (mr *MockExchangerMockRecorder) GetConversionRate(ctx, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversionRate", reflect.TypeOf((*MockExchanger)(nil).GetConversionRate), currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversionRate", reflect.TypeOf((*MockExchanger)(nil).GetConversionRate), ctx, currency)
 }
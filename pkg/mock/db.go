@@ -5,11 +5,14 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/TestardR/seller-payout/internal/domain"
 	db "github.com/TestardR/seller-payout/pkg/db"
 	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
 )
 
 // MockDB is a mock of DB interface.
@@ -36,18 +39,32 @@ func (m *MockDB) EXPECT() *MockDBMockRecorder {
 }
 
 // Begin mocks base method.
-func (m *MockDB) Begin() (db.DB, error) {
+func (m *MockDB) Begin(ctx context.Context) (db.DB, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Begin")
+	ret := m.ctrl.Call(m, "Begin", ctx)
 	ret0, _ := ret[0].(db.DB)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Begin indicates an expected call of Begin.
-func (mr *MockDBMockRecorder) Begin() *gomock.Call {
+func (mr *MockDBMockRecorder) Begin(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockDB)(nil).Begin))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockDB)(nil).Begin), ctx)
+}
+
+// Close mocks base method.
+func (m *MockDB) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockDBMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDB)(nil).Close))
 }
 
 // Commit mocks base method.
@@ -64,134 +81,600 @@ func (mr *MockDBMockRecorder) Commit() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockDB)(nil).Commit))
 }
 
-// FindAll mocks base method.
-func (m *MockDB) FindAll(dest interface{}) error {
+// ExportPayouts mocks base method.
+func (m *MockDB) ExportPayouts(ctx context.Context, bankFileID string, payoutIDs []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", dest)
+	ret := m.ctrl.Call(m, "ExportPayouts", ctx, bankFileID, payoutIDs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// FindAll indicates an expected call of FindAll.
-func (mr *MockDBMockRecorder) FindAll(dest interface{}) *gomock.Call {
+// ExportPayouts indicates an expected call of ExportPayouts.
+func (mr *MockDBMockRecorder) ExportPayouts(ctx, bankFileID, payoutIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockDB)(nil).FindAll), dest)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPayouts", reflect.TypeOf((*MockDB)(nil).ExportPayouts), ctx, bankFileID, payoutIDs)
 }
 
-// FindAllWhere mocks base method.
-func (m *MockDB) FindAllWhere(dest interface{}, conds map[string]interface{}) error {
+// FindActiveWebhookSubscriptions mocks base method.
+func (m *MockDB) FindActiveWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAllWhere", dest, conds)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FindActiveWebhookSubscriptions", ctx)
+	ret0, _ := ret[0].([]domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// FindAllWhere indicates an expected call of FindAllWhere.
-func (mr *MockDBMockRecorder) FindAllWhere(dest, conds interface{}) *gomock.Call {
+// FindActiveWebhookSubscriptions indicates an expected call of FindActiveWebhookSubscriptions.
+func (mr *MockDBMockRecorder) FindActiveWebhookSubscriptions(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllWhere", reflect.TypeOf((*MockDB)(nil).FindAllWhere), dest, conds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveWebhookSubscriptions", reflect.TypeOf((*MockDB)(nil).FindActiveWebhookSubscriptions), ctx)
 }
 
-// FindByID mocks base method.
-func (m *MockDB) FindByID(dest interface{}, id string) error {
+// FindBankFileByID mocks base method.
+func (m *MockDB) FindBankFileByID(ctx context.Context, id string) (domain.BankFile, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByID", dest, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "FindBankFileByID", ctx, id)
+	ret0, _ := ret[0].(domain.BankFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBankFileByID indicates an expected call of FindBankFileByID.
+func (mr *MockDBMockRecorder) FindBankFileByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBankFileByID", reflect.TypeOf((*MockDB)(nil).FindBankFileByID), ctx, id)
+}
+
+// FindCurrencies mocks base method.
+func (m *MockDB) FindCurrencies(ctx context.Context) ([]domain.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCurrencies", ctx)
+	ret0, _ := ret[0].([]domain.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCurrencies indicates an expected call of FindCurrencies.
+func (mr *MockDBMockRecorder) FindCurrencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCurrencies", reflect.TypeOf((*MockDB)(nil).FindCurrencies), ctx)
+}
+
+// FindDueWebhookDeliveries mocks base method.
+func (m *MockDB) FindDueWebhookDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueWebhookDeliveries", ctx, at, limit)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueWebhookDeliveries indicates an expected call of FindDueWebhookDeliveries.
+func (mr *MockDBMockRecorder) FindDueWebhookDeliveries(ctx, at, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueWebhookDeliveries", reflect.TypeOf((*MockDB)(nil).FindDueWebhookDeliveries), ctx, at, limit)
+}
+
+// FindItemByID mocks base method.
+func (m *MockDB) FindItemByID(ctx context.Context, id string) (domain.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindItemByID", ctx, id)
+	ret0, _ := ret[0].(domain.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindItemByID indicates an expected call of FindItemByID.
+func (mr *MockDBMockRecorder) FindItemByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindItemByID", reflect.TypeOf((*MockDB)(nil).FindItemByID), ctx, id)
+}
+
+// FindItemImportByID mocks base method.
+func (m *MockDB) FindItemImportByID(ctx context.Context, id string) (domain.ItemImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindItemImportByID", ctx, id)
+	ret0, _ := ret[0].(domain.ItemImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindItemImportByID indicates an expected call of FindItemImportByID.
+func (mr *MockDBMockRecorder) FindItemImportByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindItemImportByID", reflect.TypeOf((*MockDB)(nil).FindItemImportByID), ctx, id)
+}
+
+// FindItemImportErrors mocks base method.
+func (m *MockDB) FindItemImportErrors(ctx context.Context, importID string, afterRow, limit int) ([]domain.ItemImportError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindItemImportErrors", ctx, importID, afterRow, limit)
+	ret0, _ := ret[0].([]domain.ItemImportError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// FindByID indicates an expected call of FindByID.
-func (mr *MockDBMockRecorder) FindByID(dest, id interface{}) *gomock.Call {
+// FindItemImportErrors indicates an expected call of FindItemImportErrors.
+func (mr *MockDBMockRecorder) FindItemImportErrors(ctx, importID, afterRow, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDB)(nil).FindByID), dest, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindItemImportErrors", reflect.TypeOf((*MockDB)(nil).FindItemImportErrors), ctx, importID, afterRow, limit)
+}
+
+// FindItems mocks base method.
+func (m *MockDB) FindItems(ctx context.Context, f db.ItemsFilter) (db.ItemsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindItems", ctx, f)
+	ret0, _ := ret[0].(db.ItemsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindItems indicates an expected call of FindItems.
+func (mr *MockDBMockRecorder) FindItems(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindItems", reflect.TypeOf((*MockDB)(nil).FindItems), ctx, f)
+}
+
+// FindJobRunByID mocks base method.
+func (m *MockDB) FindJobRunByID(ctx context.Context, id string) (domain.JobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindJobRunByID", ctx, id)
+	ret0, _ := ret[0].(domain.JobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindJobRunByID indicates an expected call of FindJobRunByID.
+func (mr *MockDBMockRecorder) FindJobRunByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindJobRunByID", reflect.TypeOf((*MockDB)(nil).FindJobRunByID), ctx, id)
+}
+
+// FindJobRuns mocks base method.
+func (m *MockDB) FindJobRuns(ctx context.Context, f db.JobRunsFilter) (db.JobRunsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindJobRuns", ctx, f)
+	ret0, _ := ret[0].(db.JobRunsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindJobRuns indicates an expected call of FindJobRuns.
+func (mr *MockDBMockRecorder) FindJobRuns(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindJobRuns", reflect.TypeOf((*MockDB)(nil).FindJobRuns), ctx, f)
+}
+
+// FindPayoutByID mocks base method.
+func (m *MockDB) FindPayoutByID(ctx context.Context, id string) (domain.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPayoutByID", ctx, id)
+	ret0, _ := ret[0].(domain.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPayoutByID indicates an expected call of FindPayoutByID.
+func (mr *MockDBMockRecorder) FindPayoutByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayoutByID", reflect.TypeOf((*MockDB)(nil).FindPayoutByID), ctx, id)
 }
 
 // FindPayoutsBySellerID mocks base method.
-func (m *MockDB) FindPayoutsBySellerID(arg0 string) ([]domain.Payout, error) {
+func (m *MockDB) FindPayoutsBySellerID(ctx context.Context, id string, f db.PayoutsFilter) (db.PayoutsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPayoutsBySellerID", arg0)
-	ret0, _ := ret[0].([]domain.Payout)
+	ret := m.ctrl.Call(m, "FindPayoutsBySellerID", ctx, id, f)
+	ret0, _ := ret[0].(db.PayoutsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindPayoutsBySellerID indicates an expected call of FindPayoutsBySellerID.
-func (mr *MockDBMockRecorder) FindPayoutsBySellerID(arg0 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) FindPayoutsBySellerID(ctx, id, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayoutsBySellerID", reflect.TypeOf((*MockDB)(nil).FindPayoutsBySellerID), ctx, id, f)
+}
+
+// FindPayoutsByStatus mocks base method.
+func (m *MockDB) FindPayoutsByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPayoutsByStatus", ctx, status)
+	ret0, _ := ret[0].([]domain.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPayoutsByStatus indicates an expected call of FindPayoutsByStatus.
+func (mr *MockDBMockRecorder) FindPayoutsByStatus(ctx, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayoutsBySellerID", reflect.TypeOf((*MockDB)(nil).FindPayoutsBySellerID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayoutsByStatus", reflect.TypeOf((*MockDB)(nil).FindPayoutsByStatus), ctx, status)
 }
 
-// FindSellersWhereItems mocks base method.
-func (m *MockDB) FindSellersWhereItems(conds map[string]interface{}) ([]domain.Seller, error) {
+// FindReconciliationByID mocks base method.
+func (m *MockDB) FindReconciliationByID(ctx context.Context, id string) (domain.Reconciliation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindSellersWhereItems", conds)
-	ret0, _ := ret[0].([]domain.Seller)
+	ret := m.ctrl.Call(m, "FindReconciliationByID", ctx, id)
+	ret0, _ := ret[0].(domain.Reconciliation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindSellersWhereItems indicates an expected call of FindSellersWhereItems.
-func (mr *MockDBMockRecorder) FindSellersWhereItems(conds interface{}) *gomock.Call {
+// FindReconciliationByID indicates an expected call of FindReconciliationByID.
+func (mr *MockDBMockRecorder) FindReconciliationByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSellersWhereItems", reflect.TypeOf((*MockDB)(nil).FindSellersWhereItems), conds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReconciliationByID", reflect.TypeOf((*MockDB)(nil).FindReconciliationByID), ctx, id)
+}
+
+// FindSellerByID mocks base method.
+func (m *MockDB) FindSellerByID(ctx context.Context, id string) (domain.Seller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSellerByID", ctx, id)
+	ret0, _ := ret[0].(domain.Seller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSellerByID indicates an expected call of FindSellerByID.
+func (mr *MockDBMockRecorder) FindSellerByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSellerByID", reflect.TypeOf((*MockDB)(nil).FindSellerByID), ctx, id)
+}
+
+// FindSellersWithUnpaidOutItems mocks base method.
+func (m *MockDB) FindSellersWithUnpaidOutItems(ctx context.Context, f db.SellersFilter) (db.SellersPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSellersWithUnpaidOutItems", ctx, f)
+	ret0, _ := ret[0].(db.SellersPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSellersWithUnpaidOutItems indicates an expected call of FindSellersWithUnpaidOutItems.
+func (mr *MockDBMockRecorder) FindSellersWithUnpaidOutItems(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSellersWithUnpaidOutItems", reflect.TypeOf((*MockDB)(nil).FindSellersWithUnpaidOutItems), ctx, f)
 }
 
 // FindUnpaidOutItems mocks base method.
-func (m *MockDB) FindUnpaidOutItems() ([]domain.Item, error) {
+func (m *MockDB) FindUnpaidOutItems(ctx context.Context) ([]domain.Item, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindUnpaidOutItems")
+	ret := m.ctrl.Call(m, "FindUnpaidOutItems", ctx)
 	ret0, _ := ret[0].([]domain.Item)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindUnpaidOutItems indicates an expected call of FindUnpaidOutItems.
-func (mr *MockDBMockRecorder) FindUnpaidOutItems() *gomock.Call {
+func (mr *MockDBMockRecorder) FindUnpaidOutItems(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaidOutItems", reflect.TypeOf((*MockDB)(nil).FindUnpaidOutItems))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaidOutItems", reflect.TypeOf((*MockDB)(nil).FindUnpaidOutItems), ctx)
 }
 
 // FindUnpaidOutItemsBySellerID mocks base method.
-func (m *MockDB) FindUnpaidOutItemsBySellerID(arg0 string) ([]domain.Item, error) {
+func (m *MockDB) FindUnpaidOutItemsBySellerID(ctx context.Context, id string) ([]domain.Item, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindUnpaidOutItemsBySellerID", arg0)
+	ret := m.ctrl.Call(m, "FindUnpaidOutItemsBySellerID", ctx, id)
 	ret0, _ := ret[0].([]domain.Item)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindUnpaidOutItemsBySellerID indicates an expected call of FindUnpaidOutItemsBySellerID.
-func (mr *MockDBMockRecorder) FindUnpaidOutItemsBySellerID(arg0 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) FindUnpaidOutItemsBySellerID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaidOutItemsBySellerID", reflect.TypeOf((*MockDB)(nil).FindUnpaidOutItemsBySellerID), ctx, id)
+}
+
+// FindUnpaidOutItemsPage mocks base method.
+func (m *MockDB) FindUnpaidOutItemsPage(ctx context.Context, sellerID string, after *db.Cursor, limit int) (db.ItemsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnpaidOutItemsPage", ctx, sellerID, after, limit)
+	ret0, _ := ret[0].(db.ItemsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnpaidOutItemsPage indicates an expected call of FindUnpaidOutItemsPage.
+func (mr *MockDBMockRecorder) FindUnpaidOutItemsPage(ctx, sellerID, after, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaidOutItemsBySellerID", reflect.TypeOf((*MockDB)(nil).FindUnpaidOutItemsBySellerID), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaidOutItemsPage", reflect.TypeOf((*MockDB)(nil).FindUnpaidOutItemsPage), ctx, sellerID, after, limit)
+}
+
+// FindUnpublishedOutboxMessages mocks base method.
+func (m *MockDB) FindUnpublishedOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnpublishedOutboxMessages", ctx, limit)
+	ret0, _ := ret[0].([]domain.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnpublishedOutboxMessages indicates an expected call of FindUnpublishedOutboxMessages.
+func (mr *MockDBMockRecorder) FindUnpublishedOutboxMessages(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpublishedOutboxMessages", reflect.TypeOf((*MockDB)(nil).FindUnpublishedOutboxMessages), ctx, limit)
+}
+
+// FindWebhookDeadLetterByID mocks base method.
+func (m *MockDB) FindWebhookDeadLetterByID(ctx context.Context, id string) (domain.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWebhookDeadLetterByID", ctx, id)
+	ret0, _ := ret[0].(domain.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWebhookDeadLetterByID indicates an expected call of FindWebhookDeadLetterByID.
+func (mr *MockDBMockRecorder) FindWebhookDeadLetterByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebhookDeadLetterByID", reflect.TypeOf((*MockDB)(nil).FindWebhookDeadLetterByID), ctx, id)
+}
+
+// FindWebhookDeadLetters mocks base method.
+func (m *MockDB) FindWebhookDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWebhookDeadLetters", ctx)
+	ret0, _ := ret[0].([]domain.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWebhookDeadLetters indicates an expected call of FindWebhookDeadLetters.
+func (mr *MockDBMockRecorder) FindWebhookDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebhookDeadLetters", reflect.TypeOf((*MockDB)(nil).FindWebhookDeadLetters), ctx)
+}
+
+// FinishItemImport mocks base method.
+func (m *MockDB) FinishItemImport(ctx context.Context, i domain.ItemImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishItemImport", ctx, i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishItemImport indicates an expected call of FinishItemImport.
+func (mr *MockDBMockRecorder) FinishItemImport(ctx, i interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishItemImport", reflect.TypeOf((*MockDB)(nil).FinishItemImport), ctx, i)
+}
+
+// FinishJobRun mocks base method.
+func (m *MockDB) FinishJobRun(ctx context.Context, r domain.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishJobRun", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishJobRun indicates an expected call of FinishJobRun.
+func (mr *MockDBMockRecorder) FinishJobRun(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishJobRun", reflect.TypeOf((*MockDB)(nil).FinishJobRun), ctx, r)
 }
 
 // Health mocks base method.
-func (m *MockDB) Health() error {
+func (m *MockDB) Health(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Health")
+	ret := m.ctrl.Call(m, "Health", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Health indicates an expected call of Health.
-func (mr *MockDBMockRecorder) Health() *gomock.Call {
+func (mr *MockDBMockRecorder) Health(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockDB)(nil).Health), ctx)
+}
+
+// InsertBankFile mocks base method.
+func (m *MockDB) InsertBankFile(ctx context.Context, f *domain.BankFile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBankFile", ctx, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertBankFile indicates an expected call of InsertBankFile.
+func (mr *MockDBMockRecorder) InsertBankFile(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBankFile", reflect.TypeOf((*MockDB)(nil).InsertBankFile), ctx, f)
+}
+
+// InsertItemImport mocks base method.
+func (m *MockDB) InsertItemImport(ctx context.Context, i *domain.ItemImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertItemImport", ctx, i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertItemImport indicates an expected call of InsertItemImport.
+func (mr *MockDBMockRecorder) InsertItemImport(ctx, i interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertItemImport", reflect.TypeOf((*MockDB)(nil).InsertItemImport), ctx, i)
+}
+
+// InsertItemImportErrors mocks base method.
+func (m *MockDB) InsertItemImportErrors(ctx context.Context, errs []domain.ItemImportError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertItemImportErrors", ctx, errs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertItemImportErrors indicates an expected call of InsertItemImportErrors.
+func (mr *MockDBMockRecorder) InsertItemImportErrors(ctx, errs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertItemImportErrors", reflect.TypeOf((*MockDB)(nil).InsertItemImportErrors), ctx, errs)
+}
+
+// InsertItems mocks base method.
+func (m *MockDB) InsertItems(ctx context.Context, items []domain.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertItems", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertItems indicates an expected call of InsertItems.
+func (mr *MockDBMockRecorder) InsertItems(ctx, items interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockDB)(nil).Health))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertItems", reflect.TypeOf((*MockDB)(nil).InsertItems), ctx, items)
 }
 
-// Insert mocks base method.
-func (m *MockDB) Insert(dest interface{}) error {
+// InsertJobRun mocks base method.
+func (m *MockDB) InsertJobRun(ctx context.Context, r *domain.JobRun) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Insert", dest)
+	ret := m.ctrl.Call(m, "InsertJobRun", ctx, r)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Insert indicates an expected call of Insert.
-func (mr *MockDBMockRecorder) Insert(dest interface{}) *gomock.Call {
+// InsertJobRun indicates an expected call of InsertJobRun.
+func (mr *MockDBMockRecorder) InsertJobRun(ctx, r interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockDB)(nil).Insert), dest)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertJobRun", reflect.TypeOf((*MockDB)(nil).InsertJobRun), ctx, r)
+}
+
+// InsertOutboxMessage mocks base method.
+func (m_2 *MockDB) InsertOutboxMessage(ctx context.Context, m *domain.OutboxMessage) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "InsertOutboxMessage", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOutboxMessage indicates an expected call of InsertOutboxMessage.
+func (mr *MockDBMockRecorder) InsertOutboxMessage(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOutboxMessage", reflect.TypeOf((*MockDB)(nil).InsertOutboxMessage), ctx, m)
+}
+
+// InsertPayout mocks base method.
+func (m *MockDB) InsertPayout(ctx context.Context, p *domain.Payout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPayout", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPayout indicates an expected call of InsertPayout.
+func (mr *MockDBMockRecorder) InsertPayout(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPayout", reflect.TypeOf((*MockDB)(nil).InsertPayout), ctx, p)
+}
+
+// InsertReconciliation mocks base method.
+func (m *MockDB) InsertReconciliation(ctx context.Context, r *domain.Reconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertReconciliation", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertReconciliation indicates an expected call of InsertReconciliation.
+func (mr *MockDBMockRecorder) InsertReconciliation(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReconciliation", reflect.TypeOf((*MockDB)(nil).InsertReconciliation), ctx, r)
+}
+
+// InsertSeller mocks base method.
+func (m *MockDB) InsertSeller(ctx context.Context, s *domain.Seller) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSeller", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSeller indicates an expected call of InsertSeller.
+func (mr *MockDBMockRecorder) InsertSeller(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSeller", reflect.TypeOf((*MockDB)(nil).InsertSeller), ctx, s)
+}
+
+// InsertWebhookDeadLetter mocks base method.
+func (m *MockDB) InsertWebhookDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDeadLetter", ctx, l)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDeadLetter indicates an expected call of InsertWebhookDeadLetter.
+func (mr *MockDBMockRecorder) InsertWebhookDeadLetter(ctx, l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeadLetter", reflect.TypeOf((*MockDB)(nil).InsertWebhookDeadLetter), ctx, l)
+}
+
+// InsertWebhookDeliveries mocks base method.
+func (m *MockDB) InsertWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDeliveries indicates an expected call of InsertWebhookDeliveries.
+func (mr *MockDBMockRecorder) InsertWebhookDeliveries(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeliveries", reflect.TypeOf((*MockDB)(nil).InsertWebhookDeliveries), ctx, deliveries)
+}
+
+// InsertWebhookSubscription mocks base method.
+func (m *MockDB) InsertWebhookSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookSubscription", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookSubscription indicates an expected call of InsertWebhookSubscription.
+func (mr *MockDBMockRecorder) InsertWebhookSubscription(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookSubscription", reflect.TypeOf((*MockDB)(nil).InsertWebhookSubscription), ctx, s)
+}
+
+// LockUnpaidItems mocks base method.
+func (m *MockDB) LockUnpaidItems(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUnpaidItems", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUnpaidItems indicates an expected call of LockUnpaidItems.
+func (mr *MockDBMockRecorder) LockUnpaidItems(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUnpaidItems", reflect.TypeOf((*MockDB)(nil).LockUnpaidItems), ctx, ids)
+}
+
+// PayOutItems mocks base method.
+func (m *MockDB) PayOutItems(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayOutItems", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PayOutItems indicates an expected call of PayOutItems.
+func (mr *MockDBMockRecorder) PayOutItems(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayOutItems", reflect.TypeOf((*MockDB)(nil).PayOutItems), ctx, ids)
+}
+
+// ReplayWebhookDeadLetter mocks base method.
+func (m *MockDB) ReplayWebhookDeadLetter(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDeadLetter", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayWebhookDeadLetter indicates an expected call of ReplayWebhookDeadLetter.
+func (mr *MockDBMockRecorder) ReplayWebhookDeadLetter(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDeadLetter", reflect.TypeOf((*MockDB)(nil).ReplayWebhookDeadLetter), ctx, id, at)
 }
 
 // Rollback mocks base method.
@@ -222,16 +705,228 @@ func (mr *MockDBMockRecorder) RunMigrations(path interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMigrations", reflect.TypeOf((*MockDB)(nil).RunMigrations), path)
 }
 
-// Update mocks base method.
-func (m *MockDB) Update(dest interface{}) error {
+// SettlePayouts mocks base method.
+func (m *MockDB) SettlePayouts(ctx context.Context, payoutIDs []string, settledAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettlePayouts", ctx, payoutIDs, settledAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SettlePayouts indicates an expected call of SettlePayouts.
+func (mr *MockDBMockRecorder) SettlePayouts(ctx, payoutIDs, settledAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettlePayouts", reflect.TypeOf((*MockDB)(nil).SettlePayouts), ctx, payoutIDs, settledAt)
+}
+
+// SumPayoutsBySellerID mocks base method.
+func (m *MockDB) SumPayoutsBySellerID(ctx context.Context, id string, f db.PayoutsFilter) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumPayoutsBySellerID", ctx, id, f)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumPayoutsBySellerID indicates an expected call of SumPayoutsBySellerID.
+func (mr *MockDBMockRecorder) SumPayoutsBySellerID(ctx, id, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumPayoutsBySellerID", reflect.TypeOf((*MockDB)(nil).SumPayoutsBySellerID), ctx, id, f)
+}
+
+// TryLock mocks base method.
+func (m *MockDB) TryLock(ctx context.Context, name string) (db.Lock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, name)
+	ret0, _ := ret[0].(db.Lock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockDBMockRecorder) TryLock(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockDB)(nil).TryLock), ctx, name)
+}
+
+// UpdateCurrencyRates mocks base method.
+func (m *MockDB) UpdateCurrencyRates(ctx context.Context, currencies []domain.Currency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCurrencyRates", ctx, currencies)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCurrencyRates indicates an expected call of UpdateCurrencyRates.
+func (mr *MockDBMockRecorder) UpdateCurrencyRates(ctx, currencies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCurrencyRates", reflect.TypeOf((*MockDB)(nil).UpdateCurrencyRates), ctx, currencies)
+}
+
+// UpdateOutboxMessage mocks base method.
+func (m_2 *MockDB) UpdateOutboxMessage(ctx context.Context, m domain.OutboxMessage) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "UpdateOutboxMessage", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOutboxMessage indicates an expected call of UpdateOutboxMessage.
+func (mr *MockDBMockRecorder) UpdateOutboxMessage(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOutboxMessage", reflect.TypeOf((*MockDB)(nil).UpdateOutboxMessage), ctx, m)
+}
+
+// UpdatePayoutStatus mocks base method.
+func (m *MockDB) UpdatePayoutStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayoutStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePayoutStatus indicates an expected call of UpdatePayoutStatus.
+func (mr *MockDBMockRecorder) UpdatePayoutStatus(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayoutStatus", reflect.TypeOf((*MockDB)(nil).UpdatePayoutStatus), ctx, id, from, to)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockDB) UpdateWebhookDelivery(ctx context.Context, w domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockDBMockRecorder) UpdateWebhookDelivery(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockDB)(nil).UpdateWebhookDelivery), ctx, w)
+}
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Health mocks base method.
+func (m *MockHealthChecker) Health(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockHealthCheckerMockRecorder) Health(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockHealthChecker)(nil).Health), ctx)
+}
+
+// MockLocker is a mock of Locker interface.
+type MockLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockerMockRecorder
+}
+
+// MockLockerMockRecorder is the mock recorder for MockLocker.
+type MockLockerMockRecorder struct {
+	mock *MockLocker
+}
+
+// NewMockLocker creates a new mock instance.
+func NewMockLocker(ctrl *gomock.Controller) *MockLocker {
+	mock := &MockLocker{ctrl: ctrl}
+	mock.recorder = &MockLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocker) EXPECT() *MockLockerMockRecorder {
+	return m.recorder
+}
+
+// TryLock mocks base method.
+func (m *MockLocker) TryLock(ctx context.Context, name string) (db.Lock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, name)
+	ret0, _ := ret[0].(db.Lock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockLockerMockRecorder) TryLock(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockLocker)(nil).TryLock), ctx, name)
+}
+
+// MockLock is a mock of Lock interface.
+type MockLock struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockMockRecorder
+}
+
+// MockLockMockRecorder is the mock recorder for MockLock.
+type MockLockMockRecorder struct {
+	mock *MockLock
+}
+
+// NewMockLock creates a new mock instance.
+func NewMockLock(ctrl *gomock.Controller) *MockLock {
+	mock := &MockLock{ctrl: ctrl}
+	mock.recorder = &MockLockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLock) EXPECT() *MockLockMockRecorder {
+	return m.recorder
+}
+
+// Lost mocks base method.
+func (m *MockLock) Lost() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lost")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Lost indicates an expected call of Lost.
+func (mr *MockLockMockRecorder) Lost() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lost", reflect.TypeOf((*MockLock)(nil).Lost))
+}
+
+// Release mocks base method.
+func (m *MockLock) Release() error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", dest)
+	ret := m.ctrl.Call(m, "Release")
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockDBMockRecorder) Update(dest interface{}) *gomock.Call {
+// Release indicates an expected call of Release.
+func (mr *MockLockMockRecorder) Release() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDB)(nil).Update), dest)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLock)(nil).Release))
 }
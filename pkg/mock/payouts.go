@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: payout.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	payouts "github.com/TestardR/seller-payout/internal/payouts"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCreator is a mock of Creator interface.
+type MockCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreatorMockRecorder
+}
+
+// MockCreatorMockRecorder is the mock recorder for MockCreator.
+type MockCreatorMockRecorder struct {
+	mock *MockCreator
+}
+
+// NewMockCreator creates a new mock instance.
+func NewMockCreator(ctrl *gomock.Controller) *MockCreator {
+	mock := &MockCreator{ctrl: ctrl}
+	mock.recorder = &MockCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreator) EXPECT() *MockCreatorMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCreator) Create(ctx context.Context, r payouts.Request) (payouts.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(payouts.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCreatorMockRecorder) Create(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCreator)(nil).Create), ctx, r)
+}
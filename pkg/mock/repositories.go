@@ -0,0 +1,1031 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repositories.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/TestardR/seller-payout/internal/domain"
+	db "github.com/TestardR/seller-payout/pkg/db"
+	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
+)
+
+// MockSellerRepository is a mock of SellerRepository interface.
+type MockSellerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSellerRepositoryMockRecorder
+}
+
+// MockSellerRepositoryMockRecorder is the mock recorder for MockSellerRepository.
+type MockSellerRepositoryMockRecorder struct {
+	mock *MockSellerRepository
+}
+
+// NewMockSellerRepository creates a new mock instance.
+func NewMockSellerRepository(ctrl *gomock.Controller) *MockSellerRepository {
+	mock := &MockSellerRepository{ctrl: ctrl}
+	mock.recorder = &MockSellerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSellerRepository) EXPECT() *MockSellerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSellerRepository) Create(ctx context.Context, s *domain.Seller) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSellerRepositoryMockRecorder) Create(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSellerRepository)(nil).Create), ctx, s)
+}
+
+// FindWithUnpaidOutItems mocks base method.
+func (m *MockSellerRepository) FindWithUnpaidOutItems(ctx context.Context, f db.SellersFilter) (db.SellersPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWithUnpaidOutItems", ctx, f)
+	ret0, _ := ret[0].(db.SellersPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWithUnpaidOutItems indicates an expected call of FindWithUnpaidOutItems.
+func (mr *MockSellerRepositoryMockRecorder) FindWithUnpaidOutItems(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWithUnpaidOutItems", reflect.TypeOf((*MockSellerRepository)(nil).FindWithUnpaidOutItems), ctx, f)
+}
+
+// Get mocks base method.
+func (m *MockSellerRepository) Get(ctx context.Context, id string) (domain.Seller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Seller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSellerRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSellerRepository)(nil).Get), ctx, id)
+}
+
+// MockItemRepository is a mock of ItemRepository interface.
+type MockItemRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockItemRepositoryMockRecorder
+}
+
+// MockItemRepositoryMockRecorder is the mock recorder for MockItemRepository.
+type MockItemRepositoryMockRecorder struct {
+	mock *MockItemRepository
+}
+
+// NewMockItemRepository creates a new mock instance.
+func NewMockItemRepository(ctrl *gomock.Controller) *MockItemRepository {
+	mock := &MockItemRepository{ctrl: ctrl}
+	mock.recorder = &MockItemRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItemRepository) EXPECT() *MockItemRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockItemRepository) Create(ctx context.Context, items []domain.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockItemRepositoryMockRecorder) Create(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockItemRepository)(nil).Create), ctx, items)
+}
+
+// Find mocks base method.
+func (m *MockItemRepository) Find(ctx context.Context, f db.ItemsFilter) (db.ItemsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, f)
+	ret0, _ := ret[0].(db.ItemsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockItemRepositoryMockRecorder) Find(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockItemRepository)(nil).Find), ctx, f)
+}
+
+// FindUnpaidOutPage mocks base method.
+func (m *MockItemRepository) FindUnpaidOutPage(ctx context.Context, sellerID string, after *db.Cursor, limit int) (db.ItemsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnpaidOutPage", ctx, sellerID, after, limit)
+	ret0, _ := ret[0].(db.ItemsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnpaidOutPage indicates an expected call of FindUnpaidOutPage.
+func (mr *MockItemRepositoryMockRecorder) FindUnpaidOutPage(ctx, sellerID, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaidOutPage", reflect.TypeOf((*MockItemRepository)(nil).FindUnpaidOutPage), ctx, sellerID, after, limit)
+}
+
+// Get mocks base method.
+func (m *MockItemRepository) Get(ctx context.Context, id string) (domain.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockItemRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockItemRepository)(nil).Get), ctx, id)
+}
+
+// Lock mocks base method.
+func (m *MockItemRepository) Lock(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockItemRepositoryMockRecorder) Lock(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockItemRepository)(nil).Lock), ctx, ids)
+}
+
+// PayOut mocks base method.
+func (m *MockItemRepository) PayOut(ctx context.Context, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayOut", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PayOut indicates an expected call of PayOut.
+func (mr *MockItemRepositoryMockRecorder) PayOut(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayOut", reflect.TypeOf((*MockItemRepository)(nil).PayOut), ctx, ids)
+}
+
+// MockPayoutRepository is a mock of PayoutRepository interface.
+type MockPayoutRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPayoutRepositoryMockRecorder
+}
+
+// MockPayoutRepositoryMockRecorder is the mock recorder for MockPayoutRepository.
+type MockPayoutRepositoryMockRecorder struct {
+	mock *MockPayoutRepository
+}
+
+// NewMockPayoutRepository creates a new mock instance.
+func NewMockPayoutRepository(ctrl *gomock.Controller) *MockPayoutRepository {
+	mock := &MockPayoutRepository{ctrl: ctrl}
+	mock.recorder = &MockPayoutRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayoutRepository) EXPECT() *MockPayoutRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPayoutRepository) Create(ctx context.Context, p *domain.Payout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPayoutRepositoryMockRecorder) Create(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPayoutRepository)(nil).Create), ctx, p)
+}
+
+// Export mocks base method.
+func (m *MockPayoutRepository) Export(ctx context.Context, bankFileID string, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, bankFileID, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockPayoutRepositoryMockRecorder) Export(ctx, bankFileID, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockPayoutRepository)(nil).Export), ctx, bankFileID, ids)
+}
+
+// FindBySeller mocks base method.
+func (m *MockPayoutRepository) FindBySeller(ctx context.Context, sellerID string, f db.PayoutsFilter) (db.PayoutsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySeller", ctx, sellerID, f)
+	ret0, _ := ret[0].(db.PayoutsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySeller indicates an expected call of FindBySeller.
+func (mr *MockPayoutRepositoryMockRecorder) FindBySeller(ctx, sellerID, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySeller", reflect.TypeOf((*MockPayoutRepository)(nil).FindBySeller), ctx, sellerID, f)
+}
+
+// FindByStatus mocks base method.
+func (m *MockPayoutRepository) FindByStatus(ctx context.Context, status domain.PayoutStatus) ([]domain.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByStatus", ctx, status)
+	ret0, _ := ret[0].([]domain.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByStatus indicates an expected call of FindByStatus.
+func (mr *MockPayoutRepositoryMockRecorder) FindByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByStatus", reflect.TypeOf((*MockPayoutRepository)(nil).FindByStatus), ctx, status)
+}
+
+// Get mocks base method.
+func (m *MockPayoutRepository) Get(ctx context.Context, id string) (domain.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPayoutRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPayoutRepository)(nil).Get), ctx, id)
+}
+
+// Settle mocks base method.
+func (m *MockPayoutRepository) Settle(ctx context.Context, ids []string, settledAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Settle", ctx, ids, settledAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Settle indicates an expected call of Settle.
+func (mr *MockPayoutRepositoryMockRecorder) Settle(ctx, ids, settledAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Settle", reflect.TypeOf((*MockPayoutRepository)(nil).Settle), ctx, ids, settledAt)
+}
+
+// SumBySeller mocks base method.
+func (m *MockPayoutRepository) SumBySeller(ctx context.Context, sellerID string, f db.PayoutsFilter) (decimal.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumBySeller", ctx, sellerID, f)
+	ret0, _ := ret[0].(decimal.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumBySeller indicates an expected call of SumBySeller.
+func (mr *MockPayoutRepositoryMockRecorder) SumBySeller(ctx, sellerID, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumBySeller", reflect.TypeOf((*MockPayoutRepository)(nil).SumBySeller), ctx, sellerID, f)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockPayoutRepository) UpdateStatus(ctx context.Context, id string, from, to domain.PayoutStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockPayoutRepositoryMockRecorder) UpdateStatus(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockPayoutRepository)(nil).UpdateStatus), ctx, id, from, to)
+}
+
+// MockCurrencyRepository is a mock of CurrencyRepository interface.
+type MockCurrencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyRepositoryMockRecorder
+}
+
+// MockCurrencyRepositoryMockRecorder is the mock recorder for MockCurrencyRepository.
+type MockCurrencyRepositoryMockRecorder struct {
+	mock *MockCurrencyRepository
+}
+
+// NewMockCurrencyRepository creates a new mock instance.
+func NewMockCurrencyRepository(ctrl *gomock.Controller) *MockCurrencyRepository {
+	mock := &MockCurrencyRepository{ctrl: ctrl}
+	mock.recorder = &MockCurrencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyRepository) EXPECT() *MockCurrencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// All mocks base method.
+func (m *MockCurrencyRepository) All(ctx context.Context) ([]domain.Currency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "All", ctx)
+	ret0, _ := ret[0].([]domain.Currency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// All indicates an expected call of All.
+func (mr *MockCurrencyRepositoryMockRecorder) All(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "All", reflect.TypeOf((*MockCurrencyRepository)(nil).All), ctx)
+}
+
+// UpdateRates mocks base method.
+func (m *MockCurrencyRepository) UpdateRates(ctx context.Context, currencies []domain.Currency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRates", ctx, currencies)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRates indicates an expected call of UpdateRates.
+func (mr *MockCurrencyRepositoryMockRecorder) UpdateRates(ctx, currencies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRates", reflect.TypeOf((*MockCurrencyRepository)(nil).UpdateRates), ctx, currencies)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m_2 *MockOutboxRepository) Add(ctx context.Context, m *domain.OutboxMessage) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Add", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockOutboxRepositoryMockRecorder) Add(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOutboxRepository)(nil).Add), ctx, m)
+}
+
+// MockJobRunRepository is a mock of JobRunRepository interface.
+type MockJobRunRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRunRepositoryMockRecorder
+}
+
+// MockJobRunRepositoryMockRecorder is the mock recorder for MockJobRunRepository.
+type MockJobRunRepositoryMockRecorder struct {
+	mock *MockJobRunRepository
+}
+
+// NewMockJobRunRepository creates a new mock instance.
+func NewMockJobRunRepository(ctrl *gomock.Controller) *MockJobRunRepository {
+	mock := &MockJobRunRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRunRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRunRepository) EXPECT() *MockJobRunRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockJobRunRepository) Find(ctx context.Context, f db.JobRunsFilter) (db.JobRunsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, f)
+	ret0, _ := ret[0].(db.JobRunsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockJobRunRepositoryMockRecorder) Find(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockJobRunRepository)(nil).Find), ctx, f)
+}
+
+// Finish mocks base method.
+func (m *MockJobRunRepository) Finish(ctx context.Context, r domain.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockJobRunRepositoryMockRecorder) Finish(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockJobRunRepository)(nil).Finish), ctx, r)
+}
+
+// Get mocks base method.
+func (m *MockJobRunRepository) Get(ctx context.Context, id string) (domain.JobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.JobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockJobRunRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockJobRunRepository)(nil).Get), ctx, id)
+}
+
+// Start mocks base method.
+func (m *MockJobRunRepository) Start(ctx context.Context, r *domain.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockJobRunRepositoryMockRecorder) Start(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockJobRunRepository)(nil).Start), ctx, r)
+}
+
+// MockBankFileRepository is a mock of BankFileRepository interface.
+type MockBankFileRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBankFileRepositoryMockRecorder
+}
+
+// MockBankFileRepositoryMockRecorder is the mock recorder for MockBankFileRepository.
+type MockBankFileRepositoryMockRecorder struct {
+	mock *MockBankFileRepository
+}
+
+// NewMockBankFileRepository creates a new mock instance.
+func NewMockBankFileRepository(ctrl *gomock.Controller) *MockBankFileRepository {
+	mock := &MockBankFileRepository{ctrl: ctrl}
+	mock.recorder = &MockBankFileRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBankFileRepository) EXPECT() *MockBankFileRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBankFileRepository) Create(ctx context.Context, f *domain.BankFile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, f)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBankFileRepositoryMockRecorder) Create(ctx, f interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBankFileRepository)(nil).Create), ctx, f)
+}
+
+// Get mocks base method.
+func (m *MockBankFileRepository) Get(ctx context.Context, id string) (domain.BankFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.BankFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBankFileRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBankFileRepository)(nil).Get), ctx, id)
+}
+
+// MockReconciliationRepository is a mock of ReconciliationRepository interface.
+type MockReconciliationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationRepositoryMockRecorder
+}
+
+// MockReconciliationRepositoryMockRecorder is the mock recorder for MockReconciliationRepository.
+type MockReconciliationRepositoryMockRecorder struct {
+	mock *MockReconciliationRepository
+}
+
+// NewMockReconciliationRepository creates a new mock instance.
+func NewMockReconciliationRepository(ctrl *gomock.Controller) *MockReconciliationRepository {
+	mock := &MockReconciliationRepository{ctrl: ctrl}
+	mock.recorder = &MockReconciliationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationRepository) EXPECT() *MockReconciliationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReconciliationRepository) Create(ctx context.Context, r *domain.Reconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReconciliationRepositoryMockRecorder) Create(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReconciliationRepository)(nil).Create), ctx, r)
+}
+
+// Get mocks base method.
+func (m *MockReconciliationRepository) Get(ctx context.Context, id string) (domain.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReconciliationRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReconciliationRepository)(nil).Get), ctx, id)
+}
+
+// MockItemImportRepository is a mock of ItemImportRepository interface.
+type MockItemImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockItemImportRepositoryMockRecorder
+}
+
+// MockItemImportRepositoryMockRecorder is the mock recorder for MockItemImportRepository.
+type MockItemImportRepositoryMockRecorder struct {
+	mock *MockItemImportRepository
+}
+
+// NewMockItemImportRepository creates a new mock instance.
+func NewMockItemImportRepository(ctrl *gomock.Controller) *MockItemImportRepository {
+	mock := &MockItemImportRepository{ctrl: ctrl}
+	mock.recorder = &MockItemImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItemImportRepository) EXPECT() *MockItemImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockItemImportRepository) Create(ctx context.Context, i *domain.ItemImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockItemImportRepositoryMockRecorder) Create(ctx, i interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockItemImportRepository)(nil).Create), ctx, i)
+}
+
+// FindRejected mocks base method.
+func (m *MockItemImportRepository) FindRejected(ctx context.Context, importID string, afterRow, limit int) ([]domain.ItemImportError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRejected", ctx, importID, afterRow, limit)
+	ret0, _ := ret[0].([]domain.ItemImportError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRejected indicates an expected call of FindRejected.
+func (mr *MockItemImportRepositoryMockRecorder) FindRejected(ctx, importID, afterRow, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRejected", reflect.TypeOf((*MockItemImportRepository)(nil).FindRejected), ctx, importID, afterRow, limit)
+}
+
+// Finish mocks base method.
+func (m *MockItemImportRepository) Finish(ctx context.Context, i domain.ItemImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, i)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockItemImportRepositoryMockRecorder) Finish(ctx, i interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockItemImportRepository)(nil).Finish), ctx, i)
+}
+
+// Get mocks base method.
+func (m *MockItemImportRepository) Get(ctx context.Context, id string) (domain.ItemImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(domain.ItemImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockItemImportRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockItemImportRepository)(nil).Get), ctx, id)
+}
+
+// Reject mocks base method.
+func (m *MockItemImportRepository) Reject(ctx context.Context, rows []domain.ItemImportError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockItemImportRepositoryMockRecorder) Reject(ctx, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockItemImportRepository)(nil).Reject), ctx, rows)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddDeadLetter mocks base method.
+func (m *MockWebhookRepository) AddDeadLetter(ctx context.Context, l *domain.WebhookDeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeadLetter", ctx, l)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeadLetter indicates an expected call of AddDeadLetter.
+func (mr *MockWebhookRepositoryMockRecorder) AddDeadLetter(ctx, l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeadLetter", reflect.TypeOf((*MockWebhookRepository)(nil).AddDeadLetter), ctx, l)
+}
+
+// Enqueue mocks base method.
+func (m *MockWebhookRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockWebhookRepositoryMockRecorder) Enqueue(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockWebhookRepository)(nil).Enqueue), ctx, deliveries)
+}
+
+// FindActiveSubscriptions mocks base method.
+func (m *MockWebhookRepository) FindActiveSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveSubscriptions", ctx)
+	ret0, _ := ret[0].([]domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveSubscriptions indicates an expected call of FindActiveSubscriptions.
+func (mr *MockWebhookRepositoryMockRecorder) FindActiveSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveSubscriptions", reflect.TypeOf((*MockWebhookRepository)(nil).FindActiveSubscriptions), ctx)
+}
+
+// FindDeadLetters mocks base method.
+func (m *MockWebhookRepository) FindDeadLetters(ctx context.Context) ([]domain.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeadLetters", ctx)
+	ret0, _ := ret[0].([]domain.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeadLetters indicates an expected call of FindDeadLetters.
+func (mr *MockWebhookRepositoryMockRecorder) FindDeadLetters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeadLetters", reflect.TypeOf((*MockWebhookRepository)(nil).FindDeadLetters), ctx)
+}
+
+// FindDueDeliveries mocks base method.
+func (m *MockWebhookRepository) FindDueDeliveries(ctx context.Context, at time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueDeliveries", ctx, at, limit)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueDeliveries indicates an expected call of FindDueDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) FindDueDeliveries(ctx, at, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).FindDueDeliveries), ctx, at, limit)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockWebhookRepository) GetDeadLetter(ctx context.Context, id string) (domain.WebhookDeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, id)
+	ret0, _ := ret[0].(domain.WebhookDeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeadLetter(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeadLetter), ctx, id)
+}
+
+// ReplayDeadLetter mocks base method.
+func (m *MockWebhookRepository) ReplayDeadLetter(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockWebhookRepositoryMockRecorder) ReplayDeadLetter(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockWebhookRepository)(nil).ReplayDeadLetter), ctx, id, at)
+}
+
+// Subscribe mocks base method.
+func (m *MockWebhookRepository) Subscribe(ctx context.Context, s *domain.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockWebhookRepositoryMockRecorder) Subscribe(ctx, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockWebhookRepository)(nil).Subscribe), ctx, s)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, w domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateDelivery(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateDelivery), ctx, w)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// BankFiles mocks base method.
+func (m *MockUnitOfWork) BankFiles() db.BankFileRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BankFiles")
+	ret0, _ := ret[0].(db.BankFileRepository)
+	return ret0
+}
+
+// BankFiles indicates an expected call of BankFiles.
+func (mr *MockUnitOfWorkMockRecorder) BankFiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BankFiles", reflect.TypeOf((*MockUnitOfWork)(nil).BankFiles))
+}
+
+// Currencies mocks base method.
+func (m *MockUnitOfWork) Currencies() db.CurrencyRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Currencies")
+	ret0, _ := ret[0].(db.CurrencyRepository)
+	return ret0
+}
+
+// Currencies indicates an expected call of Currencies.
+func (mr *MockUnitOfWorkMockRecorder) Currencies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Currencies", reflect.TypeOf((*MockUnitOfWork)(nil).Currencies))
+}
+
+// ItemImports mocks base method.
+func (m *MockUnitOfWork) ItemImports() db.ItemImportRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ItemImports")
+	ret0, _ := ret[0].(db.ItemImportRepository)
+	return ret0
+}
+
+// ItemImports indicates an expected call of ItemImports.
+func (mr *MockUnitOfWorkMockRecorder) ItemImports() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ItemImports", reflect.TypeOf((*MockUnitOfWork)(nil).ItemImports))
+}
+
+// Items mocks base method.
+func (m *MockUnitOfWork) Items() db.ItemRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Items")
+	ret0, _ := ret[0].(db.ItemRepository)
+	return ret0
+}
+
+// Items indicates an expected call of Items.
+func (mr *MockUnitOfWorkMockRecorder) Items() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Items", reflect.TypeOf((*MockUnitOfWork)(nil).Items))
+}
+
+// JobRuns mocks base method.
+func (m *MockUnitOfWork) JobRuns() db.JobRunRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobRuns")
+	ret0, _ := ret[0].(db.JobRunRepository)
+	return ret0
+}
+
+// JobRuns indicates an expected call of JobRuns.
+func (mr *MockUnitOfWorkMockRecorder) JobRuns() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobRuns", reflect.TypeOf((*MockUnitOfWork)(nil).JobRuns))
+}
+
+// Outbox mocks base method.
+func (m *MockUnitOfWork) Outbox() db.OutboxRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Outbox")
+	ret0, _ := ret[0].(db.OutboxRepository)
+	return ret0
+}
+
+// Outbox indicates an expected call of Outbox.
+func (mr *MockUnitOfWorkMockRecorder) Outbox() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Outbox", reflect.TypeOf((*MockUnitOfWork)(nil).Outbox))
+}
+
+// Payouts mocks base method.
+func (m *MockUnitOfWork) Payouts() db.PayoutRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Payouts")
+	ret0, _ := ret[0].(db.PayoutRepository)
+	return ret0
+}
+
+// Payouts indicates an expected call of Payouts.
+func (mr *MockUnitOfWorkMockRecorder) Payouts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Payouts", reflect.TypeOf((*MockUnitOfWork)(nil).Payouts))
+}
+
+// Reconciliations mocks base method.
+func (m *MockUnitOfWork) Reconciliations() db.ReconciliationRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconciliations")
+	ret0, _ := ret[0].(db.ReconciliationRepository)
+	return ret0
+}
+
+// Reconciliations indicates an expected call of Reconciliations.
+func (mr *MockUnitOfWorkMockRecorder) Reconciliations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconciliations", reflect.TypeOf((*MockUnitOfWork)(nil).Reconciliations))
+}
+
+// Sellers mocks base method.
+func (m *MockUnitOfWork) Sellers() db.SellerRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sellers")
+	ret0, _ := ret[0].(db.SellerRepository)
+	return ret0
+}
+
+// Sellers indicates an expected call of Sellers.
+func (mr *MockUnitOfWorkMockRecorder) Sellers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sellers", reflect.TypeOf((*MockUnitOfWork)(nil).Sellers))
+}
+
+// Transaction mocks base method.
+func (m *MockUnitOfWork) Transaction(ctx context.Context, fn func(db.UnitOfWork) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockUnitOfWorkMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockUnitOfWork)(nil).Transaction), ctx, fn)
+}
+
+// Webhooks mocks base method.
+func (m *MockUnitOfWork) Webhooks() db.WebhookRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Webhooks")
+	ret0, _ := ret[0].(db.WebhookRepository)
+	return ret0
+}
+
+// Webhooks indicates an expected call of Webhooks.
+func (mr *MockUnitOfWorkMockRecorder) Webhooks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Webhooks", reflect.TypeOf((*MockUnitOfWork)(nil).Webhooks))
+}
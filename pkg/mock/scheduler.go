@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: state.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	domain "github.com/TestardR/seller-payout/internal/domain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMonitor is a mock of Monitor interface.
+type MockMonitor struct {
+	ctrl     *gomock.Controller
+	recorder *MockMonitorMockRecorder
+}
+
+// MockMonitorMockRecorder is the mock recorder for MockMonitor.
+type MockMonitorMockRecorder struct {
+	mock *MockMonitor
+}
+
+// NewMockMonitor creates a new mock instance.
+func NewMockMonitor(ctrl *gomock.Controller) *MockMonitor {
+	mock := &MockMonitor{ctrl: ctrl}
+	mock.recorder = &MockMonitorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMonitor) EXPECT() *MockMonitorMockRecorder {
+	return m.recorder
+}
+
+// States mocks base method.
+func (m *MockMonitor) States() []domain.JobState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "States")
+	ret0, _ := ret[0].([]domain.JobState)
+	return ret0
+}
+
+// States indicates an expected call of States.
+func (mr *MockMonitorMockRecorder) States() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "States", reflect.TypeOf((*MockMonitor)(nil).States))
+}